@@ -0,0 +1,313 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"goup/internal/app"
+	"goup/internal/config"
+	"goup/internal/dependency"
+)
+
+// command is a goup subcommand, e.g. 'goup list', with its own flags and help
+type command struct {
+	name     string
+	module   string // Required module argument shown in the usage, empty if none
+	summary  string
+	flags    []flagGroup
+	apply    func(cfg *config.Config, module string) // Sets the mode of the command
+	examples []string
+}
+
+// flagValues holds the flags that do not map to a configuration field
+type flagValues struct {
+	patch, minor, major bool
+	configPath          string
+}
+
+// flagGroup registers a group of related flags on the flag set of a command
+type flagGroup func(fs *flag.FlagSet, cfg *config.Config, values *flagValues)
+
+// commands are routed by their name, the first argument of goup
+var commands = []command{
+	{
+		name:    "update",
+		summary: "Update the dependencies (the default without a command)",
+		flags:   []flagGroup{outputFlags, selectionFlags, recursiveFlags, reviewFlags, updateFlags, interactiveFlag},
+		apply:   func(cfg *config.Config, module string) {},
+		examples: []string{
+			"update --patch            # Only apply patch updates",
+			"update --all --dry-run    # Preview go.mod and go.sum after the updates",
+			"update --verify           # Revert updates that break go build/go test",
+		},
+	},
+	{
+		name:    "list",
+		summary: "List the dependencies with available updates without changing anything",
+		flags:   []flagGroup{outputFlags, selectionFlags, recursiveFlags, reviewFlags, failOnUpdatesFlag},
+		apply:   func(cfg *config.Config, module string) { cfg.List = true },
+		examples: []string{
+			"list --format=json        # Print updatable dependencies as JSON",
+			"list --api-diff           # Flag updates that break the exported API",
+			"list --replaced           # Check the forks replacing modules for new versions",
+		},
+	},
+	{
+		name:    "check",
+		summary: "List the available updates and exit with code 2 if there are any, for CI",
+		flags:   []flagGroup{outputFlags, selectionFlags, recursiveFlags},
+		apply: func(cfg *config.Config, module string) {
+			cfg.List = true
+			cfg.FailOnUpdates = true
+		},
+		examples: []string{
+			"check --all --only-retracted  # Fail CI on retracted versions",
+			"check --security --vuln-db=./osv  # Fail CI on known vulnerabilities",
+		},
+	},
+	{
+		name:    "select",
+		summary: "Choose which dependencies to update",
+		flags:   []flagGroup{outputFlags, selectionFlags, recursiveFlags, reviewFlags, updateFlags},
+		apply:   func(cfg *config.Config, module string) { cfg.Selective = true },
+		examples: []string{
+			"select --changelog        # Review the changelog of each update before choosing",
+			"select --impact           # Review the most used dependencies first",
+		},
+	},
+	{
+		name:    "diff",
+		module:  "<module>[@version]",
+		summary: "Show the exported API changes of a module update, or of the given version",
+		flags:   []flagGroup{outputFlags, selectionFlags},
+		apply:   func(cfg *config.Config, module string) { cfg.DiffModule = module },
+		examples: []string{
+			"diff github.com/foo/bar   # Show the exported API changes of an update",
+		},
+	},
+	{
+		name:    "why",
+		module:  "<module>",
+		summary: "Show the requirement chain of a module and the updates raising its version",
+		flags:   []flagGroup{outputFlags, selectionFlags},
+		apply:   func(cfg *config.Config, module string) { cfg.WhyModule = module },
+		examples: []string{
+			"why golang.org/x/net      # Show which dependencies require an indirect module",
+		},
+	},
+	{
+		name:    "exclude",
+		module:  "<module>@<version>",
+		summary: "Add an exclude directive for a module version to go.mod",
+		flags:   []flagGroup{outputFlags, reasonFlag},
+		apply:   func(cfg *config.Config, module string) { cfg.ExcludeModule = module },
+		examples: []string{
+			"exclude --reason=\"Panics on startup\" example.com/lib@v1.2.0  # Never select a known-bad version",
+		},
+	},
+	{
+		name:    "drop-replaces",
+		summary: "Drop the replace directives of forks whose upstream has caught up",
+		flags:   []flagGroup{outputFlags, selectionFlags, recursiveFlags, updateFlags, interactiveFlag, listFlag},
+		apply:   func(cfg *config.Config, module string) { cfg.DropReplaces = true },
+		examples: []string{
+			"drop-replaces --all       # Drop the replace directives of forks released upstream",
+		},
+	},
+	{
+		name:    "rollback",
+		summary: "Restore go.mod and go.sum to their state before the last update",
+		flags:   []flagGroup{outputFlags, recursiveFlags},
+		apply:   func(cfg *config.Config, module string) { cfg.Rollback = true },
+		examples: []string{
+			"rollback                  # Undo the last update run",
+		},
+	},
+}
+
+// flagForm accepts every option without a command, e.g. 'goup --list --all',
+// as goup did before it had commands
+var flagForm = command{
+	flags: []flagGroup{
+		outputFlags, selectionFlags, recursiveFlags, reviewFlags, updateFlags,
+		interactiveFlag, listFlag, selectFlag, failOnUpdatesFlag, rollbackFlag, reasonFlag,
+	},
+	apply: func(cfg *config.Config, module string) {},
+}
+
+// findCommand returns the command with the given name, or nil if there is none
+func findCommand(name string) *command {
+	for i := range commands {
+		if commands[i].name == name {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+// outputFlags are accepted by every command
+func outputFlags(fs *flag.FlagSet, cfg *config.Config, values *flagValues) {
+	fs.BoolVar(&cfg.Verbose, "verbose", false, "Show detailed output")
+	fs.BoolVar(&cfg.NoColor, "no-color", false, "Disable colored output")
+	fs.StringVar(&cfg.Format, "format", config.FormatText, "Output format: text or json")
+	fs.IntVar(&cfg.Jobs, "jobs", dependency.DefaultJobs, "Number of module versions to query concurrently")
+	fs.DurationVar(&cfg.Timeout, "timeout", 0, "Stop the run after this long, e.g. 10m (default: no limit)")
+	fs.DurationVar(&cfg.CommandTimeout, "command-timeout", 0, "Stop any go command or module query that takes longer, e.g. 2m (default: no limit)")
+	fs.StringVar(&values.configPath, "config", "", "Path to a configuration file (default: .goup.yaml or .goup.toml in the project directory)")
+}
+
+// selectionFlags choose the dependencies and versions to consider
+func selectionFlags(fs *flag.FlagSet, cfg *config.Config, values *flagValues) {
+	fs.BoolVar(&cfg.All, "all", false, "Update indirect dependencies as well")
+	fs.BoolVar(&values.patch, "patch", false, "Only update to newer patch versions (same major.minor)")
+	fs.BoolVar(&values.minor, "minor", false, "Only update to newer minor or patch versions (same major)")
+	fs.BoolVar(&values.major, "major", false, "Update to the newest version, including new major version module paths (implies --discover-majors)")
+	fs.BoolVar(&cfg.DiscoverMajors, "discover-majors", false, "Offer new major version module paths (/v2, /v3...) and rewrite imports")
+	fs.BoolVar(&cfg.Security, "security", false, "Only update modules with known vulnerabilities, to the minimal fixed version")
+	fs.StringVar(&cfg.VulnDB, "vuln-db", "", "Local OSV vulnerability database (directory or JSON file) used by --security")
+	fs.BoolVar(&cfg.OnlyRetracted, "only-retracted", false, "Only update modules whose current version is retracted by their author")
+	fs.BoolVar(&cfg.Replaced, "replaced", false, "Include modules replaced in go.mod, updating the replace directive of forks")
+}
+
+// recursiveFlags run a command on every module of a monorepo
+func recursiveFlags(fs *flag.FlagSet, cfg *config.Config, values *flagValues) {
+	fs.BoolVar(&cfg.Recursive, "recursive", false, "Update every module found below the directory (skips vendor and testdata)")
+	fs.BoolVar(&cfg.SyncVersions, "sync-versions", false, "With --recursive, update a dependency to the same version in every module")
+}
+
+// reviewFlags add information about each update
+func reviewFlags(fs *flag.FlagSet, cfg *config.Config, values *flagValues) {
+	fs.BoolVar(&cfg.Changelog, "changelog", false, "Show what changed in the changelog of each module (CHANGELOG.md, release notes) before updating")
+	fs.BoolVar(&cfg.APIDiff, "api-diff", false, "Compare the exported API of each update and flag the ones removing or changing identifiers")
+	fs.BoolVar(&cfg.Impact, "impact", false, "Show which packages of the module import each dependency and sort the selection by impact")
+}
+
+// updateFlags control how the updates are applied
+func updateFlags(fs *flag.FlagSet, cfg *config.Config, values *flagValues) {
+	fs.BoolVar(&cfg.Transitive, "transitive", false, "Also upgrade the dependencies of updated modules (go get -u)")
+	fs.BoolVar(&cfg.Batch, "batch", false, "Apply all updates with a single go get, updating one at a time only if it fails")
+	fs.BoolVar(&cfg.DryRun, "dry-run", false, "Show the go.mod and go.sum diff the updates would produce without changing any file")
+	fs.BoolVar(&cfg.KeepPartial, "keep-partial", false, "Keep successful updates when others fail instead of rolling back")
+	fs.BoolVar(&cfg.Verify, "verify", false, "Run a check after each update and revert the updates that break it")
+	fs.StringVar(&cfg.VerifyCommand, "verify-cmd", "", "Check used by --verify (default \""+config.DefaultVerifyCommand+"\")")
+}
+
+func interactiveFlag(fs *flag.FlagSet, cfg *config.Config, values *flagValues) {
+	fs.BoolVar(&cfg.Interactive, "interactive", false, "Ask for confirmation before updating")
+}
+
+func listFlag(fs *flag.FlagSet, cfg *config.Config, values *flagValues) {
+	fs.BoolVar(&cfg.List, "list", false, "List all upgradeable dependencies")
+}
+
+func selectFlag(fs *flag.FlagSet, cfg *config.Config, values *flagValues) {
+	fs.BoolVar(&cfg.Selective, "select", false, "Interactively select which dependencies to update (ignored with --list)")
+}
+
+func failOnUpdatesFlag(fs *flag.FlagSet, cfg *config.Config, values *flagValues) {
+	fs.BoolVar(&cfg.FailOnUpdates, "fail-on-updates", false, "Exit with code 2 when updates are available")
+}
+
+func rollbackFlag(fs *flag.FlagSet, cfg *config.Config, values *flagValues) {
+	fs.BoolVar(&cfg.Rollback, "rollback", false, "Restore go.mod and go.sum to their state before the last update")
+}
+
+func reasonFlag(fs *flag.FlagSet, cfg *config.Config, values *flagValues) {
+	fs.StringVar(&cfg.ExcludeReason, "reason", "", "Comment of the exclude directive added by 'exclude'")
+}
+
+// newFlagSet registers the flags of a command and its help
+func newFlagSet(prog string, cmd *command, cfg *config.Config, values *flagValues) *flag.FlagSet {
+	name := prog
+	if cmd.name != "" {
+		name = prog + " " + cmd.name
+	}
+
+	// Create a new FlagSet to avoid global state issues in tests. Errors are
+	// returned rather than exiting with code 2, which means updates are available.
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	for _, register := range cmd.flags {
+		register(fs, cfg, values)
+	}
+
+	if cmd.name == "" {
+		fs.Usage = func() { printUsage(prog, fs) }
+	} else {
+		fs.Usage = func() { printCommandUsage(prog, cmd, fs) }
+	}
+	return fs
+}
+
+// printUsage prints the commands and the options of the flag form
+func printUsage(prog string, fs *flag.FlagSet) {
+	fmt.Fprintf(os.Stderr, "Usage: %s <command> [options] [arguments] [directory]\n", prog)
+	fmt.Fprintf(os.Stderr, "       %s [options] [directory]\n\n", prog)
+	fmt.Fprintf(os.Stderr, "goup - Go dependency updater\n\n")
+	fmt.Fprintf(os.Stderr, "Commands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-14s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun '%s help <command>' for the options of a command. Without a command,\n", prog)
+	fmt.Fprintf(os.Stderr, "goup updates the dependencies and accepts every option below.\n\n")
+	fmt.Fprintf(os.Stderr, "Arguments:\n")
+	fmt.Fprintf(os.Stderr, "  directory    Path to Go project directory (default: current directory)\n\n")
+	fmt.Fprintf(os.Stderr, "Options:\n")
+	fs.PrintDefaults()
+	fmt.Fprintf(os.Stderr, "\nExamples:\n")
+	fmt.Fprintf(os.Stderr, "  %s                       		# Update direct dependencies in current directory\n", prog)
+	fmt.Fprintf(os.Stderr, "  %s /path/to/project      		# Update direct dependencies in specified directory\n", prog)
+	fmt.Fprintf(os.Stderr, "  %s --all /path/to/project     # Update all dependencies in specified directory\n", prog)
+	fmt.Fprintf(os.Stderr, "  %s list                  		# List the available updates\n", prog)
+	fmt.Fprintf(os.Stderr, "  %s select --all          		# Choose which dependencies to update\n", prog)
+	fmt.Fprintf(os.Stderr, "  %s check                 		# Fail CI when updates are available\n", prog)
+	fmt.Fprintf(os.Stderr, "  %s --list --format=json  		# The flag form of 'list --format=json'\n", prog)
+	fmt.Fprintf(os.Stderr, "  %s --config=ci.goup.yaml 		# Use a specific configuration file\n", prog)
+	fmt.Fprintf(os.Stderr, "  %s --recursive --sync-versions	# Update every module of a monorepo in lockstep\n", prog)
+	fmt.Fprintf(os.Stderr, "  %s --timeout=10m --command-timeout=2m	# Give up instead of hanging on a slow proxy\n", prog)
+	printExitCodes()
+}
+
+// printCommandUsage prints the arguments, options and examples of a command
+func printCommandUsage(prog string, cmd *command, fs *flag.FlagSet) {
+	args := "[directory]"
+	if cmd.module != "" {
+		args = cmd.module + " [directory]"
+	}
+	fmt.Fprintf(os.Stderr, "Usage: %s %s [options] %s\n\n", prog, cmd.name, args)
+	fmt.Fprintf(os.Stderr, "%s\n\n", cmd.summary)
+	fmt.Fprintf(os.Stderr, "Options:\n")
+	fs.PrintDefaults()
+	fmt.Fprintf(os.Stderr, "\nExamples:\n")
+	for _, example := range cmd.examples {
+		fmt.Fprintf(os.Stderr, "  %s %s\n", prog, example)
+	}
+	if cmd.name == "check" {
+		printExitCodes()
+	}
+}
+
+func printExitCodes() {
+	fmt.Fprintf(os.Stderr, "\nExit codes:\n")
+	fmt.Fprintf(os.Stderr, "  %d  Success\n", app.ExitOK)
+	fmt.Fprintf(os.Stderr, "  %d  goup failed to run\n", app.ExitError)
+	fmt.Fprintf(os.Stderr, "  %d  Updates are available (check, or list --fail-on-updates)\n", app.ExitUpdatesAvailable)
+	fmt.Fprintf(os.Stderr, "  %d  Some dependencies failed to update\n", app.ExitPartialFailure)
+	fmt.Fprintf(os.Stderr, "  %d  All dependencies failed to update\n", app.ExitTotalFailure)
+	fmt.Fprintf(os.Stderr, "  %d  Interrupted with Ctrl-C\n", app.ExitInterrupted)
+}
+
+// printHelp prints the help of the command named in the arguments of
+// 'goup help', or the general usage without one, and exits
+func printHelp(prog string, args []string) {
+	cmd := &flagForm
+	if len(args) > 0 {
+		if cmd = findCommand(args[0]); cmd == nil {
+			fmt.Fprintf(os.Stderr, "Error: unknown command %q, run '%s help' for the list of commands\n", args[0], prog)
+			os.Exit(app.ExitError)
+		}
+	}
+
+	newFlagSet(prog, cmd, &config.Config{}, &flagValues{}).Usage()
+	os.Exit(app.ExitOK)
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"goup/internal/config"
+	"goup/internal/dependency"
+)
+
+func TestParseCommands(t *testing.T) {
+	t.Run("parse list command", func(t *testing.T) {
+		config, targetDir := parseFlagsWithArgs([]string{"goup", "list", "--all", "--format=json", "/some/path"})
+
+		assert.True(t, config.List)
+		assert.True(t, config.All)
+		assert.True(t, config.IsJSON())
+		assert.False(t, config.FailOnUpdates)
+		assert.Equal(t, "/some/path", targetDir)
+	})
+
+	t.Run("parse check command", func(t *testing.T) {
+		config, targetDir := parseFlagsWithArgs([]string{"goup", "check", "--only-retracted"})
+
+		assert.True(t, config.List)
+		assert.True(t, config.FailOnUpdates)
+		assert.True(t, config.OnlyRetracted)
+		assert.Empty(t, targetDir)
+	})
+
+	t.Run("parse select command", func(t *testing.T) {
+		config, targetDir := parseFlagsWithArgs([]string{"goup", "select", "--changelog", "--patch", "/some/path"})
+
+		assert.True(t, config.Selective)
+		assert.True(t, config.IsSelective())
+		assert.True(t, config.Changelog)
+		assert.False(t, config.List)
+		assert.Equal(t, dependency.PolicyPatch, config.Policy)
+		assert.Equal(t, "/some/path", targetDir)
+	})
+
+	t.Run("parse update command", func(t *testing.T) {
+		config, targetDir := parseFlagsWithArgs([]string{"goup", "update", "--interactive", "--verify-cmd", "make test"})
+
+		assert.True(t, config.Interactive)
+		assert.True(t, config.Verify)
+		assert.Equal(t, "make test", config.VerifyCommand)
+		assert.False(t, config.List)
+		assert.False(t, config.Selective)
+		assert.Empty(t, targetDir)
+	})
+
+	t.Run("parse rollback command", func(t *testing.T) {
+		config, targetDir := parseFlagsWithArgs([]string{"goup", "rollback", "--recursive", "/some/path"})
+
+		assert.True(t, config.Rollback)
+		assert.True(t, config.Recursive)
+		assert.Equal(t, "/some/path", targetDir)
+	})
+
+	t.Run("parse drop-replaces list", func(t *testing.T) {
+		config, _ := parseFlagsWithArgs([]string{"goup", "drop-replaces", "--list", "--all"})
+
+		assert.True(t, config.DropReplaces)
+		assert.True(t, config.List)
+		assert.True(t, config.All)
+	})
+
+	t.Run("flag form keeps working", func(t *testing.T) {
+		config, targetDir := parseFlagsWithArgs([]string{"goup", "--list", "--select", "--fail-on-updates", "/some/path"})
+
+		assert.True(t, config.List)
+		assert.True(t, config.Selective)
+		assert.True(t, config.FailOnUpdates)
+		// Listing never prompts
+		assert.False(t, config.IsSelective())
+		assert.False(t, config.IsInteractiveMode())
+		assert.Equal(t, "/some/path", targetDir)
+	})
+
+	t.Run("directory named like no command", func(t *testing.T) {
+		config, targetDir := parseFlagsWithArgs([]string{"goup", "./list"})
+
+		assert.False(t, config.List)
+		assert.Equal(t, "./list", targetDir)
+	})
+}
+
+func TestCommandFlags(t *testing.T) {
+	tests := []struct {
+		command string
+		accepts []string
+		rejects []string
+	}{
+		{
+			command: "update",
+			accepts: []string{"interactive", "dry-run", "verify", "changelog", "patch", "recursive"},
+			rejects: []string{"list", "select", "fail-on-updates", "rollback", "reason"},
+		},
+		{
+			command: "list",
+			accepts: []string{"all", "format", "fail-on-updates", "api-diff", "recursive"},
+			rejects: []string{"select", "interactive", "dry-run", "verify", "rollback"},
+		},
+		{
+			command: "check",
+			accepts: []string{"all", "security", "vuln-db", "recursive"},
+			rejects: []string{"select", "interactive", "fail-on-updates", "changelog", "dry-run"},
+		},
+		{
+			command: "select",
+			accepts: []string{"all", "changelog", "impact", "dry-run", "verify"},
+			rejects: []string{"list", "interactive", "rollback"},
+		},
+		{
+			command: "diff",
+			accepts: []string{"verbose", "format", "patch"},
+			rejects: []string{"recursive", "rollback", "dry-run"},
+		},
+		{
+			command: "exclude",
+			accepts: []string{"reason", "verbose"},
+			rejects: []string{"recursive", "dry-run", "all"},
+		},
+		{
+			command: "rollback",
+			accepts: []string{"verbose", "recursive"},
+			rejects: []string{"list", "select", "dry-run"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			cmd := findCommand(tt.command)
+			require.NotNil(t, cmd)
+
+			fs := newFlagSet("goup", cmd, &config.Config{}, &flagValues{})
+			for _, name := range tt.accepts {
+				assert.NotNil(t, fs.Lookup(name), "--%s", name)
+			}
+			for _, name := range tt.rejects {
+				assert.Nil(t, fs.Lookup(name), "--%s", name)
+			}
+		})
+	}
+}
+
+func TestFlagFormAcceptsEveryFlag(t *testing.T) {
+	form := newFlagSet("goup", &flagForm, &config.Config{}, &flagValues{})
+
+	for _, cmd := range commands {
+		newFlagSet("goup", &cmd, &config.Config{}, &flagValues{}).VisitAll(func(f *flag.Flag) {
+			assert.NotNil(t, form.Lookup(f.Name), "--%s of %s", f.Name, cmd.name)
+		})
+	}
+}
+
+func TestFlagSetReturnsErrors(t *testing.T) {
+	// Exiting with the flag package's code 2 would read as "updates are available"
+	fs := newFlagSet("goup", findCommand("check"), &config.Config{}, &flagValues{})
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {}
+
+	assert.ErrorContains(t, fs.Parse([]string{"--fail-on-updates"}), "flag provided but not defined")
+	assert.ErrorIs(t, fs.Parse([]string{"--help"}), flag.ErrHelp)
+}
+
+func TestFindCommand(t *testing.T) {
+	assert.Equal(t, "check", findCommand("check").name)
+	assert.Nil(t, findCommand("--list"))
+	assert.Nil(t, findCommand("/path/to/project"))
+}
@@ -1,14 +1,24 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"sync"
 
+	"goup/internal/apidiff"
 	"goup/internal/app"
+	"goup/internal/changelog"
 	"goup/internal/config"
 	"goup/internal/dependency"
+	"goup/internal/impact"
+	"goup/internal/modgraph"
+	"goup/internal/modzip"
+	"goup/internal/proxy"
 	"goup/internal/selector"
 	"goup/internal/ui"
 	"goup/internal/updater"
@@ -19,75 +29,369 @@ func main() {
 
 	// Change to target directory if specified
 	if targetDir != "" {
-		if err := changeToDirectory(targetDir); err != nil {
+		enter := changeToDirectory
+		if cfg.Recursive {
+			// A monorepo root does not need to be a module itself
+			enter = enterDirectory
+		}
+		if err := enter(targetDir); err != nil {
 			fmt.Printf("Error: %v\n", err)
 			os.Exit(1)
 		}
 	}
 
 	// Initialize dependencies using dependency injection
-	console := ui.NewConsole(cfg)
-	depManager := dependency.NewManager()
-	depSelector := selector.NewInteractiveSelector(console)
-	depUpdater := updater.NewGoUpdater()
+	console := newConsole(cfg)
+	depSelector := selector.NewTerminalSelector(console)
 
 	// Create and run the application
-	application := app.New(cfg, console, depManager, depSelector, depUpdater)
+	var application interface {
+		Run(ctx context.Context) error
+	}
+	if cfg.Recursive {
+		modules, err := newModules(cfg, console, depSelector)
+		if err != nil {
+			console.Error("%v", err)
+			os.Exit(app.ExitError)
+		}
+		application = app.NewRecursive(cfg, console, modules)
+	} else {
+		depManager, depUpdater, err := newManagerAndUpdater(cfg, console)
+		if err != nil {
+			console.Error("%v", err)
+			os.Exit(app.ExitError)
+		}
+		application = app.NewWithSources(cfg, console, depManager, depSelector, depUpdater, newSources(cfg, console)("."))
+	}
+
+	ctx, stop := runContext(cfg, console)
+	err := application.Run(ctx)
+	stop()
+
+	if err != nil {
+		code := app.ExitCode(err)
+		if code == app.ExitError {
+			console.Error("Application failed: %v", err)
+		}
+		os.Exit(code)
+	}
+}
+
+// runContext returns the context of the run. It expires after --timeout and
+// is cancelled by the first Ctrl-C, which lets the current update finish; a
+// second Ctrl-C terminates goup immediately, with the go commands it runs.
+func runContext(cfg *config.Config, console ui.Console) (context.Context, context.CancelFunc) {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if cfg.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), cfg.Timeout)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+
+	signals := make(chan os.Signal, 1)
+	stopped := make(chan struct{})
+	signal.Notify(signals, os.Interrupt)
+	go func() {
+		select {
+		case <-signals:
+			console.Warning("Interrupted, stopping after the current step (press Ctrl-C again to quit now)")
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+
+		select {
+		case <-signals:
+			// The go commands run in their own process group, out of reach of
+			// the interrupt sent by the terminal
+			updater.KillCommands()
+			os.Exit(app.ExitInterrupted)
+		case <-stopped:
+		}
+	}()
+
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(stopped)
+			cancel()
+		})
+	}
+}
+
+func newConsole(cfg *config.Config) ui.Console {
+	if cfg.IsJSON() {
+		return ui.NewJSONConsole(cfg)
+	}
+	return ui.NewConsole(cfg)
+}
+
+// newManagerAndUpdater works on every module of the go.work file in the
+// current directory, or on the go.mod module otherwise
+func newManagerAndUpdater(cfg *config.Config, console ui.Console) (dependency.Manager, updater.Updater, error) {
+	lookup, err := newLookup(cfg, console)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	workPath := dependency.FindWorkspace(".")
+	if workPath == "" {
+		return dependency.NewManagerWithLookup("go.mod", lookup), updater.NewGoUpdater(cfg), nil
+	}
 
-	if err := application.Run(); err != nil {
-		console.Error("Application failed: %v", err)
-		os.Exit(1)
+	workspace, err := dependency.LoadWorkspace(workPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	console.Debug("Using workspace %s with %d modules", workPath, len(workspace.Modules))
+	return dependency.NewWorkspaceManagerWithLookup(workspace, lookup), updater.NewWorkspaceUpdater(cfg, workspace), nil
+}
+
+// newLookup queries module versions concurrently through the module proxy
+// configured for the go command, showing the progress of the queries
+func newLookup(cfg *config.Config, console ui.Console) (*dependency.Lookup, error) {
+	client, err := proxy.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	progress := func(done, total int, path string) {
+		console.ProgressBar(done, total, path)
+	}
+	return dependency.NewLookup(client, cfg.GetJobs(), cfg.CommandTimeout, progress), nil
+}
+
+// newSources returns the sources of the module, or workspace, in a
+// directory. Changelogs and APIs are read from the module cache, downloading
+// the module zips it lacks through the module proxy, and the packages and
+// requirement graph of the directory are analyzed. The sources are only set
+// up for the features that need them.
+func newSources(cfg *config.Config, console ui.Console) func(dir string) app.Sources {
+	readZips := cfg.Changelog || cfg.APIDiff || cfg.DiffModule != ""
+	// Indirect dependencies are explained with the requirement graph
+	readGraph := cfg.ShouldIncludeIndirect() || cfg.WhyModule != ""
+
+	var client *proxy.Client
+	var modules *modzip.Cache
+	if readZips || readGraph {
+		var err error
+		client, err = proxy.NewClient()
+		if err != nil {
+			console.Debug("Cannot download modules: %v", err)
+		}
+	}
+	if client != nil && readZips {
+		cacheDir, err := modzip.Dir()
+		if err != nil {
+			// Every module zip is downloaded instead
+			console.Debug("Cannot locate the module cache: %v", err)
+		}
+		modules = modzip.NewCache(client, cacheDir)
+	}
+
+	return func(dir string) app.Sources {
+		var sources app.Sources
+		if cfg.Impact {
+			sources.Impacts = impact.NewAnalyzer(dir)
+		}
+		if client != nil && readGraph {
+			sources.Requires = modgraph.NewLoader(dir, client)
+		}
+		if modules != nil {
+			sources.Changelogs = changelog.NewFetcher(modules)
+			sources.APIs = apidiff.NewDiffer(modules)
+		}
+		return sources
 	}
 }
 
+// newModules creates an application for every module below the current
+// directory. Each module is updated on its own, so go.work files are ignored.
+func newModules(cfg *config.Config, console ui.Console, sel selector.Selector) ([]app.Module, error) {
+	dirs, err := dependency.FindModules(".")
+	if err != nil {
+		return nil, fmt.Errorf("searching for modules: %w", err)
+	}
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("no go.mod file found below the current directory")
+	}
+
+	if err := os.Setenv("GOWORK", "off"); err != nil {
+		return nil, err
+	}
+
+	lookup, err := newLookup(cfg, console)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := newSources(cfg, console)
+
+	console.Debug("Found %d modules", len(dirs))
+	modules := make([]app.Module, 0, len(dirs))
+	for _, dir := range dirs {
+		depManager := dependency.NewManagerWithLookup(filepath.Join(dir, "go.mod"), lookup)
+		modules = append(modules, app.Module{
+			Dir: dir,
+			App: app.NewWithSources(cfg, console, depManager, sel, updater.NewModuleUpdater(cfg, dir), sources(dir)),
+		})
+	}
+
+	return modules, nil
+}
+
 func parseFlags() (*config.Config, string) {
 	return parseFlagsWithArgs(os.Args)
 }
 
+// parseFlagsWithArgs routes the arguments to the command they name, e.g.
+// 'goup list --all', or parses them as the flag form, e.g. 'goup --list --all'
 func parseFlagsWithArgs(args []string) (*config.Config, string) {
+	if len(args) > 1 {
+		if args[1] == "help" {
+			printHelp(args[0], args[2:])
+		}
+		if cmd := findCommand(args[1]); cmd != nil {
+			return parseCommand(args[0], cmd, args[2:])
+		}
+	}
+	return parseCommand(args[0], &flagForm, args[1:])
+}
+
+// parseCommand parses the options and arguments of a command, exiting with
+// its usage when they are invalid. -h and --help exit successfully.
+func parseCommand(prog string, cmd *command, args []string) (*config.Config, string) {
 	cfg := &config.Config{}
+	values := &flagValues{}
+	fs := newFlagSet(prog, cmd, cfg, values)
+
+	// The flag set has already printed the error and the usage
+	err := fs.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(app.ExitOK)
+	}
+	if err != nil {
+		os.Exit(app.ExitError)
+	}
 
-	// Create a new FlagSet to avoid global state issues in tests
-	fs := flag.NewFlagSet(args[0], flag.ExitOnError)
-
-	fs.BoolVar(&cfg.List, "list", false, "List all upgradeable dependencies")
-	fs.BoolVar(&cfg.Interactive, "interactive", false, "Ask for confirmation before updating")
-	fs.BoolVar(&cfg.Verbose, "verbose", false, "Show detailed output")
-	fs.BoolVar(&cfg.NoColor, "no-color", false, "Disable colored output")
-	fs.BoolVar(&cfg.All, "all", false, "Update indirect dependencies as well")
-	fs.BoolVar(&cfg.Selective, "select", false, "Interactively select which dependencies to update")
-
-	fs.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Usage: %s [options] [directory]\n\n", args[0])
-		fmt.Fprintf(os.Stderr, "goup - Go dependency updater\n\n")
-		fmt.Fprintf(os.Stderr, "Arguments:\n")
-		fmt.Fprintf(os.Stderr, "  directory    Path to Go project directory (default: current directory)\n\n")
-		fmt.Fprintf(os.Stderr, "Options:\n")
-		fs.PrintDefaults()
-		fmt.Fprintf(os.Stderr, "\nExamples:\n")
-		fmt.Fprintf(os.Stderr, "  %s                       		# Update direct dependencies in current directory\n", args[0])
-		fmt.Fprintf(os.Stderr, "  %s /path/to/project      		# Update direct dependencies in specified directory\n", args[0])
-		fmt.Fprintf(os.Stderr, "  %s /path/to/project --all     # Update direct dependencies in specified directory\n", args[0])
-		fmt.Fprintf(os.Stderr, "  %s --select              		# Interactively select dependencies to update\n", args[0])
-	}
-
-	// Parse the arguments (skip the program name)
-	err := fs.Parse(args[1:])
+	cfg.Policy, err = policyFromFlags(values.patch, values.minor, values.major)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
-		os.Exit(1)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(app.ExitError)
+	}
+
+	positional := fs.Args()
+	var module string
+	if cmd.module != "" {
+		if len(positional) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: %s %s requires a module path\n", prog, cmd.name)
+			os.Exit(app.ExitError)
+		}
+		module, positional = positional[0], positional[1:]
 	}
+	cmd.apply(cfg, module)
 
 	// Get target directory from command line arguments
 	var targetDir string
-	if fs.NArg() > 0 {
-		targetDir = fs.Arg(0)
+	if len(positional) > 0 {
+		targetDir = positional[0]
+	}
+
+	// Flags given on the command line take precedence over the config file
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+
+	if err := applyConfigFile(cfg, values.configPath, targetDir, explicit); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(app.ExitError)
+	}
+
+	// A custom check is only useful in verify mode
+	if explicit["verify-cmd"] {
+		cfg.Verify = true
+	}
+
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(app.ExitError)
 	}
 
 	return cfg, targetDir
 }
 
+// applyConfigFile loads the configuration file given with --config, or the
+// one found in the project directory, into cfg
+func applyConfigFile(cfg *config.Config, configPath, targetDir string, explicit map[string]bool) error {
+	if configPath == "" {
+		dir := targetDir
+		if dir == "" {
+			dir = "."
+		}
+		configPath = config.FindFile(dir)
+		if configPath == "" {
+			return nil
+		}
+	}
+
+	file, err := config.LoadFile(configPath)
+	if err != nil {
+		return err
+	}
+
+	file.Apply(cfg, explicit)
+	return nil
+}
+
+func policyFromFlags(patch, minor, major bool) (dependency.Policy, error) {
+	policy := dependency.PolicyLatest
+	count := 0
+
+	for _, flag := range []struct {
+		set    bool
+		policy dependency.Policy
+	}{
+		{patch, dependency.PolicyPatch},
+		{minor, dependency.PolicyMinor},
+		{major, dependency.PolicyMajor},
+	} {
+		if flag.set {
+			policy = flag.policy
+			count++
+		}
+	}
+
+	if count > 1 {
+		return dependency.PolicyLatest, fmt.Errorf("only one of --patch, --minor or --major can be used")
+	}
+
+	return policy, nil
+}
+
+// changeToDirectory enters the project directory, which must contain a
+// go.mod or go.work file
 func changeToDirectory(targetDir string) error {
+	if err := enterDirectory(targetDir); err != nil {
+		return err
+	}
+
+	// Verify go.mod or go.work exists in the target directory
+	if _, err := os.Stat("go.mod"); os.IsNotExist(err) {
+		if dependency.FindWorkspace(".") == "" {
+			absPath, _ := os.Getwd()
+			return fmt.Errorf("no go.mod file found in directory '%s' - not a Go module", absPath)
+		}
+	}
+
+	return nil
+}
+
+// enterDirectory changes the working directory to targetDir
+func enterDirectory(targetDir string) error {
 	// Convert to absolute path
 	absPath, err := filepath.Abs(targetDir)
 	if err != nil {
@@ -116,11 +420,7 @@ func changeToDirectory(targetDir string) error {
 		return fmt.Errorf("failed to change to directory '%s': %w", absPath, err)
 	}
 
-	// Verify go.mod exists in the target directory
-	if _, err := os.Stat("go.mod"); os.IsNotExist(err) {
-		return fmt.Errorf("no go.mod file found in directory '%s' - not a Go module", absPath)
-	}
-
-	fmt.Printf("Working in directory: %s\n", absPath)
+	// Keep stdout clean for machine-readable output
+	fmt.Fprintf(os.Stderr, "Working in directory: %s\n", absPath)
 	return nil
 }
@@ -1,12 +1,18 @@
 package main
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"goup/internal/config"
+	"goup/internal/dependency"
+	"goup/internal/ui"
 )
 
 func TestChangeToDirectory(t *testing.T) {
@@ -64,6 +70,22 @@ func TestChangeToDirectory(t *testing.T) {
 		assert.Contains(t, err.Error(), "not a Go module")
 	})
 
+	t.Run("accept workspace directory without go.mod", func(t *testing.T) {
+		t.Setenv("GOWORK", "")
+		tempDir := t.TempDir()
+
+		err := os.WriteFile(filepath.Join(tempDir, "go.work"), []byte("go 1.21\n\nuse ./api\n"), 0644)
+		require.NoError(t, err)
+
+		assert.NoError(t, changeToDirectory(tempDir))
+	})
+
+	t.Run("recursive mode accepts directory without go.mod", func(t *testing.T) {
+		tempDir := t.TempDir()
+
+		assert.NoError(t, enterDirectory(tempDir))
+	})
+
 	t.Run("handle relative paths", func(t *testing.T) {
 		// Create temporary directory with go.mod
 		tempDir := t.TempDir()
@@ -131,6 +153,14 @@ func TestParseFlags(t *testing.T) {
 			"--no-color",
 			"--all",
 			"--select",
+			"--transitive",
+			"--fail-on-updates",
+			"--discover-majors",
+			"--keep-partial",
+			"--batch",
+			"--changelog",
+			"--api-diff",
+			"--impact",
 		}
 
 		config, targetDir := parseFlagsWithArgs(args)
@@ -142,6 +172,108 @@ func TestParseFlags(t *testing.T) {
 		assert.True(t, config.NoColor)
 		assert.True(t, config.All)
 		assert.True(t, config.Selective)
+		assert.True(t, config.Transitive)
+		assert.True(t, config.FailOnUpdates)
+		assert.True(t, config.DiscoverMajors)
+		assert.True(t, config.KeepPartial)
+		assert.True(t, config.Batch)
+		assert.True(t, config.Changelog)
+		assert.True(t, config.APIDiff)
+		assert.True(t, config.Impact)
+	})
+
+	t.Run("verify command implies verify", func(t *testing.T) {
+		config, _ := parseFlagsWithArgs([]string{"goup", "--verify-cmd", "make test"})
+
+		assert.True(t, config.Verify)
+		assert.Equal(t, "make test", config.VerifyCommand)
+	})
+
+	t.Run("parse rollback flag", func(t *testing.T) {
+		config, _ := parseFlagsWithArgs([]string{"goup", "--rollback"})
+
+		assert.True(t, config.Rollback)
+	})
+
+	t.Run("parse only retracted flag", func(t *testing.T) {
+		config, _ := parseFlagsWithArgs([]string{"goup", "--list", "--only-retracted"})
+
+		assert.True(t, config.OnlyRetracted)
+		assert.True(t, config.List)
+	})
+
+	t.Run("parse dry run flag", func(t *testing.T) {
+		config, _ := parseFlagsWithArgs([]string{"goup", "--dry-run", "--all"})
+
+		assert.True(t, config.DryRun)
+		assert.True(t, config.All)
+	})
+
+	t.Run("parse diff command", func(t *testing.T) {
+		config, targetDir := parseFlagsWithArgs([]string{"goup", "diff", "--verbose", "example.com/lib@v1.2.0", "/some/path"})
+
+		assert.Equal(t, "example.com/lib@v1.2.0", config.DiffModule)
+		assert.True(t, config.Verbose)
+		assert.Equal(t, "/some/path", targetDir)
+	})
+
+	t.Run("parse why command", func(t *testing.T) {
+		config, targetDir := parseFlagsWithArgs([]string{"goup", "why", "golang.org/x/net"})
+
+		assert.Equal(t, "golang.org/x/net", config.WhyModule)
+		assert.Empty(t, config.DiffModule)
+		assert.Empty(t, targetDir)
+	})
+
+	t.Run("parse exclude command", func(t *testing.T) {
+		config, targetDir := parseFlagsWithArgs([]string{"goup", "exclude", "--reason", "Panics on startup", "example.com/lib@v1.2.0", "/some/path"})
+
+		assert.Equal(t, "example.com/lib@v1.2.0", config.ExcludeModule)
+		assert.Equal(t, "Panics on startup", config.ExcludeReason)
+		assert.Empty(t, config.WhyModule)
+		assert.Equal(t, "/some/path", targetDir)
+	})
+
+	t.Run("parse replaced flag", func(t *testing.T) {
+		config, _ := parseFlagsWithArgs([]string{"goup", "--list", "--replaced"})
+
+		assert.True(t, config.Replaced)
+		assert.False(t, config.DropReplaces)
+	})
+
+	t.Run("parse drop-replaces command", func(t *testing.T) {
+		config, targetDir := parseFlagsWithArgs([]string{"goup", "drop-replaces", "--dry-run", "/some/path"})
+
+		assert.True(t, config.DropReplaces)
+		assert.True(t, config.DryRun)
+		assert.Equal(t, "/some/path", targetDir)
+	})
+
+	t.Run("parse security flags", func(t *testing.T) {
+		config, _ := parseFlagsWithArgs([]string{"goup", "--security", "--vuln-db", "/var/lib/osv"})
+
+		assert.True(t, config.Security)
+		assert.Equal(t, "/var/lib/osv", config.VulnDB)
+	})
+
+	t.Run("parse jobs flag", func(t *testing.T) {
+		config, _ := parseFlagsWithArgs([]string{"goup", "--jobs", "16"})
+
+		assert.Equal(t, 16, config.GetJobs())
+	})
+
+	t.Run("parse timeout flags", func(t *testing.T) {
+		config, _ := parseFlagsWithArgs([]string{"goup", "--timeout", "10m", "--command-timeout=90s"})
+
+		assert.Equal(t, 10*time.Minute, config.Timeout)
+		assert.Equal(t, 90*time.Second, config.CommandTimeout)
+	})
+
+	t.Run("parse recursive flags", func(t *testing.T) {
+		config, _ := parseFlagsWithArgs([]string{"goup", "--recursive", "--sync-versions"})
+
+		assert.True(t, config.Recursive)
+		assert.True(t, config.SyncVersions)
 	})
 
 	t.Run("flags after directory are ignored", func(t *testing.T) {
@@ -168,6 +300,25 @@ func TestParseFlags(t *testing.T) {
 		assert.True(t, config.Verbose)
 	})
 
+	t.Run("parse output format", func(t *testing.T) {
+		args := []string{"goup", "--list", "--format=json"}
+
+		config, targetDir := parseFlagsWithArgs(args)
+
+		assert.Empty(t, targetDir)
+		assert.True(t, config.List)
+		assert.Equal(t, "json", config.Format)
+		assert.True(t, config.IsJSON())
+	})
+
+	t.Run("parse policy flag", func(t *testing.T) {
+		args := []string{"goup", "--minor"}
+
+		config, _ := parseFlagsWithArgs(args)
+
+		assert.Equal(t, dependency.PolicyMinor, config.Policy)
+	})
+
 	t.Run("only program name", func(t *testing.T) {
 		args := []string{"goup"}
 
@@ -181,5 +332,126 @@ func TestParseFlags(t *testing.T) {
 		assert.False(t, config.NoColor)
 		assert.False(t, config.All)
 		assert.False(t, config.Selective)
+		assert.False(t, config.Transitive)
+		assert.Equal(t, "text", config.Format)
+		assert.False(t, config.FailOnUpdates)
+		assert.Equal(t, dependency.PolicyLatest, config.Policy)
+	})
+}
+
+func TestPolicyFromFlags(t *testing.T) {
+	tests := []struct {
+		name                string
+		patch, minor, major bool
+		expected            dependency.Policy
+		wantErr             bool
+	}{
+		{name: "no policy", expected: dependency.PolicyLatest},
+		{name: "patch", patch: true, expected: dependency.PolicyPatch},
+		{name: "minor", minor: true, expected: dependency.PolicyMinor},
+		{name: "major", major: true, expected: dependency.PolicyMajor},
+		{name: "conflicting policies", patch: true, major: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := policyFromFlags(tt.patch, tt.minor, tt.major)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, policy)
+		})
+	}
+}
+
+func TestParseFlagsWithConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	content := "all: true\npolicy: patch\nrules:\n  - module: github.com/aws/*\n    ignore: true\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".goup.yaml"), []byte(content), 0644))
+
+	t.Run("file found in project directory", func(t *testing.T) {
+		config, _ := parseFlagsWithArgs([]string{"goup", dir})
+
+		assert.True(t, config.All)
+		assert.Equal(t, dependency.PolicyPatch, config.Policy)
+		require.Len(t, config.Rules, 1)
+		assert.True(t, config.Rules[0].Ignore)
+	})
+
+	t.Run("flags override the file", func(t *testing.T) {
+		config, _ := parseFlagsWithArgs([]string{"goup", "--all=false", "--major", dir})
+
+		assert.False(t, config.All)
+		assert.Equal(t, dependency.PolicyMajor, config.Policy)
+	})
+
+	t.Run("explicit config path", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "ci.toml")
+		require.NoError(t, os.WriteFile(path, []byte("verbose = true\n"), 0644))
+
+		config, _ := parseFlagsWithArgs([]string{"goup", "--config", path, dir})
+
+		assert.True(t, config.Verbose)
+		assert.False(t, config.All, "The project file is not read when --config is given")
+	})
+}
+
+func TestNewModules(t *testing.T) {
+	originalDir, err := os.Getwd()
+	require.NoError(t, err)
+	defer os.Chdir(originalDir)
+	t.Setenv("GOWORK", "")
+
+	root := t.TempDir()
+	for _, dir := range []string{"api", "worker", "vendor/example.com/lib"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, dir, "go.mod"), []byte("module example.com/"+filepath.Base(dir)+"\n"), 0644))
+	}
+	require.NoError(t, os.Chdir(root))
+
+	cfg := &config.Config{Recursive: true}
+	modules, err := newModules(cfg, ui.NewConsole(cfg), nil)
+	require.NoError(t, err)
+
+	require.Len(t, modules, 2)
+	assert.Equal(t, "api", modules[0].Dir)
+	assert.Equal(t, "worker", modules[1].Dir)
+	assert.Equal(t, "off", os.Getenv("GOWORK"), "Modules are updated on their own")
+}
+
+func TestNewModulesWithoutModules(t *testing.T) {
+	originalDir, err := os.Getwd()
+	require.NoError(t, err)
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(t.TempDir()))
+
+	cfg := &config.Config{Recursive: true}
+	_, err = newModules(cfg, ui.NewConsole(cfg), nil)
+
+	assert.ErrorContains(t, err, "no go.mod file found")
+}
+
+func TestRunContext(t *testing.T) {
+	t.Run("without timeout", func(t *testing.T) {
+		cfg := &config.Config{}
+		ctx, stop := runContext(cfg, ui.NewConsole(cfg))
+
+		_, hasDeadline := ctx.Deadline()
+		assert.False(t, hasDeadline)
+		assert.NoError(t, ctx.Err())
+
+		stop()
+		assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	})
+
+	t.Run("with timeout", func(t *testing.T) {
+		cfg := &config.Config{Timeout: time.Millisecond}
+		ctx, stop := runContext(cfg, ui.NewConsole(cfg))
+		defer stop()
+
+		<-ctx.Done()
+		assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
 	})
 }
@@ -0,0 +1,264 @@
+// Package apidiff compares the exported API of two versions of a module.
+// Declarations are read from the Go files in the module zips without type
+// checking, so the dependencies of the module do not need to be downloaded.
+// Files are selected with the build constraints of the current platform.
+//
+// go/parser is enough because both versions are compared as written: every
+// exported identifier whose declaration changes is reported, which is what a
+// caller needs to review. Without go/types, a change made in a type of
+// another module that a declaration refers to is not seen, so the report is
+// a review aid rather than a proof of compatibility.
+package apidiff
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"goup/internal/dependency"
+	"goup/internal/modzip"
+)
+
+// Kind is the kind of an API change
+type Kind string
+
+const (
+	Added   Kind = "added"
+	Removed Kind = "removed"
+	Changed Kind = "changed"
+)
+
+// Change is an exported identifier, or a whole package, that differs
+// between two versions of a module
+type Change struct {
+	Package string // Import path of the package
+	Name    string // Identifier, "Type.Member" for fields and methods, empty for a whole package
+	Kind    Kind
+	Old     string // Declaration in the current version, empty if added
+	New     string // Declaration in the new version, empty if removed
+
+	// Interface is set for the methods and embedded interfaces of an
+	// interface, which every implementation of it must provide
+	Interface bool
+}
+
+// Breaking returns true if code using the current version may no longer
+// compile with the new one. Adding a method to an interface breaks the types
+// implementing it.
+func (c Change) Breaking() bool {
+	return c.Kind != Added || c.Interface
+}
+
+// Identifier returns the package qualified name of the change
+func (c Change) Identifier() string {
+	if c.Name == "" {
+		return c.Package
+	}
+	return c.Package + "." + c.Name
+}
+
+// Breaking returns the qualified identifiers of the breaking changes
+func Breaking(changes []Change) []string {
+	var breaking []string
+	for _, change := range changes {
+		if change.Breaking() {
+			breaking = append(breaking, change.Identifier())
+		}
+	}
+	return breaking
+}
+
+// API is the exported API of a module, by package directory relative to the
+// module root ("" for the root package)
+type API map[string]*Package
+
+// Package is the exported API of one package
+type Package struct {
+	Name  string            // Package name
+	Decls map[string]string // Declaration of each exported identifier, by name
+}
+
+// Differ compares the APIs of module versions read from module zips
+type Differ struct {
+	modules *modzip.Cache
+}
+
+// NewDiffer creates a differ reading the module zips from modules
+func NewDiffer(modules *modzip.Cache) *Differ {
+	return &Differ{modules: modules}
+}
+
+// Changes returns the API changes between the current and the new version of
+// dep. For major upgrades the packages are matched by their directory.
+func (d *Differ) Changes(ctx context.Context, dep dependency.Dependency) ([]Change, error) {
+	oldAPI, err := d.load(ctx, dep.Path, dep.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	newAPI, err := d.load(ctx, dep.TargetPath(), dep.NewVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return Compare(dep.TargetPath(), oldAPI, newAPI), nil
+}
+
+func (d *Differ) load(ctx context.Context, path, version string) (API, error) {
+	m, err := d.modules.Open(ctx, path, version)
+	if err != nil {
+		return nil, err
+	}
+
+	api, err := Load(m)
+	if err != nil {
+		return nil, fmt.Errorf("reading the API of %s@%s: %w", path, version, err)
+	}
+	return api, nil
+}
+
+// Load reads the exported API of the importable packages of a module.
+// Commands, tests and internal, testdata and vendor directories are skipped.
+func Load(m *modzip.Module) (API, error) {
+	ctxt := build.Default
+	ctxt.JoinPath = path.Join
+	ctxt.OpenFile = func(name string) (io.ReadCloser, error) {
+		data, err := m.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+
+	api := make(API)
+	fset := token.NewFileSet()
+
+	for _, name := range m.Files() {
+		dir, base := path.Split(name)
+		dir = strings.TrimSuffix(dir, "/")
+		if !strings.HasSuffix(base, ".go") || strings.HasSuffix(base, "_test.go") || !importable(dir) {
+			continue
+		}
+
+		if match, err := ctxt.MatchFile(dir, base); err != nil || !match {
+			continue
+		}
+
+		data, err := m.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		file, err := parser.ParseFile(fset, name, data, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		if file.Name.Name == "main" {
+			continue
+		}
+
+		pkg := api[dir]
+		if pkg == nil {
+			pkg = &Package{Name: file.Name.Name, Decls: make(map[string]string)}
+			api[dir] = pkg
+		}
+		addDecls(pkg.Decls, fset, file)
+	}
+
+	return api, nil
+}
+
+// importable returns false for directories whose packages cannot be imported
+// from another module, or that the go command ignores
+func importable(dir string) bool {
+	if dir == "" {
+		return true
+	}
+	for _, elem := range strings.Split(dir, "/") {
+		switch {
+		case elem == "internal", elem == "testdata", elem == "vendor":
+			return false
+		case strings.HasPrefix(elem, "."), strings.HasPrefix(elem, "_"):
+			return false
+		}
+	}
+	return true
+}
+
+// Compare returns the changes between two APIs of a module, sorted by
+// package and name. Members of added or removed types are not listed on
+// their own.
+func Compare(modulePath string, oldAPI, newAPI API) []Change {
+	var changes []Change
+
+	for _, dir := range sortedKeys(oldAPI, newAPI) {
+		importPath := modulePath
+		if dir != "" {
+			importPath += "/" + dir
+		}
+
+		oldPkg, newPkg := oldAPI[dir], newAPI[dir]
+		switch {
+		case oldPkg == nil:
+			changes = append(changes, Change{Package: importPath, Kind: Added, New: "package " + newPkg.Name})
+			continue
+		case newPkg == nil:
+			changes = append(changes, Change{Package: importPath, Kind: Removed, Old: "package " + oldPkg.Name})
+			continue
+		}
+
+		for _, name := range sortedKeys(oldPkg.Decls, newPkg.Decls) {
+			oldDecl, inOld := oldPkg.Decls[name]
+			newDecl, inNew := newPkg.Decls[name]
+
+			change := Change{Package: importPath, Name: name, Old: oldDecl, New: newDecl}
+			if parent, _, ok := strings.Cut(name, "."); ok {
+				oldParent, parentInOld := oldPkg.Decls[parent]
+				newParent, parentInNew := newPkg.Decls[parent]
+				if parentInOld != parentInNew {
+					continue
+				}
+				change.Interface = isInterface(oldParent) || isInterface(newParent)
+			}
+
+			switch {
+			case !inOld:
+				change.Kind = Added
+			case !inNew:
+				change.Kind = Removed
+			case oldDecl != newDecl:
+				change.Kind = Changed
+			default:
+				continue
+			}
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
+
+// isInterface reports whether a recorded declaration is an interface type
+func isInterface(decl string) bool {
+	return strings.HasPrefix(decl, "type ") && strings.HasSuffix(decl, " interface")
+}
+
+func sortedKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,258 @@
+package apidiff
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"goup/internal/dependency"
+	"goup/internal/modzip"
+	"goup/internal/proxy"
+)
+
+// fakeSource serves module zips keyed by "path@version"
+type fakeSource map[string][]byte
+
+func (s fakeSource) Zip(ctx context.Context, path, version string) ([]byte, error) {
+	data, ok := s[path+"@"+version]
+	if !ok {
+		return nil, proxy.ErrNotFound
+	}
+	return data, nil
+}
+
+// moduleZip builds a module zip holding the given files
+func moduleZip(t *testing.T, path, version string, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(path + "@" + version + "/" + name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+// loadAPI reads the API of a module made of the given files
+func loadAPI(t *testing.T, files map[string]string) API {
+	t.Helper()
+	cache := modzip.NewCache(fakeSource{"example.com/lib@v1.0.0": moduleZip(t, "example.com/lib", "v1.0.0", files)}, "")
+	m, err := cache.Open(context.Background(), "example.com/lib", "v1.0.0")
+	require.NoError(t, err)
+
+	api, err := Load(m)
+	require.NoError(t, err)
+	return api
+}
+
+func TestLoad(t *testing.T) {
+	api := loadAPI(t, map[string]string{
+		"lib.go": `package lib
+
+import "io"
+
+const (
+	KindA Kind = iota
+	KindB
+	hidden
+)
+
+const Version = "1.0"
+
+var ErrClosed, errOther = io.EOF, io.EOF
+
+type Kind int
+
+type Client[T any] struct {
+	Name    string
+	Timeout, Retries int
+	io.Reader
+	*Options
+	secret string
+}
+
+type Options struct{}
+
+type Store interface {
+	io.Closer
+	Get(key string) (value []byte, err error)
+	unexported()
+}
+
+type Alias = Client[int]
+
+func New[T any](name string, opts ...func(*Options)) (*Client[T], error) { return nil, nil }
+
+func (c *Client[T]) Do(ctx, other string) (err error) { return nil }
+
+func (k Kind) String() string { return "" }
+
+func (o options) Apply() {}
+
+func helper() {}
+`,
+		"lib_test.go":           "package lib\n\nfunc TestOnly() {}\n",
+		"lib_tagged.go":         "//go:build never\n\npackage lib\n\nfunc Tagged() {}\n",
+		"internal/x/x.go":       "package x\n\nfunc Internal() {}\n",
+		"testdata/t.go":         "package t\n\nfunc Fixture() {}\n",
+		"cmd/tool/main.go":      "package main\n\nfunc Main() {}\n",
+		"sub/sub.go":            "package sub\n\ntype T struct{ F chan<- map[string][]int }\n",
+		"sub/gen.go":            "//go:build ignore\n\npackage main\n\nfunc Generate() {}\n",
+		"README.md":             "# lib\n",
+		"_examples/example.go":  "package examples\n\nfunc Example() {}\n",
+		"sub/.hidden/hidden.go": "package hidden\n\nfunc Hidden() {}\n",
+	})
+
+	if lib, ok := api[""]; assert.True(t, ok) {
+		assert.Equal(t, "lib", lib.Name)
+		assert.Equal(t, map[string]string{
+			"KindA":            "const KindA Kind",
+			"KindB":            "const KindB Kind",
+			"Version":          "const Version",
+			"ErrClosed":        "var ErrClosed",
+			"Kind":             "type Kind int",
+			"Kind.String":      "func (Kind) String() string",
+			"Client":           "type Client[T any] struct",
+			"Client.Name":      "field Name string",
+			"Client.Timeout":   "field Timeout int",
+			"Client.Retries":   "field Retries int",
+			"Client.Reader":    "embedded io.Reader",
+			"Client.Options":   "embedded *Options",
+			"Client.Do":        "func (*Client[T]) Do(string, string) error",
+			"Options":          "type Options struct",
+			"Store":            "type Store interface",
+			"Store.io.Closer":  "embeds io.Closer",
+			"Store.Get":        "method Get(string) ([]byte, error)",
+			"Store.unexported": "method unexported()",
+			"Alias":            "type Alias = Client[int]",
+			"New":              "func New[T any](string, ...func(*Options)) (*Client[T], error)",
+		}, lib.Decls)
+	}
+
+	assert.Equal(t, map[string]string{"T": "type T struct", "T.F": "field F chan<- map[string][]int"}, api["sub"].Decls)
+	assert.Len(t, api, 2, "Only the importable packages are loaded")
+}
+
+func TestCompare(t *testing.T) {
+	oldAPI := API{
+		"": {Name: "lib", Decls: map[string]string{
+			"New":         "func New(string) *Client",
+			"Client":      "type Client struct",
+			"Client.Name": "field Name string",
+			"Client.Do":   "func (*Client) Do() error",
+			"Legacy":      "type Legacy struct",
+			"Legacy.Old":  "field Old int",
+			"Version":     "const Version",
+		}},
+		"v1api": {Name: "v1api", Decls: map[string]string{"Get": "func Get()"}},
+	}
+	newAPI := API{
+		"": {Name: "lib", Decls: map[string]string{
+			"New":          "func New(string, ...Option) *Client",
+			"Client":       "type Client struct",
+			"Client.Name":  "field Name string",
+			"Client.Close": "func (*Client) Close() error",
+			"Option":       "type Option func(*Client)",
+			"Option.Apply": "func (Option) Apply()",
+			"Version":      "const Version",
+		}},
+		"v2api": {Name: "v2api", Decls: map[string]string{"Get": "func Get()"}},
+	}
+
+	changes := Compare("example.com/lib", oldAPI, newAPI)
+
+	assert.Equal(t, []Change{
+		{Package: "example.com/lib", Name: "Client.Close", Kind: Added, New: "func (*Client) Close() error"},
+		{Package: "example.com/lib", Name: "Client.Do", Kind: Removed, Old: "func (*Client) Do() error"},
+		{Package: "example.com/lib", Name: "Legacy", Kind: Removed, Old: "type Legacy struct"},
+		{Package: "example.com/lib", Name: "New", Kind: Changed, Old: "func New(string) *Client", New: "func New(string, ...Option) *Client"},
+		{Package: "example.com/lib", Name: "Option", Kind: Added, New: "type Option func(*Client)"},
+		{Package: "example.com/lib/v1api", Kind: Removed, Old: "package v1api"},
+		{Package: "example.com/lib/v2api", Kind: Added, New: "package v2api"},
+	}, changes)
+
+	assert.Equal(t, []string{
+		"example.com/lib.Client.Do",
+		"example.com/lib.Legacy",
+		"example.com/lib.New",
+		"example.com/lib/v1api",
+	}, Breaking(changes))
+}
+
+func TestCompareInterfaceMethods(t *testing.T) {
+	oldAPI := loadAPI(t, map[string]string{"lib.go": `package lib
+
+type Store interface {
+	Get(key string) ([]byte, error)
+}
+
+type Client struct{}
+`})
+	newAPI := loadAPI(t, map[string]string{"lib.go": `package lib
+
+import "io"
+
+type Store interface {
+	io.Closer
+	Get(key string) ([]byte, error)
+	Put(key string, value []byte) error
+	sealed()
+}
+
+type Client struct{}
+
+func (Client) Put() {}
+`})
+
+	changes := Compare("example.com/lib", oldAPI, newAPI)
+
+	// Every implementation of Store must now provide the new methods
+	assert.Equal(t, []string{
+		"example.com/lib.Store.Put",
+		"example.com/lib.Store.io.Closer",
+		"example.com/lib.Store.sealed",
+	}, Breaking(changes))
+	assert.False(t, changes[0].Breaking(), "A method added to a struct is not breaking")
+	assert.Equal(t, "Client.Put", changes[0].Name)
+}
+
+func TestCompareIgnoresParameterNames(t *testing.T) {
+	oldAPI := loadAPI(t, map[string]string{"lib.go": "package lib\n\nfunc Get(key string) (value []byte, err error) { return nil, nil }\n"})
+	newAPI := loadAPI(t, map[string]string{"lib.go": "package lib\n\nfunc Get(name string) ([]byte, error) { return nil, nil }\n"})
+
+	assert.Empty(t, Compare("example.com/lib", oldAPI, newAPI))
+}
+
+func TestDifferChanges(t *testing.T) {
+	source := fakeSource{
+		"example.com/lib@v1.2.0": moduleZip(t, "example.com/lib", "v1.2.0", map[string]string{
+			"lib.go": "package lib\n\nfunc Foo() {}\n\nfunc Bar() {}\n",
+		}),
+		"example.com/lib/v2@v2.0.0": moduleZip(t, "example.com/lib/v2", "v2.0.0", map[string]string{
+			"lib.go": "package lib\n\nfunc Foo(n int) {}\n",
+		}),
+	}
+	differ := NewDiffer(modzip.NewCache(source, ""))
+
+	changes, err := differ.Changes(context.Background(), dependency.Dependency{
+		Path: "example.com/lib", Version: "v1.2.0", NewPath: "example.com/lib/v2", NewVersion: "v2.0.0", HasUpdate: true,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []Change{
+		{Package: "example.com/lib/v2", Name: "Bar", Kind: Removed, Old: "func Bar()"},
+		{Package: "example.com/lib/v2", Name: "Foo", Kind: Changed, Old: "func Foo()", New: "func Foo(int)"},
+	}, changes)
+
+	_, err = differ.Changes(context.Background(), dependency.Dependency{
+		Path: "example.com/lib", Version: "v1.2.0", NewVersion: "v1.3.0", HasUpdate: true,
+	})
+	assert.ErrorIs(t, err, proxy.ErrNotFound)
+}
@@ -0,0 +1,207 @@
+package apidiff
+
+import (
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"strings"
+)
+
+// addDecls records the exported declarations of a file. Parameter names are
+// left out of signatures since renaming them does not affect callers.
+func addDecls(decls map[string]string, fset *token.FileSet, file *ast.File) {
+	p := declPrinter{fset: fset}
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			p.addFunc(decls, d)
+		case *ast.GenDecl:
+			switch d.Tok {
+			case token.TYPE:
+				for _, spec := range d.Specs {
+					p.addType(decls, spec.(*ast.TypeSpec))
+				}
+			case token.CONST, token.VAR:
+				p.addValues(decls, d)
+			}
+		}
+	}
+}
+
+// declPrinter formats declarations as single lines
+type declPrinter struct {
+	fset *token.FileSet
+}
+
+func (p declPrinter) addFunc(decls map[string]string, d *ast.FuncDecl) {
+	if !d.Name.IsExported() {
+		return
+	}
+
+	if d.Recv == nil || len(d.Recv.List) == 0 {
+		decls[d.Name.Name] = "func " + d.Name.Name + p.typeParams(d.Type.TypeParams) + p.signature(d.Type)
+		return
+	}
+
+	recv := d.Recv.List[0].Type
+	typeName := baseName(recv)
+	if !ast.IsExported(typeName) {
+		return
+	}
+	decls[typeName+"."+d.Name.Name] = "func (" + p.expr(recv) + ") " + d.Name.Name + p.signature(d.Type)
+}
+
+func (p declPrinter) addType(decls map[string]string, spec *ast.TypeSpec) {
+	if !spec.Name.IsExported() {
+		return
+	}
+
+	name := spec.Name.Name
+	head := "type " + name + p.typeParams(spec.TypeParams)
+	if spec.Assign.IsValid() {
+		decls[name] = head + " = " + p.expr(spec.Type)
+		return
+	}
+
+	switch t := spec.Type.(type) {
+	case *ast.StructType:
+		decls[name] = head + " struct"
+		for _, field := range t.Fields.List {
+			if len(field.Names) == 0 {
+				if embedded := baseName(field.Type); ast.IsExported(embedded) {
+					decls[name+"."+embedded] = "embedded " + p.expr(field.Type)
+				}
+				continue
+			}
+			for _, fieldName := range field.Names {
+				if fieldName.IsExported() {
+					decls[name+"."+fieldName.Name] = "field " + fieldName.Name + " " + p.expr(field.Type)
+				}
+			}
+		}
+
+	case *ast.InterfaceType:
+		decls[name] = head + " interface"
+		for _, method := range t.Methods.List {
+			if len(method.Names) == 0 {
+				// An embedded interface or a type set
+				embedded := p.expr(method.Type)
+				decls[name+"."+embedded] = "embeds " + embedded
+				continue
+			}
+			// Unexported methods are kept: adding one breaks the implementations outside the package
+			if ft, ok := method.Type.(*ast.FuncType); ok {
+				decls[name+"."+method.Names[0].Name] = "method " + method.Names[0].Name + p.signature(ft)
+			}
+		}
+
+	default:
+		decls[name] = head + " " + p.expr(spec.Type)
+	}
+}
+
+func (p declPrinter) addValues(decls map[string]string, d *ast.GenDecl) {
+	// Constants without a type or value repeat the previous ones (iota)
+	var previous ast.Expr
+
+	for _, spec := range d.Specs {
+		vs := spec.(*ast.ValueSpec)
+		typ := vs.Type
+		if d.Tok == token.CONST {
+			if typ == nil && len(vs.Values) == 0 {
+				typ = previous
+			} else {
+				previous = typ
+			}
+		}
+
+		for _, name := range vs.Names {
+			if !name.IsExported() {
+				continue
+			}
+			decl := d.Tok.String() + " " + name.Name
+			if typ != nil {
+				decl += " " + p.expr(typ)
+			}
+			decls[name.Name] = decl
+		}
+	}
+}
+
+// signature formats the parameters and results of a function type
+func (p declPrinter) signature(ft *ast.FuncType) string {
+	sig := "(" + p.fieldTypes(ft.Params) + ")"
+
+	if ft.Results == nil || len(ft.Results.List) == 0 {
+		return sig
+	}
+	results := p.fieldTypes(ft.Results)
+	if len(ft.Results.List) == 1 && len(ft.Results.List[0].Names) <= 1 {
+		return sig + " " + results
+	}
+	return sig + " (" + results + ")"
+}
+
+// fieldTypes formats the types of a parameter list, once per parameter
+func (p declPrinter) fieldTypes(fields *ast.FieldList) string {
+	if fields == nil {
+		return ""
+	}
+
+	var types []string
+	for _, field := range fields.List {
+		typ := p.expr(field.Type)
+		for range max(len(field.Names), 1) {
+			types = append(types, typ)
+		}
+	}
+	return strings.Join(types, ", ")
+}
+
+// typeParams formats type parameters with their constraints
+func (p declPrinter) typeParams(fields *ast.FieldList) string {
+	if fields == nil || len(fields.List) == 0 {
+		return ""
+	}
+
+	var params []string
+	for _, field := range fields.List {
+		names := make([]string, len(field.Names))
+		for i, name := range field.Names {
+			names[i] = name.Name
+		}
+		params = append(params, strings.Join(names, ", ")+" "+p.expr(field.Type))
+	}
+	return "[" + strings.Join(params, ", ") + "]"
+}
+
+// expr formats an expression on a single line
+func (p declPrinter) expr(expr ast.Expr) string {
+	var sb strings.Builder
+	if err := printer.Fprint(&sb, p.fset, expr); err != nil {
+		return "?"
+	}
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// baseName returns the name of the type in a receiver or embedded field,
+// without pointer, package qualifier or type arguments
+func baseName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.StarExpr:
+		return baseName(e.X)
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	case *ast.IndexExpr:
+		return baseName(e.X)
+	case *ast.IndexListExpr:
+		return baseName(e.X)
+	case *ast.ParenExpr:
+		return baseName(e.X)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"goup/internal/apidiff"
+	"goup/internal/dependency"
+	"goup/internal/ui"
+)
+
+// attachBreakingChanges compares the exported API of every update so the
+// table can flag the ones that remove or change identifiers. Modules whose
+// API cannot be read are shown without the flag.
+func (a *App) attachBreakingChanges(ctx context.Context, deps []dependency.Dependency) []dependency.Dependency {
+	if a.sources.APIs == nil {
+		a.console.Warning("API comparison is not available, showing the updates without it")
+		return deps
+	}
+
+	return a.annotate(ctx, deps, "API", func(ctx context.Context, dep *dependency.Dependency) {
+		changes, err := a.sources.APIs.Changes(ctx, *dep)
+		if err != nil {
+			a.console.Warning("Could not compare the API of %s: %v", dep.TargetPath(), err)
+			return
+		}
+		dep.Breaking = apidiff.Breaking(changes)
+	})
+}
+
+// diffModule reports the exported API changes between the version of a
+// module required by go.mod and its update, or the version given after '@'
+func (a *App) diffModule(ctx context.Context, report *ui.Report) error {
+	if a.sources.APIs == nil {
+		return fmt.Errorf("API comparison is not available")
+	}
+
+	dep, err := a.diffTarget(ctx)
+	if err != nil || dep == nil {
+		return err
+	}
+
+	var changes []apidiff.Change
+	a.withCommandTimeout(ctx, func(ctx context.Context) {
+		changes, err = a.sources.APIs.Changes(ctx, *dep)
+	})
+	if err != nil {
+		return fmt.Errorf("comparing the API of %s: %w", dep.Path, err)
+	}
+
+	dep.Breaking = apidiff.Breaking(changes)
+	report.Dependencies = []dependency.Dependency{*dep}
+	report.APIChanges = changes
+
+	a.console.PrintAPIChanges(*dep, changes)
+	if len(dep.Breaking) > 0 {
+		a.console.Warning("Updating %s to %s removes or changes %d exported identifiers", dep.Path, dep.NewVersion, len(dep.Breaking))
+	} else {
+		a.console.Success("Updating %s to %s keeps every exported identifier", dep.Path, dep.NewVersion)
+	}
+	return nil
+}
+
+// diffTarget returns the dependency compared by goup diff, or nil if the
+// module is up to date
+func (a *App) diffTarget(ctx context.Context) (*dependency.Dependency, error) {
+	path, version, _ := strings.Cut(a.config.DiffModule, "@")
+
+	if version == "" {
+		// The update goup would apply, with the policy and rules of the configuration
+		updates, err := a.findUpdates(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, dep := range updates {
+			if dep.Path == path {
+				return &dep, nil
+			}
+		}
+	}
+
+	deps, err := a.depMgr.GetDependencies()
+	if err != nil {
+		return nil, err
+	}
+	for _, dep := range deps {
+		if dep.Path != path {
+			continue
+		}
+		if version == "" {
+			a.console.Info("%s %s is up to date", dep.Path, dep.Version)
+			return nil, nil
+		}
+
+		dep.NewVersion = version
+		dep.HasUpdate = true
+		return &dep, nil
+	}
+
+	return nil, fmt.Errorf("%s is not required by the module", path)
+}
@@ -0,0 +1,187 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"goup/internal/apidiff"
+	"goup/internal/config"
+	"goup/internal/dependency"
+	"goup/internal/mocks"
+	"goup/internal/ui"
+)
+
+// fakeAPIs returns canned API changes, or errors, by module path and new version
+type fakeAPIs map[string]any
+
+func (f fakeAPIs) Changes(ctx context.Context, dep dependency.Dependency) ([]apidiff.Change, error) {
+	switch v := f[dep.TargetPath()+"@"+dep.NewVersion].(type) {
+	case []apidiff.Change:
+		return v, nil
+	case error:
+		return nil, v
+	default:
+		return nil, nil
+	}
+}
+
+var removedFoo = apidiff.Change{Package: "github.com/foo/bar", Name: "Foo", Kind: apidiff.Removed, Old: "func Foo()"}
+
+func TestRunListWithAPIDiff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{List: true, APIDiff: true}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/foo/bar", Version: "v1.2.0", NewVersion: "v1.3.0", HasUpdate: true},
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+		{Path: "golang.org/x/crypto", Version: "v0.14.0", NewVersion: "v0.17.0", HasUpdate: true},
+	}
+	apis := fakeAPIs{
+		"github.com/foo/bar@v1.3.0":   []apidiff.Change{removedFoo, {Kind: apidiff.Added, Name: "Bar"}},
+		"golang.org/x/crypto@v0.17.0": errors.New("downloading golang.org/x/crypto@v0.17.0: 410 Gone"),
+	}
+
+	flagged := append([]dependency.Dependency(nil), deps...)
+	flagged[0].Breaking = []string{"github.com/foo/bar.Foo"}
+
+	console.EXPECT().Header()
+	console.EXPECT().PrintReport(gomock.Any())
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().ProgressBar(gomock.Any(), 3, gomock.Any()).Times(4)
+	console.EXPECT().Warning("Could not compare the API of %s: %v", "golang.org/x/crypto", gomock.Any())
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil)
+	depMgr.EXPECT().FilterDependencies(deps, false).Return(deps)
+	console.EXPECT().PrintDependencies(flagged, "Found 3 direct dependencies with available updates:")
+
+	app := NewWithSources(cfg, console, depMgr, mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl), Sources{APIs: apis})
+	err := app.Run(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func TestRunDiff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{DiffModule: "github.com/foo/bar"}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+
+	updates := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+		{Path: "github.com/foo/bar", Version: "v1.2.0", NewVersion: "v1.3.0", HasUpdate: true},
+	}
+	changes := []apidiff.Change{removedFoo}
+	expected := updates[1]
+	expected.Breaking = []string{"github.com/foo/bar.Foo"}
+
+	var report ui.Report
+	console.EXPECT().Header()
+	console.EXPECT().PrintReport(gomock.Any()).Do(func(r ui.Report) { report = r })
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(updates, nil)
+	console.EXPECT().PrintAPIChanges(expected, changes)
+	console.EXPECT().Warning("Updating %s to %s removes or changes %d exported identifiers", "github.com/foo/bar", "v1.3.0", 1)
+
+	app := NewWithSources(cfg, console, depMgr, mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl),
+		Sources{APIs: fakeAPIs{"github.com/foo/bar@v1.3.0": changes}})
+	err := app.Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, ui.ModeDiff, report.Mode)
+	assert.Equal(t, []dependency.Dependency{expected}, report.Dependencies)
+	assert.Equal(t, changes, report.APIChanges)
+}
+
+func TestRunDiffWithVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{DiffModule: "github.com/foo/bar@v1.5.0"}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+
+	required := []dependency.Dependency{{Path: "github.com/foo/bar", Version: "v1.2.0"}}
+	expected := dependency.Dependency{Path: "github.com/foo/bar", Version: "v1.2.0", NewVersion: "v1.5.0", HasUpdate: true}
+
+	console.EXPECT().Header()
+	console.EXPECT().PrintReport(gomock.Any())
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	depMgr.EXPECT().GetDependencies().Return(required, nil)
+	console.EXPECT().PrintAPIChanges(expected, nil)
+	console.EXPECT().Success("Updating %s to %s keeps every exported identifier", "github.com/foo/bar", "v1.5.0")
+
+	app := NewWithSources(cfg, console, depMgr, mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl), Sources{APIs: fakeAPIs{}})
+	err := app.Run(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func TestRunDiffUpToDate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{DiffModule: "github.com/foo/bar"}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+
+	console.EXPECT().Header()
+	console.EXPECT().PrintReport(gomock.Any())
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(nil, nil)
+	depMgr.EXPECT().GetDependencies().Return([]dependency.Dependency{{Path: "github.com/foo/bar", Version: "v1.2.0"}}, nil)
+	console.EXPECT().Info("%s %s is up to date", "github.com/foo/bar", "v1.2.0")
+
+	app := NewWithSources(cfg, console, depMgr, mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl), Sources{APIs: fakeAPIs{}})
+	err := app.Run(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func TestRunDiffErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources Sources
+		wantErr string
+	}{
+		{
+			name:    "module not required",
+			sources: Sources{APIs: fakeAPIs{}},
+			wantErr: "example.com/other is not required by the module",
+		},
+		{
+			name:    "no API source",
+			wantErr: "API comparison is not available",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			cfg := &config.Config{DiffModule: "example.com/other@v1.0.0"}
+			console := mocks.NewMockConsole(ctrl)
+			depMgr := mocks.NewMockManager(ctrl)
+
+			console.EXPECT().Header()
+			console.EXPECT().PrintReport(gomock.Any())
+			console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+			depMgr.EXPECT().GetDependencies().Return([]dependency.Dependency{{Path: "github.com/foo/bar", Version: "v1.2.0"}}, nil).AnyTimes()
+
+			app := NewWithSources(cfg, console, depMgr, mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl), tt.sources)
+			err := app.Run(context.Background())
+
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
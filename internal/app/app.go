@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"fmt"
 
 	"goup/internal/config"
@@ -8,6 +9,7 @@ import (
 	"goup/internal/selector"
 	"goup/internal/ui"
 	"goup/internal/updater"
+	"goup/internal/vuln"
 )
 
 // App represents the main application
@@ -17,6 +19,12 @@ type App struct {
 	depMgr   dependency.Manager
 	selector selector.Selector
 	updater  updater.Updater
+	targets  map[string]string       // Versions shared by every module in recursive mode, by module path
+	updates  []dependency.Dependency // Result of findUpdates, nil until it has run
+	notices  []dependency.Dependency // Up-to-date dependencies found by findUpdates with a retraction or deprecation notice
+	vulnDB   *vuln.Database          // Vulnerability database, loaded on first use in security mode
+
+	sources Sources // Module contents used by --changelog, --api-diff and goup diff
 }
 
 // New creates a new application instance
@@ -26,6 +34,19 @@ func New(
 	depMgr dependency.Manager,
 	sel selector.Selector,
 	upd updater.Updater,
+) *App {
+	return NewWithSources(cfg, console, depMgr, sel, upd, Sources{})
+}
+
+// NewWithSources creates an application instance that reads the changelogs
+// and the APIs of the updated modules from sources
+func NewWithSources(
+	cfg *config.Config,
+	console ui.Console,
+	depMgr dependency.Manager,
+	sel selector.Selector,
+	upd updater.Updater,
+	sources Sources,
 ) *App {
 	return &App{
 		config:   cfg,
@@ -33,13 +54,60 @@ func New(
 		depMgr:   depMgr,
 		selector: sel,
 		updater:  upd,
+		sources:  sources,
 	}
 }
 
-// Run executes the main application logic
-func (a *App) Run() error {
+// Run executes the main application logic and reports the outcome. Cancelling
+// ctx stops the run after the current update.
+func (a *App) Run(ctx context.Context) error {
 	a.console.Header()
 
+	report, err := a.execute(ctx)
+	a.console.PrintReport(report)
+
+	return err
+}
+
+// execute runs the application and returns its report without printing it
+func (a *App) execute(ctx context.Context) (ui.Report, error) {
+	report := ui.Report{Mode: reportMode(a.config)}
+
+	err := a.run(ctx, &report)
+	if stopErr := stopError(ctx, a.config); err != nil && stopErr != nil {
+		// Commands killed by the interruption fail with less useful errors
+		err = stopErr
+	}
+
+	report.ExitCode = ExitCode(err)
+	if report.ExitCode == ExitError || report.ExitCode == ExitInterrupted {
+		report.Err = err
+	}
+
+	return report, err
+}
+
+// reportMode returns the report mode matching the configuration
+func reportMode(cfg *config.Config) string {
+	switch {
+	case cfg.Rollback:
+		return ui.ModeRollback
+	case cfg.DiffModule != "":
+		return ui.ModeDiff
+	case cfg.WhyModule != "":
+		return ui.ModeWhy
+	case cfg.ExcludeModule != "":
+		return ui.ModeExclude
+	case cfg.List:
+		return ui.ModeList
+	case cfg.DryRun:
+		return ui.ModeDryRun
+	default:
+		return ui.ModeUpdate
+	}
+}
+
+func (a *App) run(ctx context.Context, report *ui.Report) error {
 	// Debug: Print configuration
 	if a.config.Verbose {
 		if a.config.List {
@@ -48,22 +116,84 @@ func (a *App) Run() error {
 		if a.config.Selective {
 			a.console.Debug("Selective mode enabled")
 		}
+		if a.config.FailOnUpdates {
+			a.console.Debug("Fail on updates enabled")
+		}
+		if a.config.Policy != dependency.PolicyLatest {
+			a.console.Debug("Update policy: %s", a.config.Policy)
+		}
+		if a.config.ShouldDiscoverMajors() {
+			a.console.Debug("Major version discovery enabled")
+		}
+		if a.config.KeepPartial {
+			a.console.Debug("Keeping partial updates on failure")
+		}
+		if a.config.Verify {
+			a.console.Debug("Verifying each update with: %s", a.config.GetVerifyCommand())
+		}
 		if a.config.Interactive {
 			a.console.Debug("Interactive mode enabled")
 		}
 		if a.config.All {
 			a.console.Debug("All dependencies mode enabled")
 		}
+		if a.config.Changelog {
+			a.console.Debug("Changelog preview enabled")
+		}
+		if a.config.APIDiff {
+			a.console.Debug("API comparison enabled")
+		}
+		if a.config.Impact {
+			a.console.Debug("Impact analysis enabled")
+		}
+		if a.config.OnlyRetracted {
+			a.console.Debug("Only updating retracted versions")
+		}
+		if a.config.DryRun {
+			a.console.Debug("Dry run enabled, go.mod and go.sum will not be changed")
+		}
+		if a.config.Replaced {
+			a.console.Debug("Including replaced modules")
+		}
+		if a.config.DropReplaces {
+			a.console.Debug("Looking for stale replace directives")
+		}
 	}
 
-	// Get only updatable dependencies
-	allUpdatableDeps, err := a.depMgr.GetUpdatableDependencies()
+	if a.config.Rollback {
+		return a.rollbackLastRun(report)
+	}
+	if a.config.DiffModule != "" {
+		return a.diffModule(ctx, report)
+	}
+	if a.config.WhyModule != "" {
+		return a.explainModule(ctx, report)
+	}
+	if a.config.ExcludeModule != "" {
+		return a.excludeVersion(ctx, report)
+	}
+
+	allUpdatableDeps, err := a.findUpdates(ctx)
 	if err != nil {
 		return err
 	}
+	a.reportNotices()
+	allUpdatableDeps = a.alignVersions(ctx, allUpdatableDeps)
+	if a.config.OnlyRetracted {
+		allUpdatableDeps = retractedOnly(allUpdatableDeps)
+	}
+	allUpdatableDeps = a.skipReplaced(allUpdatableDeps)
 
 	if len(allUpdatableDeps) == 0 {
-		a.console.Info("All dependencies are up to date! 🎉")
+		if a.config.Security {
+			a.console.Info("No known vulnerabilities affect the dependencies 🎉")
+		} else if a.config.OnlyRetracted {
+			a.console.Info("No dependency uses a retracted version 🎉")
+		} else if a.config.DropReplaces {
+			a.console.Info("No replace directive is stale 🎉")
+		} else {
+			a.console.Info("All dependencies are up to date! 🎉")
+		}
 		return nil
 	}
 
@@ -81,6 +211,17 @@ func (a *App) Run() error {
 		}
 		return nil
 	}
+	filteredDeps = a.attachRequirements(ctx, filteredDeps, allUpdatableDeps)
+	if a.config.Changelog {
+		filteredDeps = a.attachChangelogs(ctx, filteredDeps)
+	}
+	if a.config.APIDiff {
+		filteredDeps = a.attachBreakingChanges(ctx, filteredDeps)
+	}
+	if a.config.Impact {
+		filteredDeps = a.attachImpact(ctx, filteredDeps)
+	}
+	report.Dependencies = filteredDeps
 
 	// Select dependencies to update
 	a.console.Debug("Selecting dependencies to update...")
@@ -98,33 +239,63 @@ func (a *App) Run() error {
 
 	// Handle List mode
 	if a.config.List {
+		if a.config.FailOnUpdates {
+			return ErrUpdatesAvailable
+		}
 		return nil
 	}
 
+	// Nothing is changed by a dry run, so there is nothing to confirm
+	if a.config.DryRun {
+		return a.previewUpdate(ctx, selectedDeps, report)
+	}
+
 	// Confirm update if in interactive mode (but not selective, as that already confirms)
-	if a.config.Interactive && !a.config.Selective {
+	if a.config.Interactive && !a.config.IsSelective() {
 		if !a.console.Confirm("Do you want to proceed with the update?") {
 			a.console.Info("Update cancelled")
 			return nil
 		}
 	}
 
+	// Ctrl-C may have been pressed while choosing
+	if err := stopError(ctx, a.config); err != nil {
+		return err
+	}
+
 	// Perform the update - handle failures gracefully
-	return a.performUpdate(selectedDeps)
+	return a.performUpdate(ctx, selectedDeps, report)
 }
 
 func (a *App) selectDependencies(deps []dependency.Dependency) ([]dependency.Dependency, error) {
-	if !a.config.Selective {
+	if !a.config.IsSelective() {
 		// Non-selective mode: show dependencies that will be updated and return all
 		typeStr := "direct"
 		if a.config.All {
 			typeStr = "all"
 		}
 		title := fmt.Sprintf("Found %d %s dependencies with available updates:", len(deps), typeStr)
+		if a.config.Security {
+			title = fmt.Sprintf("Found %d %s dependencies with known vulnerabilities:", len(deps), typeStr)
+		}
+		if a.config.DropReplaces {
+			title = fmt.Sprintf("Found %d %s dependencies with stale replace directives:", len(deps), typeStr)
+		}
 		a.console.PrintDependencies(deps, title)
+		for _, dep := range deps {
+			if a.config.Changelog {
+				a.console.PrintChangelog(dep)
+			}
+			if a.config.Impact {
+				a.console.PrintImpact(dep)
+			}
+		}
+		a.warnNotices(deps)
 		return deps, nil
 	}
 
+	a.warnNotices(deps)
+
 	// Selective mode: use interactive selection
 	result := a.selector.Select(deps, a.config.ShouldIncludeIndirect())
 	if result.Error != nil {
@@ -138,22 +309,41 @@ func (a *App) selectDependencies(deps []dependency.Dependency) ([]dependency.Dep
 	return result.Selected, nil
 }
 
-func (a *App) performUpdate(deps []dependency.Dependency) error {
+func (a *App) performUpdate(ctx context.Context, deps []dependency.Dependency, report *ui.Report) error {
+	// Save go.mod and go.sum so a failed run can be undone, now or with --rollback
+	if err := a.updater.Snapshot(); err != nil {
+		return fmt.Errorf("saving snapshot of go.mod and go.sum: %w", err)
+	}
+
 	a.console.Info("Updating dependencies...")
 
 	// Update dependencies with progress reporting
-	result := a.updateWithProgress(deps)
+	result := a.updateWithProgress(ctx, deps)
+	report.Update = &result
 
 	// Report results
-	a.console.PrintUpdateResult(len(result.Updated), len(deps), len(result.Failed) > 0)
+	a.console.PrintUpdateResult(len(result.Updated), len(deps), !result.Success)
 
 	// Show individual errors if any - but don't fail the whole process
 	for _, failure := range result.Failed {
 		a.console.Error("Failed to update %s: %v", failure.Dependency.Path, failure.Error)
 	}
+	a.reportVerification(result)
+
+	// A failed update can leave go.mod half-migrated, so undo the whole run
+	if len(result.Failed) > 0 && !a.config.KeepPartial {
+		return a.rollbackFailedUpdate(result, report)
+	}
+
+	// The updates applied before the interruption are kept
+	if err := stopError(ctx, a.config); err != nil {
+		return a.reportStop(result, len(deps), err)
+	}
 
 	// Run go mod tidy - even if some updates failed
-	if err := a.runModTidy(); err != nil {
+	err := a.runModTidy(ctx)
+	report.Tidy = &ui.TidyResult{Err: err}
+	if err != nil {
 		// Don't fail completely if mod tidy fails
 		a.console.Warning("go mod tidy failed: %v", err)
 	} else {
@@ -166,35 +356,119 @@ func (a *App) performUpdate(deps []dependency.Dependency) error {
 		if len(result.Failed) > 0 {
 			a.console.Info("Successfully updated %d out of %d dependencies", len(result.Updated), len(deps))
 		}
-	} else if len(result.Failed) > 0 {
+	} else if len(result.Failed) > 0 || len(result.Reverted) > 0 {
 		a.console.Warning("No dependencies were successfully updated due to errors")
 	}
 
-	return nil // Don't fail the whole process for individual dependency issues
+	// Individual failures don't stop the run, but they decide the exit code
+	switch {
+	case len(result.Failed) == 0 && len(result.Reverted) == 0:
+		return nil
+	case len(result.Updated) == 0:
+		return ErrTotalFailure
+	default:
+		return ErrPartialFailure
+	}
+}
+
+func (a *App) reportVerification(result updater.UpdateResult) {
+	if !a.config.Verify {
+		return
+	}
+
+	for _, reverted := range result.Reverted {
+		a.console.Error("Reverted %s %s: %v", reverted.Dependency.Path, reverted.Dependency.NewVersion, reverted.Error)
+	}
+
+	if len(result.Skipped) > 0 {
+		a.console.Warning("The module failed '%s' before any update, %d dependencies were updated without verification",
+			a.config.GetVerifyCommand(), len(result.Skipped))
+	} else if len(result.Verified) > 0 {
+		a.console.Success("%d updates passed '%s'", len(result.Verified), a.config.GetVerifyCommand())
+	}
+}
+
+func (a *App) rollbackFailedUpdate(result updater.UpdateResult, report *ui.Report) error {
+	a.console.Warning("Rolling back go.mod and go.sum to their state before the update...")
+	if err := a.updater.Rollback(); err != nil {
+		return fmt.Errorf("rollback failed: %w", err)
+	}
+	report.RolledBack = true
+
+	a.console.Success("Rollback completed, no dependencies were changed")
+	if len(result.Updated) > 0 {
+		a.console.Info("Use --keep-partial to keep the %d dependencies that updated successfully", len(result.Updated))
+	}
+
+	if len(result.Updated) == 0 {
+		return ErrTotalFailure
+	}
+	return ErrPartialFailure
 }
 
-func (a *App) updateWithProgress(deps []dependency.Dependency) updater.UpdateResult {
+func (a *App) rollbackLastRun(report *ui.Report) error {
+	a.console.Info("Restoring go.mod and go.sum from the last run...")
+	if err := a.updater.Rollback(); err != nil {
+		return fmt.Errorf("rollback failed: %w", err)
+	}
+	report.RolledBack = true
+
+	a.console.Success("Rollback completed")
+	return nil
+}
+
+// updateWithProgress applies the updates one at a time, or all at once in
+// batch mode. Once ctx is cancelled no further update is started, but the
+// current one is never interrupted halfway.
+func (a *App) updateWithProgress(ctx context.Context, deps []dependency.Dependency) updater.UpdateResult {
+	updateCtx, cancel := uninterruptible(ctx)
+	defer cancel()
+
+	if a.config.Batch {
+		// One resolution for every update, so there is no per-dependency progress
+		message := fmt.Sprintf("%d dependencies in one batch", len(deps))
+		a.console.ProgressBar(0, len(deps), message)
+		result := a.updater.UpdateDependencies(updateCtx, deps, a.config.Verbose)
+		a.console.ProgressBar(len(deps), len(deps), message)
+		return mergeResults([]updater.UpdateResult{result})
+	}
+
 	var allResults []updater.UpdateResult
 
 	for i, dep := range deps {
+		if ctx.Err() != nil {
+			break
+		}
+
 		a.console.ProgressBar(i, len(deps), dep.Path)
 
 		// Update individual dependency - errors are captured in result
-		singleResult := a.updater.UpdateDependencies([]dependency.Dependency{dep}, a.config.Verbose)
+		singleResult := a.updater.UpdateDependencies(updateCtx, []dependency.Dependency{dep}, a.config.Verbose)
 		allResults = append(allResults, singleResult)
 
 		a.console.ProgressBar(i+1, len(deps), dep.Path)
 	}
 
+	return mergeResults(allResults)
+}
+
+// mergeResults combines update results into one with every list non-nil
+func mergeResults(allResults []updater.UpdateResult) updater.UpdateResult {
 	finalResult := updater.UpdateResult{
-		Updated: make([]dependency.Dependency, 0),
-		Failed:  make([]updater.UpdateError, 0),
-		Success: true,
+		Updated:  make([]dependency.Dependency, 0),
+		Failed:   make([]updater.UpdateError, 0),
+		Verified: make([]dependency.Dependency, 0),
+		Reverted: make([]updater.UpdateError, 0),
+		Skipped:  make([]dependency.Dependency, 0),
+		Success:  true,
 	}
 
 	for _, result := range allResults {
 		finalResult.Updated = append(finalResult.Updated, result.Updated...)
 		finalResult.Failed = append(finalResult.Failed, result.Failed...)
+		finalResult.Verified = append(finalResult.Verified, result.Verified...)
+		finalResult.Reverted = append(finalResult.Reverted, result.Reverted...)
+		finalResult.Skipped = append(finalResult.Skipped, result.Skipped...)
 		if !result.Success {
 			finalResult.Success = false
 		}
@@ -203,7 +477,10 @@ func (a *App) updateWithProgress(deps []dependency.Dependency) updater.UpdateRes
 	return finalResult
 }
 
-func (a *App) runModTidy() error {
+func (a *App) runModTidy(ctx context.Context) error {
 	a.console.Info("Running go mod tidy...")
-	return a.updater.RunModTidy(a.config.Verbose)
+
+	tidyCtx, cancel := uninterruptible(ctx)
+	defer cancel()
+	return a.updater.RunModTidy(tidyCtx, a.config.Verbose)
 }
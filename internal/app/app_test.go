@@ -1,7 +1,9 @@
 package app
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -12,6 +14,8 @@ import (
 	"goup/internal/dependency"
 	"goup/internal/mocks"
 	"goup/internal/selector"
+	"goup/internal/snapshot"
+	"goup/internal/ui"
 	"goup/internal/updater"
 )
 
@@ -27,11 +31,12 @@ func TestRunNoDependencies(t *testing.T) {
 
 	// Setup expectations
 	console.EXPECT().Header().Times(1)
-	depMgr.EXPECT().GetUpdatableDependencies().Return([]dependency.Dependency{}, nil).Times(1)
+	console.EXPECT().PrintReport(gomock.Any()).Times(1)
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return([]dependency.Dependency{}, nil).Times(1)
 	console.EXPECT().Info("All dependencies are up to date! 🎉").Times(1)
 
 	app := New(cfg, console, depMgr, sel, upd)
-	err := app.Run()
+	err := app.Run(context.Background())
 
 	assert.NoError(t, err)
 }
@@ -48,10 +53,11 @@ func TestRunGetDependenciesError(t *testing.T) {
 
 	// Setup expectations
 	console.EXPECT().Header().Times(1)
-	depMgr.EXPECT().GetUpdatableDependencies().Return(nil, errors.New("failed to read go.mod")).Times(1)
+	console.EXPECT().PrintReport(gomock.Any()).Times(1)
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(nil, errors.New("failed to read go.mod")).Times(1)
 
 	app := New(cfg, console, depMgr, sel, upd)
-	err := app.Run()
+	err := app.Run(context.Background())
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to read go.mod")
@@ -73,13 +79,14 @@ func TestRunNoDirectDependencies(t *testing.T) {
 
 	// Setup expectations
 	console.EXPECT().Header().Times(1)
-	depMgr.EXPECT().GetUpdatableDependencies().Return(deps, nil).Times(1)
+	console.EXPECT().PrintReport(gomock.Any()).Times(1)
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil).Times(1)
 	depMgr.EXPECT().FilterDependencies(deps, false).Return([]dependency.Dependency{}).Times(1)
 	console.EXPECT().Info("All direct dependencies are up to date! 🎉").Times(1)
 	console.EXPECT().Info("(%d indirect dependencies have updates available, use --all to include them)", 1).Times(1)
 
 	app := New(cfg, console, depMgr, sel, upd)
-	err := app.Run()
+	err := app.Run(context.Background())
 
 	assert.NoError(t, err)
 }
@@ -100,13 +107,40 @@ func TestRunList(t *testing.T) {
 
 	// Setup expectations
 	console.EXPECT().Header().Times(1)
+	console.EXPECT().PrintReport(gomock.Any()).Times(1)
 	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
-	depMgr.EXPECT().GetUpdatableDependencies().Return(deps, nil).Times(1)
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil).Times(1)
 	depMgr.EXPECT().FilterDependencies(deps, false).Return(deps).Times(1)
 	console.EXPECT().PrintDependencies(deps, "Found 1 direct dependencies with available updates:").Times(1)
 
 	app := New(cfg, console, depMgr, sel, upd)
-	err := app.Run()
+	err := app.Run(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func TestRunListNeverPrompts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{List: true, Selective: true, Interactive: true}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", Indirect: false},
+	}
+
+	// The selector and the confirmation are not used
+	console.EXPECT().Header().Times(1)
+	console.EXPECT().PrintReport(gomock.Any()).Times(1)
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil).Times(1)
+	depMgr.EXPECT().FilterDependencies(deps, false).Return(deps).Times(1)
+	console.EXPECT().PrintDependencies(deps, "Found 1 direct dependencies with available updates:").Times(1)
+
+	app := New(cfg, console, depMgr, mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl))
+	err := app.Run(context.Background())
 
 	assert.NoError(t, err)
 }
@@ -127,14 +161,15 @@ func TestRunSelectiveModeCancelled(t *testing.T) {
 
 	// Setup expectations
 	console.EXPECT().Header().Times(1)
+	console.EXPECT().PrintReport(gomock.Any()).Times(1)
 	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
-	depMgr.EXPECT().GetUpdatableDependencies().Return(deps, nil).Times(1)
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil).Times(1)
 	depMgr.EXPECT().FilterDependencies(deps, false).Return(deps).Times(1)
 	sel.EXPECT().Select(deps, false).Return(selector.SelectionResult{Cancelled: true}).Times(1)
 	console.EXPECT().Info("No dependencies selected for update").Times(1)
 
 	app := New(cfg, console, depMgr, sel, upd)
-	err := app.Run()
+	err := app.Run(context.Background())
 
 	assert.NoError(t, err)
 }
@@ -155,13 +190,14 @@ func TestRunSelectiveModeError(t *testing.T) {
 
 	// Setup expectations
 	console.EXPECT().Header().Times(1)
+	console.EXPECT().PrintReport(gomock.Any()).Times(1)
 	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
-	depMgr.EXPECT().GetUpdatableDependencies().Return(deps, nil).Times(1)
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil).Times(1)
 	depMgr.EXPECT().FilterDependencies(deps, false).Return(deps).Times(1)
 	sel.EXPECT().Select(deps, false).Return(selector.SelectionResult{Error: errors.New("selection failed")}).Times(1)
 
 	app := New(cfg, console, depMgr, sel, upd)
-	err := app.Run()
+	err := app.Run(context.Background())
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "dependency selection failed")
@@ -183,15 +219,16 @@ func TestRunInteractiveModeDeclined(t *testing.T) {
 
 	// Setup expectations
 	console.EXPECT().Header().Times(1)
+	console.EXPECT().PrintReport(gomock.Any()).Times(1)
 	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
-	depMgr.EXPECT().GetUpdatableDependencies().Return(deps, nil).Times(1)
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil).Times(1)
 	depMgr.EXPECT().FilterDependencies(deps, false).Return(deps).Times(1)
 	console.EXPECT().PrintDependencies(deps, "Found 1 direct dependencies with available updates:").Times(1)
 	console.EXPECT().Confirm("Do you want to proceed with the update?").Return(false).Times(1)
 	console.EXPECT().Info("Update cancelled").Times(1)
 
 	app := New(cfg, console, depMgr, sel, upd)
-	err := app.Run()
+	err := app.Run(context.Background())
 
 	assert.NoError(t, err)
 }
@@ -212,6 +249,7 @@ func TestRunSuccessfulUpdate(t *testing.T) {
 
 	// Setup expectations - Solo UI con AnyTimes
 	console.EXPECT().Header().Times(1)
+	console.EXPECT().PrintReport(gomock.Any()).Times(1)
 	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
 	console.EXPECT().Info(gomock.Any()).AnyTimes()
 	console.EXPECT().Progress(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
@@ -220,19 +258,65 @@ func TestRunSuccessfulUpdate(t *testing.T) {
 	console.EXPECT().PrintDependencies(gomock.Any(), gomock.Any()).AnyTimes()
 	console.EXPECT().PrintUpdateResult(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
-	depMgr.EXPECT().GetUpdatableDependencies().Return(deps, nil).Times(1)
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil).Times(1)
 	depMgr.EXPECT().FilterDependencies(deps, false).Return(deps).Times(1)
 
+	upd.EXPECT().Snapshot().Return(nil).Times(1)
+
 	// Solo la llamada individual (eliminamos la final)
-	upd.EXPECT().UpdateDependencies([]dependency.Dependency{deps[0]}, false).Return(updater.UpdateResult{Success: true}).Times(1)
-	upd.EXPECT().RunModTidy(false).Return(nil).Times(1)
+	upd.EXPECT().UpdateDependencies(gomock.Any(), []dependency.Dependency{deps[0]}, false).Return(updater.UpdateResult{Success: true}).Times(1)
+	upd.EXPECT().RunModTidy(gomock.Any(), false).Return(nil).Times(1)
 
 	app := New(cfg, console, depMgr, sel, upd)
-	err := app.Run()
+	err := app.Run(context.Background())
 
 	assert.NoError(t, err)
 }
 
+func TestRunBatchUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{Batch: true}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+	sel := mocks.NewMockSelector(ctrl)
+	upd := mocks.NewMockUpdater(ctrl)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+		{Path: "golang.org/x/crypto", Version: "v0.14.0", NewVersion: "v0.17.0", HasUpdate: true},
+	}
+
+	console.EXPECT().Header().Times(1)
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().Info(gomock.Any()).AnyTimes()
+	console.EXPECT().Success(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().PrintDependencies(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().PrintUpdateResult(2, 2, false).Times(1)
+	console.EXPECT().ProgressBar(0, 2, "2 dependencies in one batch").Times(1)
+	console.EXPECT().ProgressBar(2, 2, "2 dependencies in one batch").Times(1)
+
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil).Times(1)
+	depMgr.EXPECT().FilterDependencies(deps, false).Return(deps).Times(1)
+
+	upd.EXPECT().Snapshot().Return(nil).Times(1)
+	// Every dependency is passed to the updater at once
+	upd.EXPECT().UpdateDependencies(gomock.Any(), deps, false).Return(updater.UpdateResult{Updated: deps, Success: true}).Times(1)
+	upd.EXPECT().RunModTidy(gomock.Any(), false).Return(nil).Times(1)
+
+	var report ui.Report
+	console.EXPECT().PrintReport(gomock.Any()).Do(func(r ui.Report) { report = r }).Times(1)
+
+	app := New(cfg, console, depMgr, sel, upd)
+	err := app.Run(context.Background())
+
+	assert.NoError(t, err)
+	require.NotNil(t, report.Update)
+	assert.Equal(t, deps, report.Update.Updated)
+	assert.NotNil(t, report.Update.Failed)
+}
+
 func TestRunUpdateWithErrors(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -250,6 +334,7 @@ func TestRunUpdateWithErrors(t *testing.T) {
 
 	// Setup expectations - Solo UI con AnyTimes
 	console.EXPECT().Header().Times(1)
+	console.EXPECT().PrintReport(gomock.Any()).Times(1)
 	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
 	console.EXPECT().Info(gomock.Any()).AnyTimes()
 	console.EXPECT().Progress(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
@@ -259,16 +344,18 @@ func TestRunUpdateWithErrors(t *testing.T) {
 	console.EXPECT().PrintDependencies(gomock.Any(), gomock.Any()).AnyTimes()
 	console.EXPECT().PrintUpdateResult(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
-	depMgr.EXPECT().GetUpdatableDependencies().Return(deps, nil).Times(1)
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil).Times(1)
 	depMgr.EXPECT().FilterDependencies(deps, false).Return(deps).Times(1)
 
+	upd.EXPECT().Snapshot().Return(nil).Times(1)
+
 	// Solo llamadas individuales (eliminamos la final)
-	upd.EXPECT().UpdateDependencies([]dependency.Dependency{deps[0]}, false).Return(updater.UpdateResult{Success: true}).Times(1)
-	upd.EXPECT().UpdateDependencies([]dependency.Dependency{deps[1]}, false).Return(updater.UpdateResult{Success: false}).Times(1)
-	upd.EXPECT().RunModTidy(false).Return(nil).Times(1)
+	upd.EXPECT().UpdateDependencies(gomock.Any(), []dependency.Dependency{deps[0]}, false).Return(updater.UpdateResult{Success: true}).Times(1)
+	upd.EXPECT().UpdateDependencies(gomock.Any(), []dependency.Dependency{deps[1]}, false).Return(updater.UpdateResult{Success: false}).Times(1)
+	upd.EXPECT().RunModTidy(gomock.Any(), false).Return(nil).Times(1)
 
 	app := New(cfg, console, depMgr, sel, upd)
-	err := app.Run()
+	err := app.Run(context.Background())
 
 	assert.NoError(t, err)
 }
@@ -289,6 +376,7 @@ func TestRunModTidyError(t *testing.T) {
 
 	// Setup expectations - UI calls with flexibility
 	console.EXPECT().Header().Times(1)
+	console.EXPECT().PrintReport(gomock.Any()).Times(1)
 	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
 	console.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
 	console.EXPECT().ProgressBar(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
@@ -298,21 +386,23 @@ func TestRunModTidyError(t *testing.T) {
 
 	console.EXPECT().Warning("go mod tidy failed: %v", errors.New("mod tidy failed")).Times(1)
 
-	depMgr.EXPECT().GetUpdatableDependencies().Return(deps, nil).Times(1)
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil).Times(1)
 	depMgr.EXPECT().FilterDependencies(deps, false).Return(deps).Times(1)
 
+	upd.EXPECT().Snapshot().Return(nil).Times(1)
+
 	// Individual dependency update succeeds
-	upd.EXPECT().UpdateDependencies([]dependency.Dependency{deps[0]}, false).Return(updater.UpdateResult{
+	upd.EXPECT().UpdateDependencies(gomock.Any(), []dependency.Dependency{deps[0]}, false).Return(updater.UpdateResult{
 		Updated: deps,
 		Failed:  []updater.UpdateError{},
 		Success: true,
 	}).Times(1)
 
 	// Mod tidy fails
-	upd.EXPECT().RunModTidy(false).Return(errors.New("mod tidy failed")).Times(1)
+	upd.EXPECT().RunModTidy(gomock.Any(), false).Return(errors.New("mod tidy failed")).Times(1)
 
 	app := New(cfg, console, depMgr, sel, upd)
-	err := app.Run()
+	err := app.Run(context.Background())
 
 	// ⭐ NEW: Should NOT error - resilient behavior continues even if mod tidy fails
 	assert.NoError(t, err)
@@ -334,6 +424,7 @@ func TestRunNoDirectDependenciesWithAllFlag(t *testing.T) {
 
 	// Setup expectations - Solo UI con AnyTimes
 	console.EXPECT().Header().Times(1)
+	console.EXPECT().PrintReport(gomock.Any()).Times(1)
 	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
 	console.EXPECT().Info(gomock.Any()).AnyTimes()
 	console.EXPECT().Progress(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
@@ -342,15 +433,17 @@ func TestRunNoDirectDependenciesWithAllFlag(t *testing.T) {
 	console.EXPECT().PrintDependencies(gomock.Any(), gomock.Any()).AnyTimes()
 	console.EXPECT().PrintUpdateResult(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
-	depMgr.EXPECT().GetUpdatableDependencies().Return(deps, nil).Times(1)
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil).Times(1)
 	depMgr.EXPECT().FilterDependencies(deps, true).Return(deps).Times(1)
 
+	upd.EXPECT().Snapshot().Return(nil).Times(1)
+
 	// Solo llamada individual (eliminamos la final)
-	upd.EXPECT().UpdateDependencies([]dependency.Dependency{deps[0]}, false).Return(updater.UpdateResult{Success: true}).Times(1)
-	upd.EXPECT().RunModTidy(false).Return(nil).Times(1)
+	upd.EXPECT().UpdateDependencies(gomock.Any(), []dependency.Dependency{deps[0]}, false).Return(updater.UpdateResult{Success: true}).Times(1)
+	upd.EXPECT().RunModTidy(gomock.Any(), false).Return(nil).Times(1)
 
 	app := New(cfg, console, depMgr, sel, upd)
-	err := app.Run()
+	err := app.Run(context.Background())
 
 	assert.NoError(t, err)
 }
@@ -374,3 +467,509 @@ func TestNew(t *testing.T) {
 	assert.Equal(t, sel, app.selector)
 	assert.Equal(t, upd, app.updater)
 }
+
+func TestRunReportsUpdateOutcome(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{KeepPartial: true}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+	sel := mocks.NewMockSelector(ctrl)
+	upd := mocks.NewMockUpdater(ctrl)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+		{Path: "github.com/bad/package", Version: "v1.0.0", NewVersion: "v1.1.0", HasUpdate: true},
+	}
+	updateErr := errors.New("command failed")
+
+	console.EXPECT().Header().Times(1)
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().ProgressBar(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().Success(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().Error(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().PrintDependencies(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().PrintUpdateResult(1, 2, true).Times(1)
+
+	var report ui.Report
+	console.EXPECT().PrintReport(gomock.Any()).Do(func(r ui.Report) { report = r }).Times(1)
+
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil).Times(1)
+	depMgr.EXPECT().FilterDependencies(deps, false).Return(deps).Times(1)
+
+	upd.EXPECT().Snapshot().Return(nil).Times(1)
+	upd.EXPECT().UpdateDependencies(gomock.Any(), []dependency.Dependency{deps[0]}, false).Return(updater.UpdateResult{
+		Updated: []dependency.Dependency{deps[0]},
+		Success: true,
+	}).Times(1)
+	upd.EXPECT().UpdateDependencies(gomock.Any(), []dependency.Dependency{deps[1]}, false).Return(updater.UpdateResult{
+		Failed:  []updater.UpdateError{{Dependency: deps[1], Error: updateErr}},
+		Success: false,
+	}).Times(1)
+	upd.EXPECT().RunModTidy(gomock.Any(), false).Return(nil).Times(1)
+
+	app := New(cfg, console, depMgr, sel, upd)
+	err := app.Run(context.Background())
+
+	assert.ErrorIs(t, err, ErrPartialFailure)
+	assert.Equal(t, ui.ModeUpdate, report.Mode)
+	assert.Equal(t, deps, report.Dependencies)
+	require.NotNil(t, report.Update)
+	assert.Equal(t, []dependency.Dependency{deps[0]}, report.Update.Updated)
+	assert.Equal(t, []updater.UpdateError{{Dependency: deps[1], Error: updateErr}}, report.Update.Failed)
+	require.NotNil(t, report.Tidy)
+	assert.NoError(t, report.Tidy.Err)
+	assert.NoError(t, report.Err, "Update failures are outcomes, not aborted runs")
+	assert.Equal(t, ExitPartialFailure, report.ExitCode)
+}
+
+func TestRunListFailOnUpdates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{List: true, FailOnUpdates: true}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+	sel := mocks.NewMockSelector(ctrl)
+	upd := mocks.NewMockUpdater(ctrl)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", Indirect: false},
+	}
+
+	var report ui.Report
+	console.EXPECT().Header().Times(1)
+	console.EXPECT().PrintReport(gomock.Any()).Do(func(r ui.Report) { report = r }).Times(1)
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil).Times(1)
+	depMgr.EXPECT().FilterDependencies(deps, false).Return(deps).Times(1)
+	console.EXPECT().PrintDependencies(deps, "Found 1 direct dependencies with available updates:").Times(1)
+
+	app := New(cfg, console, depMgr, sel, upd)
+	err := app.Run(context.Background())
+
+	assert.ErrorIs(t, err, ErrUpdatesAvailable)
+	assert.Equal(t, ExitUpdatesAvailable, report.ExitCode)
+	assert.Equal(t, ui.ModeList, report.Mode)
+}
+
+func TestRunListFailOnUpdatesUpToDate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{List: true, FailOnUpdates: true}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+	sel := mocks.NewMockSelector(ctrl)
+	upd := mocks.NewMockUpdater(ctrl)
+
+	console.EXPECT().Header().Times(1)
+	console.EXPECT().PrintReport(gomock.Any()).Times(1)
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return([]dependency.Dependency{}, nil).Times(1)
+	console.EXPECT().Info("All dependencies are up to date! 🎉").Times(1)
+
+	app := New(cfg, console, depMgr, sel, upd)
+	err := app.Run(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func TestRunUpdateTotalFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+	sel := mocks.NewMockSelector(ctrl)
+	upd := mocks.NewMockUpdater(ctrl)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/bad/package", Version: "v1.0.0", NewVersion: "v1.1.0", HasUpdate: true},
+	}
+
+	console.EXPECT().Header().Times(1)
+	console.EXPECT().PrintReport(gomock.Any()).Times(1)
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().ProgressBar(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().Success(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().Error(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().PrintDependencies(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().PrintUpdateResult(0, 1, true).Times(1)
+	console.EXPECT().Warning("Rolling back go.mod and go.sum to their state before the update...").Times(1)
+
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil).Times(1)
+	depMgr.EXPECT().FilterDependencies(deps, false).Return(deps).Times(1)
+
+	upd.EXPECT().Snapshot().Return(nil).Times(1)
+	upd.EXPECT().UpdateDependencies(gomock.Any(), deps, false).Return(updater.UpdateResult{
+		Failed:  []updater.UpdateError{{Dependency: deps[0], Error: errors.New("command failed")}},
+		Success: false,
+	}).Times(1)
+	upd.EXPECT().Rollback().Return(nil).Times(1)
+
+	app := New(cfg, console, depMgr, sel, upd)
+	err := app.Run(context.Background())
+
+	assert.ErrorIs(t, err, ErrTotalFailure)
+}
+
+func TestRunListWithPatchPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{List: true, Policy: dependency.PolicyPatch}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+	sel := mocks.NewMockSelector(ctrl)
+	upd := mocks.NewMockUpdater(ctrl)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.0", NewVersion: "v1.10.0", HasUpdate: true},
+		{Path: "github.com/stretchr/testify", Version: "v1.8.4", NewVersion: "v1.9.0", HasUpdate: true},
+	}
+	patched := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.0", NewVersion: "v1.9.1", HasUpdate: true},
+	}
+
+	console.EXPECT().Header().Times(1)
+	console.EXPECT().PrintReport(gomock.Any()).Times(1)
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil).Times(1)
+	depMgr.EXPECT().GetAvailableVersions(gomock.Any(), []string{"github.com/gin-gonic/gin", "github.com/stretchr/testify"}).Return(map[string][]string{
+		"github.com/gin-gonic/gin":    {"v1.9.0", "v1.9.1", "v1.10.0"},
+		"github.com/stretchr/testify": {"v1.8.4", "v1.9.0"},
+	}, nil).Times(1)
+	depMgr.EXPECT().FilterDependencies(patched, false).Return(patched).Times(1)
+	console.EXPECT().PrintDependencies(patched, "Found 1 direct dependencies with available updates:").Times(1)
+
+	app := New(cfg, console, depMgr, sel, upd)
+	err := app.Run(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func TestRunPolicyVersionsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{Policy: dependency.PolicyMinor}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+	sel := mocks.NewMockSelector(ctrl)
+	upd := mocks.NewMockUpdater(ctrl)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.0", NewVersion: "v1.10.0", HasUpdate: true},
+	}
+
+	console.EXPECT().Header().Times(1)
+	console.EXPECT().PrintReport(gomock.Any()).Times(1)
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil).Times(1)
+	depMgr.EXPECT().GetAvailableVersions(gomock.Any(), []string{"github.com/gin-gonic/gin"}).Return(nil, errors.New("failed to list versions")).Times(1)
+
+	app := New(cfg, console, depMgr, sel, upd)
+	err := app.Run(context.Background())
+
+	assert.ErrorContains(t, err, "failed to list versions")
+}
+
+func TestRunListDiscoverMajors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{List: true, DiscoverMajors: true}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+	sel := mocks.NewMockSelector(ctrl)
+	upd := mocks.NewMockUpdater(ctrl)
+
+	required := []dependency.Dependency{
+		{Path: "github.com/foo/bar/v2", Version: "v2.5.0"},
+		{Path: "golang.org/x/crypto", Version: "v0.14.0", Indirect: true},
+	}
+	major := dependency.Dependency{
+		Path: "github.com/foo/bar/v2", Version: "v2.5.0", NewPath: "github.com/foo/bar/v4", NewVersion: "v4.0.1", HasUpdate: true,
+	}
+	expected := []dependency.Dependency{major}
+
+	console.EXPECT().Header().Times(1)
+	console.EXPECT().PrintReport(gomock.Any()).Times(1)
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(nil, nil).Times(1)
+	depMgr.EXPECT().GetDependencies().Return(required, nil).Times(1)
+	depMgr.EXPECT().GetMajorUpgrades(gomock.Any(), []dependency.Dependency{required[0]}).Return(expected, nil).Times(1)
+	depMgr.EXPECT().FilterDependencies(expected, false).Return(expected).Times(1)
+	console.EXPECT().PrintDependencies(expected, "Found 1 direct dependencies with available updates:").Times(1)
+
+	app := New(cfg, console, depMgr, sel, upd)
+	err := app.Run(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func TestRunListWithConfigRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{List: true, Rules: []config.Rule{
+		{Module: "github.com/aws/*", Ignore: true},
+		{Module: "golang.org/x/crypto", Pin: "<v0.18.0"},
+		{Module: "github.com/gin-gonic/gin", Allow: "patch"},
+	}}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+	sel := mocks.NewMockSelector(ctrl)
+	upd := mocks.NewMockUpdater(ctrl)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/aws/aws-sdk-go", Version: "v1.44.0", NewVersion: "v1.50.0", HasUpdate: true},
+		{Path: "golang.org/x/crypto", Version: "v0.14.0", NewVersion: "v0.20.0", HasUpdate: true},
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.0", NewVersion: "v1.10.0", HasUpdate: true},
+		{Path: "github.com/stretchr/testify", Version: "v1.8.4", NewVersion: "v1.9.0", HasUpdate: true},
+	}
+	expected := []dependency.Dependency{
+		{Path: "golang.org/x/crypto", Version: "v0.14.0", NewVersion: "v0.17.0", HasUpdate: true},
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.0", NewVersion: "v1.9.1", HasUpdate: true},
+		deps[3],
+	}
+
+	console.EXPECT().Header().Times(1)
+	console.EXPECT().PrintReport(gomock.Any()).Times(1)
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil).Times(1)
+	depMgr.EXPECT().GetAvailableVersions(gomock.Any(), []string{"golang.org/x/crypto", "github.com/gin-gonic/gin"}).Return(map[string][]string{
+		"golang.org/x/crypto":      {"v0.14.0", "v0.17.0", "v0.18.0", "v0.20.0"},
+		"github.com/gin-gonic/gin": {"v1.9.0", "v1.9.1", "v1.10.0"},
+	}, nil).Times(1)
+	depMgr.EXPECT().FilterDependencies(expected, false).Return(expected).Times(1)
+	console.EXPECT().PrintDependencies(expected, "Found 3 direct dependencies with available updates:").Times(1)
+
+	app := New(cfg, console, depMgr, sel, upd)
+	err := app.Run(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func TestRunListDiscoverMajorsWithConfigRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{List: true, DiscoverMajors: true, Rules: []config.Rule{
+		{Module: "github.com/foo/bar/*", Allow: "minor"},
+		{Module: "github.com/baz/qux", Pin: "<v3.0.0"},
+	}}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+	sel := mocks.NewMockSelector(ctrl)
+	upd := mocks.NewMockUpdater(ctrl)
+
+	required := []dependency.Dependency{
+		{Path: "github.com/foo/bar/v2", Version: "v2.5.0"},
+		{Path: "github.com/baz/qux", Version: "v1.0.0"},
+	}
+	upgrades := []dependency.Dependency{
+		{Path: "github.com/baz/qux", Version: "v1.0.0", NewPath: "github.com/baz/qux/v3", NewVersion: "v3.0.0", HasUpdate: true},
+	}
+
+	console.EXPECT().Header().Times(1)
+	console.EXPECT().PrintReport(gomock.Any()).Times(1)
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(nil, nil).Times(1)
+	depMgr.EXPECT().GetDependencies().Return(required, nil).Times(1)
+	depMgr.EXPECT().GetMajorUpgrades(gomock.Any(), []dependency.Dependency{required[1]}).Return(upgrades, nil).Times(1)
+	console.EXPECT().Info("All dependencies are up to date! 🎉").Times(1)
+
+	app := New(cfg, console, depMgr, sel, upd)
+	err := app.Run(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func TestRunUpdateRollsBackPartialFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+	sel := mocks.NewMockSelector(ctrl)
+	upd := mocks.NewMockUpdater(ctrl)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+		{Path: "github.com/bad/package", Version: "v1.0.0", NewVersion: "v1.1.0", HasUpdate: true},
+	}
+
+	console.EXPECT().Header().Times(1)
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().ProgressBar(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().Error(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().PrintDependencies(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().PrintUpdateResult(1, 2, true).Times(1)
+	console.EXPECT().Warning("Rolling back go.mod and go.sum to their state before the update...").Times(1)
+	console.EXPECT().Success("Rollback completed, no dependencies were changed").Times(1)
+
+	var report ui.Report
+	console.EXPECT().PrintReport(gomock.Any()).Do(func(r ui.Report) { report = r }).Times(1)
+
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil).Times(1)
+	depMgr.EXPECT().FilterDependencies(deps, false).Return(deps).Times(1)
+
+	gomock.InOrder(
+		upd.EXPECT().Snapshot().Return(nil).Times(1),
+		upd.EXPECT().UpdateDependencies(gomock.Any(), []dependency.Dependency{deps[0]}, false).Return(updater.UpdateResult{
+			Updated: []dependency.Dependency{deps[0]},
+			Success: true,
+		}).Times(1),
+		upd.EXPECT().UpdateDependencies(gomock.Any(), []dependency.Dependency{deps[1]}, false).Return(updater.UpdateResult{
+			Failed:  []updater.UpdateError{{Dependency: deps[1], Error: errors.New("command failed")}},
+			Success: false,
+		}).Times(1),
+		upd.EXPECT().Rollback().Return(nil).Times(1),
+	)
+
+	app := New(cfg, console, depMgr, sel, upd)
+	err := app.Run(context.Background())
+
+	assert.ErrorIs(t, err, ErrPartialFailure)
+	assert.True(t, report.RolledBack)
+	assert.Nil(t, report.Tidy, "go mod tidy must not run on restored files")
+}
+
+func TestRunUpdateSnapshotError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+	sel := mocks.NewMockSelector(ctrl)
+	upd := mocks.NewMockUpdater(ctrl)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+	}
+
+	console.EXPECT().Header().Times(1)
+	console.EXPECT().PrintReport(gomock.Any()).Times(1)
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().PrintDependencies(gomock.Any(), gomock.Any()).AnyTimes()
+
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil).Times(1)
+	depMgr.EXPECT().FilterDependencies(deps, false).Return(deps).Times(1)
+	upd.EXPECT().Snapshot().Return(errors.New("disk full")).Times(1)
+
+	app := New(cfg, console, depMgr, sel, upd)
+	err := app.Run(context.Background())
+
+	assert.EqualError(t, err, "saving snapshot of go.mod and go.sum: disk full")
+}
+
+func TestRunRollback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{Rollback: true}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+	sel := mocks.NewMockSelector(ctrl)
+	upd := mocks.NewMockUpdater(ctrl)
+
+	var report ui.Report
+	console.EXPECT().Header().Times(1)
+	console.EXPECT().PrintReport(gomock.Any()).Do(func(r ui.Report) { report = r }).Times(1)
+	console.EXPECT().Info("Restoring go.mod and go.sum from the last run...").Times(1)
+	console.EXPECT().Success("Rollback completed").Times(1)
+	upd.EXPECT().Rollback().Return(nil).Times(1)
+
+	app := New(cfg, console, depMgr, sel, upd)
+	err := app.Run(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, ui.ModeRollback, report.Mode)
+	assert.True(t, report.RolledBack)
+}
+
+func TestRunRollbackWithoutSnapshot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{Rollback: true}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+	sel := mocks.NewMockSelector(ctrl)
+	upd := mocks.NewMockUpdater(ctrl)
+
+	console.EXPECT().Header().Times(1)
+	console.EXPECT().PrintReport(gomock.Any()).Times(1)
+	console.EXPECT().Info(gomock.Any()).AnyTimes()
+	upd.EXPECT().Rollback().Return(snapshot.ErrNoSnapshot).Times(1)
+
+	app := New(cfg, console, depMgr, sel, upd)
+	err := app.Run(context.Background())
+
+	assert.ErrorIs(t, err, snapshot.ErrNoSnapshot)
+	assert.Equal(t, ExitError, ExitCode(err))
+}
+
+func TestRunVerifyRevertsFailingUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{Verify: true}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+	sel := mocks.NewMockSelector(ctrl)
+	upd := mocks.NewMockUpdater(ctrl)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+		{Path: "github.com/bad/package", Version: "v1.0.0", NewVersion: "v1.1.0", HasUpdate: true},
+	}
+	verifyErr := fmt.Errorf("%w: go test ./...: exit status 1", updater.ErrVerificationFailed)
+
+	console.EXPECT().Header().Times(1)
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().ProgressBar(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().PrintDependencies(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().PrintUpdateResult(1, 2, true).Times(1)
+	console.EXPECT().Error("Reverted %s %s: %v", "github.com/bad/package", "v1.1.0", verifyErr).Times(1)
+	console.EXPECT().Success("%d updates passed '%s'", 1, config.DefaultVerifyCommand).Times(1)
+	console.EXPECT().Success(gomock.Any()).AnyTimes()
+
+	var report ui.Report
+	console.EXPECT().PrintReport(gomock.Any()).Do(func(r ui.Report) { report = r }).Times(1)
+
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil).Times(1)
+	depMgr.EXPECT().FilterDependencies(deps, false).Return(deps).Times(1)
+
+	upd.EXPECT().Snapshot().Return(nil).Times(1)
+	upd.EXPECT().UpdateDependencies(gomock.Any(), []dependency.Dependency{deps[0]}, false).Return(updater.UpdateResult{
+		Updated:  []dependency.Dependency{deps[0]},
+		Verified: []dependency.Dependency{deps[0]},
+		Success:  true,
+	}).Times(1)
+	upd.EXPECT().UpdateDependencies(gomock.Any(), []dependency.Dependency{deps[1]}, false).Return(updater.UpdateResult{
+		Reverted: []updater.UpdateError{{Dependency: deps[1], Error: verifyErr}},
+		Success:  false,
+	}).Times(1)
+	// Reverted updates are already undone, so the verified ones are kept and tidied
+	upd.EXPECT().RunModTidy(gomock.Any(), false).Return(nil).Times(1)
+
+	app := New(cfg, console, depMgr, sel, upd)
+	err := app.Run(context.Background())
+
+	assert.ErrorIs(t, err, ErrPartialFailure)
+	assert.False(t, report.RolledBack)
+	require.NotNil(t, report.Update)
+	assert.Equal(t, []dependency.Dependency{deps[0]}, report.Update.Verified)
+	assert.Equal(t, []updater.UpdateError{{Dependency: deps[1], Error: verifyErr}}, report.Update.Reverted)
+}
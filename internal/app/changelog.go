@@ -0,0 +1,30 @@
+package app
+
+import (
+	"context"
+	"errors"
+
+	"goup/internal/changelog"
+	"goup/internal/dependency"
+)
+
+// attachChangelogs fetches the changelog diff of every dependency. Modules
+// whose changelog cannot be read are shown without one.
+func (a *App) attachChangelogs(ctx context.Context, deps []dependency.Dependency) []dependency.Dependency {
+	if a.sources.Changelogs == nil {
+		a.console.Warning("Changelogs are not available, showing the updates without them")
+		return deps
+	}
+
+	return a.annotate(ctx, deps, "changelog", func(ctx context.Context, dep *dependency.Dependency) {
+		changes, err := a.sources.Changelogs.Changes(ctx, *dep)
+		switch {
+		case errors.Is(err, changelog.ErrNoChangelog):
+			a.console.Debug("%s has no changelog", dep.TargetPath())
+		case err != nil:
+			a.console.Warning("Could not read the changelog of %s: %v", dep.TargetPath(), err)
+		default:
+			dep.Changelog = changes
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"goup/internal/changelog"
+	"goup/internal/config"
+	"goup/internal/dependency"
+	"goup/internal/mocks"
+	"goup/internal/selector"
+	"goup/internal/ui"
+)
+
+// fakeChangelogs returns canned changelog diffs, or errors, by module path
+type fakeChangelogs map[string]any
+
+func (f fakeChangelogs) Changes(ctx context.Context, dep dependency.Dependency) (string, error) {
+	switch v := f[dep.TargetPath()].(type) {
+	case string:
+		return v, nil
+	case error:
+		return "", v
+	default:
+		return "", fmt.Errorf("%s: %w", dep.TargetPath(), changelog.ErrNoChangelog)
+	}
+}
+
+func TestRunListWithChangelog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{List: true, Changelog: true}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+		{Path: "github.com/foo/bar", Version: "v1.0.0", NewVersion: "v1.1.0", HasUpdate: true},
+		{Path: "golang.org/x/crypto", Version: "v0.14.0", NewVersion: "v0.17.0", HasUpdate: true},
+	}
+	changelogs := fakeChangelogs{
+		"github.com/gin-gonic/gin": "+## v1.9.2\n",
+		"golang.org/x/crypto":      errors.New("downloading golang.org/x/crypto@v0.17.0: 410 Gone"),
+	}
+
+	withChangelog := append([]dependency.Dependency(nil), deps...)
+	withChangelog[0].Changelog = "+## v1.9.2\n"
+
+	var report ui.Report
+	console.EXPECT().Header()
+	console.EXPECT().PrintReport(gomock.Any()).Do(func(r ui.Report) { report = r })
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().ProgressBar(gomock.Any(), 3, gomock.Any()).Times(4)
+	console.EXPECT().Warning("Could not read the changelog of %s: %v", "golang.org/x/crypto", gomock.Any())
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil)
+	depMgr.EXPECT().FilterDependencies(deps, false).Return(deps)
+	gomock.InOrder(
+		console.EXPECT().PrintDependencies(withChangelog, "Found 3 direct dependencies with available updates:"),
+		console.EXPECT().PrintChangelog(withChangelog[0]),
+		console.EXPECT().PrintChangelog(withChangelog[1]),
+		console.EXPECT().PrintChangelog(withChangelog[2]),
+	)
+
+	app := NewWithSources(cfg, console, depMgr, mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl), Sources{Changelogs: changelogs})
+	err := app.Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, withChangelog, report.Dependencies)
+	assert.Empty(t, deps[0].Changelog, "The dependencies of the manager are not modified")
+}
+
+func TestRunSelectiveWithChangelog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{Selective: true, Changelog: true}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+	sel := mocks.NewMockSelector(ctrl)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/foo/bar/v2", Version: "v2.5.0", NewPath: "github.com/foo/bar/v3", NewVersion: "v3.0.0", HasUpdate: true},
+	}
+	withChangelog := []dependency.Dependency{deps[0]}
+	withChangelog[0].Changelog = "+## v3.0.0\n"
+
+	console.EXPECT().Header()
+	console.EXPECT().PrintReport(gomock.Any())
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().ProgressBar(gomock.Any(), 1, gomock.Any()).Times(2)
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil)
+	depMgr.EXPECT().FilterDependencies(deps, false).Return(deps)
+	// The selector shows the changelogs of the selection before confirming
+	sel.EXPECT().Select(withChangelog, false).Return(selector.SelectionResult{Cancelled: true})
+	console.EXPECT().Info("No dependencies selected for update")
+
+	app := NewWithSources(cfg, console, depMgr, sel, mocks.NewMockUpdater(ctrl), Sources{Changelogs: fakeChangelogs{"github.com/foo/bar/v3": "+## v3.0.0\n"}})
+	err := app.Run(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func TestRunChangelogUnavailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{List: true, Changelog: true}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+	}
+
+	console.EXPECT().Header()
+	console.EXPECT().PrintReport(gomock.Any())
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().Warning("Changelogs are not available, showing the updates without them")
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil)
+	depMgr.EXPECT().FilterDependencies(deps, false).Return(deps)
+	console.EXPECT().PrintDependencies(deps, gomock.Any())
+	console.EXPECT().PrintChangelog(deps[0])
+
+	app := New(cfg, console, depMgr, mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl))
+	err := app.Run(context.Background())
+
+	assert.NoError(t, err)
+}
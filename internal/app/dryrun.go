@@ -0,0 +1,48 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"goup/internal/dependency"
+	"goup/internal/ui"
+)
+
+// previewUpdate applies the selected updates to a copy of go.mod and go.sum
+// and shows how the files would change. The module is left untouched.
+func (a *App) previewUpdate(ctx context.Context, deps []dependency.Dependency, report *ui.Report) error {
+	a.console.Info("Applying %d updates to a copy of go.mod and go.sum...", len(deps))
+
+	preview, err := a.updater.Preview(ctx, deps, a.config.Verbose)
+	if err != nil {
+		return fmt.Errorf("dry run failed: %w", err)
+	}
+	report.Update = &preview.Result
+	report.ModDiff = preview.Diff
+
+	for _, failure := range preview.Result.Failed {
+		a.console.Error("Failed to update %s: %v", failure.Dependency.Path, failure.Error)
+	}
+
+	if preview.TidySkipped {
+		// Without the rewritten imports tidy would go back to the old major versions
+		a.console.Warning("go mod tidy was skipped: the imports of major upgrades are not rewritten in a dry run")
+	} else {
+		report.Tidy = &ui.TidyResult{Err: preview.Tidy}
+		if preview.Tidy != nil {
+			a.console.Warning("go mod tidy failed: %v", preview.Tidy)
+		}
+	}
+
+	a.console.PrintModDiff(preview.Diff)
+	a.console.Info("Dry run: go.mod and go.sum were left untouched")
+
+	switch {
+	case len(preview.Result.Failed) == 0:
+		return nil
+	case len(preview.Result.Updated) == 0:
+		return ErrTotalFailure
+	default:
+		return ErrPartialFailure
+	}
+}
@@ -0,0 +1,151 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"goup/internal/config"
+	"goup/internal/dependency"
+	"goup/internal/mocks"
+	"goup/internal/ui"
+	"goup/internal/updater"
+)
+
+const ginDiff = "--- go.mod\n+++ go.mod\n@@ -3 +3 @@\n-require github.com/gin-gonic/gin v1.9.1\n+require github.com/gin-gonic/gin v1.9.2\n"
+
+func TestRunDryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{DryRun: true, Interactive: true}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+	upd := mocks.NewMockUpdater(ctrl)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+	}
+	result := updater.UpdateResult{Updated: deps, Success: true}
+
+	var report ui.Report
+	console.EXPECT().Header()
+	console.EXPECT().PrintReport(gomock.Any()).Do(func(r ui.Report) { report = r })
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil)
+	depMgr.EXPECT().FilterDependencies(deps, false).Return(deps)
+	console.EXPECT().PrintDependencies(deps, gomock.Any())
+	// No snapshot, no confirmation and no update of the module
+	upd.EXPECT().Preview(gomock.Any(), deps, false).Return(updater.Preview{Result: result, Diff: ginDiff}, nil)
+	console.EXPECT().PrintModDiff(ginDiff)
+
+	app := New(cfg, console, depMgr, mocks.NewMockSelector(ctrl), upd)
+	err := app.Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, ui.ModeDryRun, report.Mode)
+	assert.Equal(t, &result, report.Update)
+	assert.Equal(t, &ui.TidyResult{}, report.Tidy)
+	assert.Equal(t, ginDiff, report.ModDiff)
+}
+
+func TestRunDryRunOutcomes(t *testing.T) {
+	gin := dependency.Dependency{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true}
+	bar := dependency.Dependency{Path: "github.com/foo/bar/v2", Version: "v2.5.0", NewPath: "github.com/foo/bar/v4", NewVersion: "v4.0.1", HasUpdate: true}
+	failure := updater.UpdateError{Dependency: gin, Error: errors.New("exit status 1")}
+
+	tests := []struct {
+		name     string
+		deps     []dependency.Dependency
+		preview  updater.Preview
+		err      error
+		console  func(*mocks.MockConsole)
+		wantTidy *ui.TidyResult
+		wantErr  error
+	}{
+		{
+			name: "major upgrade skips tidy",
+			deps: []dependency.Dependency{bar},
+			preview: updater.Preview{
+				Result:      updater.UpdateResult{Updated: []dependency.Dependency{bar}, Success: true},
+				TidySkipped: true,
+			},
+			console: func(c *mocks.MockConsole) {
+				c.EXPECT().Warning("go mod tidy was skipped: the imports of major upgrades are not rewritten in a dry run")
+				c.EXPECT().PrintModDiff("")
+			},
+		},
+		{
+			name: "failed update",
+			deps: []dependency.Dependency{gin},
+			preview: updater.Preview{
+				Result: updater.UpdateResult{Failed: []updater.UpdateError{failure}},
+			},
+			console: func(c *mocks.MockConsole) {
+				c.EXPECT().Error("Failed to update %s: %v", gin.Path, failure.Error)
+				c.EXPECT().PrintModDiff("")
+			},
+			wantTidy: &ui.TidyResult{},
+			wantErr:  ErrTotalFailure,
+		},
+		{
+			name: "tidy fails",
+			deps: []dependency.Dependency{gin},
+			preview: updater.Preview{
+				Result: updater.UpdateResult{Updated: []dependency.Dependency{gin}, Success: true},
+				Tidy:   errors.New("missing go.sum entry"),
+				Diff:   ginDiff,
+			},
+			console: func(c *mocks.MockConsole) {
+				c.EXPECT().Warning("go mod tidy failed: %v", errors.New("missing go.sum entry"))
+				c.EXPECT().PrintModDiff(ginDiff)
+			},
+			wantTidy: &ui.TidyResult{Err: errors.New("missing go.sum entry")},
+		},
+		{
+			name:    "workspace",
+			deps:    []dependency.Dependency{gin},
+			err:     updater.ErrDryRunWorkspace,
+			console: func(c *mocks.MockConsole) {},
+			wantErr: updater.ErrDryRunWorkspace,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			cfg := &config.Config{DryRun: true}
+			console := mocks.NewMockConsole(ctrl)
+			depMgr := mocks.NewMockManager(ctrl)
+			upd := mocks.NewMockUpdater(ctrl)
+
+			var report ui.Report
+			console.EXPECT().Header()
+			console.EXPECT().PrintReport(gomock.Any()).Do(func(r ui.Report) { report = r })
+			console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+			console.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+			depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(tt.deps, nil)
+			depMgr.EXPECT().FilterDependencies(tt.deps, false).Return(tt.deps)
+			console.EXPECT().PrintDependencies(tt.deps, gomock.Any())
+			upd.EXPECT().Preview(gomock.Any(), tt.deps, false).Return(tt.preview, tt.err)
+			tt.console(console)
+
+			app := New(cfg, console, depMgr, mocks.NewMockSelector(ctrl), upd)
+			err := app.Run(context.Background())
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantTidy, report.Tidy)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"goup/internal/dependency"
+	"goup/internal/ui"
+)
+
+// defaultExcludeReason is the comment of an exclude directive added without --reason
+const defaultExcludeReason = "excluded with goup exclude"
+
+// excludeVersion adds the exclude directive of 'goup exclude' to go.mod, so
+// the version is never chosen again, neither by goup nor by the go command.
+// Like an update, it can be undone with --rollback.
+// The go command moves a requirement on an excluded version to the next
+// version, so go mod tidy runs when go.mod requires it.
+func (a *App) excludeVersion(ctx context.Context, report *ui.Report) error {
+	path, version, _ := strings.Cut(a.config.ExcludeModule, "@")
+	if version == "" {
+		return fmt.Errorf("goup exclude requires a module version, e.g. %s@v1.2.3", path)
+	}
+	report.Dependencies = []dependency.Dependency{{Path: path, Version: version}}
+
+	reason := a.config.ExcludeReason
+	if reason == "" {
+		reason = defaultExcludeReason
+	}
+
+	added, err := a.updater.Exclude(path, version, reason)
+	if err != nil {
+		return fmt.Errorf("excluding %s@%s: %w", path, version, err)
+	}
+	if !added {
+		a.console.Info("%s %s is already excluded", path, version)
+		return nil
+	}
+	a.console.Success("Excluded %s %s from go.mod", path, version)
+
+	required, err := a.depMgr.GetDependencies()
+	if err != nil {
+		return err
+	}
+	for _, dep := range required {
+		if dep.Path != path || dep.Version != version {
+			continue
+		}
+
+		a.console.Warning("go.mod requires the excluded version, %s moves to the next one", path)
+		err := a.runModTidy(ctx)
+		report.Tidy = &ui.TidyResult{Err: err}
+		if err != nil {
+			a.console.Warning("go mod tidy failed: %v", err)
+		} else {
+			a.console.Success("go mod tidy completed")
+		}
+	}
+
+	return nil
+}
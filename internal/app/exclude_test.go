@@ -0,0 +1,106 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"goup/internal/config"
+	"goup/internal/dependency"
+	"goup/internal/mocks"
+	"goup/internal/ui"
+)
+
+func TestRunExclude(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      *config.Config
+		required []dependency.Dependency
+		expect   func(*mocks.MockConsole, *mocks.MockUpdater)
+		wantTidy bool
+	}{
+		{
+			name:     "version not required",
+			cfg:      &config.Config{ExcludeModule: "example.com/lib@v1.2.0", ExcludeReason: "Panics on startup"},
+			required: []dependency.Dependency{{Path: "example.com/lib", Version: "v1.1.0"}},
+			expect: func(console *mocks.MockConsole, upd *mocks.MockUpdater) {
+				upd.EXPECT().Exclude("example.com/lib", "v1.2.0", "Panics on startup").Return(true, nil)
+				console.EXPECT().Success("Excluded %s %s from go.mod", "example.com/lib", "v1.2.0")
+			},
+		},
+		{
+			name:     "required version",
+			cfg:      &config.Config{ExcludeModule: "example.com/lib@v1.2.0"},
+			required: []dependency.Dependency{{Path: "example.com/lib", Version: "v1.2.0"}},
+			expect: func(console *mocks.MockConsole, upd *mocks.MockUpdater) {
+				upd.EXPECT().Exclude("example.com/lib", "v1.2.0", defaultExcludeReason).Return(true, nil)
+				console.EXPECT().Success("Excluded %s %s from go.mod", "example.com/lib", "v1.2.0")
+				console.EXPECT().Warning("go.mod requires the excluded version, %s moves to the next one", "example.com/lib")
+				console.EXPECT().Info("Running go mod tidy...")
+				upd.EXPECT().RunModTidy(gomock.Any(), false)
+				console.EXPECT().Success("go mod tidy completed")
+			},
+			wantTidy: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			console := mocks.NewMockConsole(ctrl)
+			depMgr := mocks.NewMockManager(ctrl)
+			upd := mocks.NewMockUpdater(ctrl)
+
+			var report ui.Report
+			console.EXPECT().Header()
+			console.EXPECT().PrintReport(gomock.Any()).Do(func(r ui.Report) { report = r })
+			depMgr.EXPECT().GetDependencies().Return(tt.required, nil)
+			tt.expect(console, upd)
+
+			err := New(tt.cfg, console, depMgr, mocks.NewMockSelector(ctrl), upd).Run(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, ui.ModeExclude, report.Mode)
+			assert.Equal(t, []dependency.Dependency{{Path: "example.com/lib", Version: "v1.2.0"}}, report.Dependencies)
+			assert.Equal(t, tt.wantTidy, report.Tidy != nil)
+		})
+	}
+}
+
+func TestRunExcludeAlreadyExcluded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{ExcludeModule: "example.com/lib@v1.2.0"}
+	console := mocks.NewMockConsole(ctrl)
+	upd := mocks.NewMockUpdater(ctrl)
+
+	console.EXPECT().Header()
+	console.EXPECT().PrintReport(gomock.Any())
+	upd.EXPECT().Exclude("example.com/lib", "v1.2.0", defaultExcludeReason).Return(false, nil)
+	console.EXPECT().Info("%s %s is already excluded", "example.com/lib", "v1.2.0")
+
+	err := New(cfg, console, mocks.NewMockManager(ctrl), mocks.NewMockSelector(ctrl), upd).Run(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func TestRunExcludeWithoutVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{ExcludeModule: "example.com/lib"}
+	console := mocks.NewMockConsole(ctrl)
+
+	console.EXPECT().Header()
+	console.EXPECT().PrintReport(gomock.Any())
+
+	err := New(cfg, console, mocks.NewMockManager(ctrl), mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl)).Run(context.Background())
+
+	assert.EqualError(t, err, "goup exclude requires a module version, e.g. example.com/lib@v1.2.3")
+}
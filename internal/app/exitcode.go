@@ -0,0 +1,39 @@
+package app
+
+import "errors"
+
+// Process exit codes returned by goup
+const (
+	ExitOK               = 0   // Run completed (or nothing to update)
+	ExitError            = 1   // goup could not complete the run
+	ExitUpdatesAvailable = 2   // Updates are available and --fail-on-updates was set
+	ExitPartialFailure   = 3   // Some dependencies failed to update
+	ExitTotalFailure     = 4   // Every dependency failed to update
+	ExitInterrupted      = 130 // The run was stopped with Ctrl-C
+)
+
+// Outcomes reported by Run that are not failures of goup itself
+var (
+	ErrUpdatesAvailable = errors.New("dependency updates are available")
+	ErrPartialFailure   = errors.New("some dependencies failed to update")
+	ErrTotalFailure     = errors.New("all dependencies failed to update")
+	ErrInterrupted      = errors.New("interrupted")
+)
+
+// ExitCode maps an error returned by Run to the process exit code
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, ErrUpdatesAvailable):
+		return ExitUpdatesAvailable
+	case errors.Is(err, ErrPartialFailure):
+		return ExitPartialFailure
+	case errors.Is(err, ErrTotalFailure):
+		return ExitTotalFailure
+	case errors.Is(err, ErrInterrupted):
+		return ExitInterrupted
+	default:
+		return ExitError
+	}
+}
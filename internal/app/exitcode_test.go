@@ -0,0 +1,31 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{name: "success", err: nil, expected: ExitOK},
+		{name: "updates available", err: ErrUpdatesAvailable, expected: ExitUpdatesAvailable},
+		{name: "partial failure", err: ErrPartialFailure, expected: ExitPartialFailure},
+		{name: "total failure", err: ErrTotalFailure, expected: ExitTotalFailure},
+		{name: "wrapped outcome", err: fmt.Errorf("run: %w", ErrPartialFailure), expected: ExitPartialFailure},
+		{name: "interrupted", err: ErrInterrupted, expected: ExitInterrupted},
+		{name: "application error", err: errors.New("failed to check for updates"), expected: ExitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ExitCode(tt.err))
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package app
+
+import (
+	"context"
+
+	"goup/internal/dependency"
+)
+
+// attachImpact records which packages of the main module use every
+// dependency. The updates are shown without it if the packages cannot be
+// listed.
+func (a *App) attachImpact(ctx context.Context, deps []dependency.Dependency) []dependency.Dependency {
+	if a.sources.Impacts == nil {
+		a.console.Warning("Impact analysis is not available, showing the updates without it")
+		return deps
+	}
+
+	var impacts map[string]*dependency.Impact
+	var err error
+	a.withCommandTimeout(ctx, func(ctx context.Context) {
+		impacts, err = a.sources.Impacts.Analyze(ctx)
+	})
+	if err != nil {
+		a.console.Warning("Could not analyze the impact of the updates: %v", err)
+		return deps
+	}
+
+	result := make([]dependency.Dependency, len(deps))
+	for i, dep := range deps {
+		dep.Impact = impacts[dep.Path]
+		if dep.Impact == nil {
+			// Required, but no package of the main module imports it
+			dep.Impact = &dependency.Impact{}
+		}
+		result[i] = dep
+	}
+	return result
+}
@@ -0,0 +1,112 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"goup/internal/config"
+	"goup/internal/dependency"
+	"goup/internal/mocks"
+)
+
+// fakeImpacts returns a canned analysis
+type fakeImpacts struct {
+	impacts map[string]*dependency.Impact
+	err     error
+}
+
+func (f fakeImpacts) Analyze(ctx context.Context) (map[string]*dependency.Impact, error) {
+	return f.impacts, f.err
+}
+
+func TestRunListWithImpact(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{List: true, Impact: true}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+		{Path: "github.com/google/uuid", Version: "v1.5.0", NewVersion: "v1.6.0", HasUpdate: true},
+	}
+	ginImpact := &dependency.Impact{Importers: []string{"example.com/app/api"}, Transitive: []string{"example.com/app"}, CallSites: 14}
+	impacts := fakeImpacts{impacts: map[string]*dependency.Impact{
+		"github.com/gin-gonic/gin": ginImpact,
+		"golang.org/x/net":         {Transitive: []string{"example.com/app/api"}},
+	}}
+
+	analyzed := append([]dependency.Dependency(nil), deps...)
+	analyzed[0].Impact = ginImpact
+	analyzed[1].Impact = &dependency.Impact{}
+
+	console.EXPECT().Header()
+	console.EXPECT().PrintReport(gomock.Any())
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil)
+	depMgr.EXPECT().FilterDependencies(deps, false).Return(deps)
+	console.EXPECT().PrintDependencies(analyzed, "Found 2 direct dependencies with available updates:")
+	console.EXPECT().PrintImpact(analyzed[0])
+	console.EXPECT().PrintImpact(analyzed[1])
+
+	app := NewWithSources(cfg, console, depMgr, mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl), Sources{Impacts: impacts})
+	err := app.Run(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func TestRunImpactUnavailable(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources Sources
+		warn    func(console *mocks.MockConsole)
+	}{
+		{
+			name: "no analyzer",
+			warn: func(console *mocks.MockConsole) {
+				console.EXPECT().Warning("Impact analysis is not available, showing the updates without it")
+			},
+		},
+		{
+			name:    "packages cannot be listed",
+			sources: Sources{Impacts: fakeImpacts{err: errors.New("failed to list packages: exit status 1")}},
+			warn: func(console *mocks.MockConsole) {
+				console.EXPECT().Warning("Could not analyze the impact of the updates: %v", gomock.Any())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			cfg := &config.Config{List: true, Impact: true}
+			console := mocks.NewMockConsole(ctrl)
+			depMgr := mocks.NewMockManager(ctrl)
+
+			deps := []dependency.Dependency{
+				{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+			}
+
+			console.EXPECT().Header()
+			console.EXPECT().PrintReport(gomock.Any())
+			console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+			depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil)
+			depMgr.EXPECT().FilterDependencies(deps, false).Return(deps)
+			tt.warn(console)
+			console.EXPECT().PrintDependencies(deps, "Found 1 direct dependencies with available updates:")
+			console.EXPECT().PrintImpact(deps[0])
+
+			app := NewWithSources(cfg, console, depMgr, mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl), tt.sources)
+			err := app.Run(context.Background())
+
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/semver"
+
+	"goup/internal/config"
+	"goup/internal/dependency"
+	"goup/internal/ui"
+)
+
+// Module is a module found in recursive mode with the application that updates it
+type Module struct {
+	Dir string // Module directory relative to the target directory
+	App *App
+}
+
+// Recursive runs the application on every module below the target directory
+type Recursive struct {
+	config  *config.Config
+	console ui.Console
+	modules []Module
+}
+
+// NewRecursive creates a runner for the modules of a monorepo
+func NewRecursive(cfg *config.Config, console ui.Console, modules []Module) *Recursive {
+	return &Recursive{
+		config:  cfg,
+		console: console,
+		modules: modules,
+	}
+}
+
+// Run executes the application in every module, then reports the outcome of
+// each one. A module that fails does not stop the others. Cancelling ctx
+// stops the run once the current module is done.
+func (r *Recursive) Run(ctx context.Context) error {
+	r.console.Header()
+
+	if r.config.SyncVersions && !r.config.Rollback {
+		targets := r.sharedTargets(ctx)
+		for _, module := range r.modules {
+			module.App.targets = targets
+		}
+	}
+
+	report := ui.Report{Mode: reportMode(r.config)}
+	var stopErr error
+	for i, module := range r.modules {
+		if stopErr = stopError(ctx, r.config); stopErr != nil {
+			r.console.Warning("Stopped before module %s, %d of %d modules were not processed: %v",
+				module.Dir, len(r.modules)-i, len(r.modules), stopErr)
+			break
+		}
+
+		r.console.Info("📁 Module %s", module.Dir)
+
+		moduleCtx, cancel := uninterruptible(ctx)
+		moduleReport, _ := module.App.execute(moduleCtx)
+		cancel()
+		if moduleReport.Err != nil {
+			r.console.Error("Module %s failed: %v", module.Dir, moduleReport.Err)
+		}
+		report.Modules = append(report.Modules, ui.ModuleReport{Dir: module.Dir, Report: moduleReport})
+	}
+
+	err := combineModuleErrors(report.Modules)
+	if stopErr != nil {
+		err = stopErr
+	}
+
+	report.ExitCode = ExitCode(err)
+	if report.ExitCode == ExitError || report.ExitCode == ExitInterrupted {
+		report.Err = err
+	}
+	r.console.PrintReport(report)
+
+	return err
+}
+
+// sharedTargets returns, for each dependency, the highest version any module
+// would update it to
+func (r *Recursive) sharedTargets(ctx context.Context) map[string]string {
+	targets := make(map[string]string)
+
+	for _, module := range r.modules {
+		deps, err := module.App.findUpdates(ctx)
+		if err != nil {
+			// The module reports the error when it runs
+			r.console.Debug("Skipping %s while aligning versions: %v", module.Dir, err)
+			continue
+		}
+
+		for _, dep := range deps {
+			if dep.NewPath != "" {
+				continue
+			}
+			if current, ok := targets[dep.Path]; !ok || semver.Compare(dep.NewVersion, current) > 0 {
+				targets[dep.Path] = dep.NewVersion
+			}
+		}
+	}
+
+	return targets
+}
+
+// alignVersions raises each update to the version shared by every module, so
+// a dependency ends up at the same version across the monorepo. A module only
+// takes the shared version when its own policy, rules and exclude directives
+// allow it; otherwise it keeps its own target and the mismatch is reported.
+func (a *App) alignVersions(ctx context.Context, deps []dependency.Dependency) []dependency.Dependency {
+	if len(a.targets) == 0 {
+		return deps
+	}
+
+	var paths []string
+	for _, dep := range deps {
+		if _, ok := a.sharedTarget(dep); ok {
+			paths = append(paths, dep.Path)
+		}
+	}
+	if len(paths) == 0 {
+		return deps
+	}
+
+	versions, err := a.depMgr.GetAvailableVersions(ctx, paths)
+	if err != nil {
+		a.console.Warning("Could not align versions across modules, keeping the targets of this module: %v", err)
+		return deps
+	}
+
+	aligned := make([]dependency.Dependency, len(deps))
+	for i, dep := range deps {
+		if target, ok := a.sharedTarget(dep); ok {
+			if a.allowsTarget(dep, target, versions[dep.Path]) {
+				a.console.Debug("Aligning %s on %s (was %s)", dep.Path, target, dep.NewVersion)
+				dep.NewVersion = target
+			} else {
+				a.console.Warning("%s stays on %s, the shared version %s is not allowed by this module's policy, rules or exclude directives",
+					dep.Path, dep.NewVersion, target)
+			}
+		}
+		aligned[i] = dep
+	}
+
+	return aligned
+}
+
+// sharedTarget returns the shared version of a dependency if it is higher
+// than the update the module found on its own
+func (a *App) sharedTarget(dep dependency.Dependency) (string, bool) {
+	target, ok := a.targets[dep.Path]
+	if !ok || dep.NewPath != "" || semver.Compare(target, dep.NewVersion) <= 0 {
+		return "", false
+	}
+	return target, true
+}
+
+// allowsTarget reports whether resolveVersions could have chosen target for
+// the dependency: it must be published and not excluded, within the pinned
+// range of its rule and allowed by the update policy
+func (a *App) allowsTarget(dep dependency.Dependency, target string, available []string) bool {
+	if rule := a.config.RuleFor(dep.Path); rule != nil && rule.Ignore {
+		return false
+	}
+
+	allowed, err := a.allowedVersions(dep, available)
+	return err == nil && slices.Contains(allowed, target)
+}
+
+// combineModuleErrors merges the outcomes of the modules into the error of
+// the whole run, so the exit code reflects the worst outcome
+func combineModuleErrors(modules []ui.ModuleReport) error {
+	var errored []string
+	var partial, total, available, updated bool
+
+	for _, module := range modules {
+		switch module.ExitCode {
+		case ExitError:
+			errored = append(errored, module.Dir)
+		case ExitPartialFailure:
+			partial = true
+		case ExitTotalFailure:
+			total = true
+		case ExitUpdatesAvailable:
+			available = true
+		}
+
+		if module.Update != nil && len(module.Update.Updated) > 0 && !module.RolledBack {
+			updated = true
+		}
+	}
+
+	switch {
+	case len(errored) > 0:
+		return fmt.Errorf("%d of %d modules failed: %s", len(errored), len(modules), strings.Join(errored, ", "))
+	case partial || (total && updated):
+		return ErrPartialFailure
+	case total:
+		return ErrTotalFailure
+	case available:
+		return ErrUpdatesAvailable
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,270 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"goup/internal/config"
+	"goup/internal/dependency"
+	"goup/internal/mocks"
+	"goup/internal/ui"
+	"goup/internal/updater"
+)
+
+func TestRecursiveRunReportsEveryModule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{List: true, Recursive: true}
+	console := mocks.NewMockConsole(ctrl)
+	apiMgr := mocks.NewMockManager(ctrl)
+	toolsMgr := mocks.NewMockManager(ctrl)
+
+	gin := dependency.Dependency{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true}
+
+	var report ui.Report
+	console.EXPECT().Header().Times(1)
+	console.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().PrintDependencies(gomock.Any(), gomock.Any()).Times(1)
+	console.EXPECT().Error("Module %s failed: %v", "tools", gomock.Any()).Times(1)
+	console.EXPECT().PrintReport(gomock.Any()).Do(func(r ui.Report) { report = r }).Times(1)
+	apiMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return([]dependency.Dependency{gin}, nil)
+	apiMgr.EXPECT().FilterDependencies(gomock.Any(), false).DoAndReturn(
+		func(deps []dependency.Dependency, _ bool) []dependency.Dependency { return deps })
+	toolsMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(nil, errors.New("go list failed"))
+
+	recursive := NewRecursive(cfg, console, []Module{
+		{Dir: "services/api", App: New(cfg, console, apiMgr, mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl))},
+		{Dir: "tools", App: New(cfg, console, toolsMgr, mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl))},
+	})
+	err := recursive.Run(context.Background())
+
+	require.Error(t, err)
+	assert.Equal(t, "1 of 2 modules failed: tools", err.Error())
+	assert.Equal(t, ExitError, report.ExitCode)
+	require.Len(t, report.Modules, 2)
+	assert.Equal(t, "services/api", report.Modules[0].Dir)
+	assert.Equal(t, []dependency.Dependency{gin}, report.Modules[0].Dependencies)
+	assert.Equal(t, "tools", report.Modules[1].Dir)
+	assert.EqualError(t, report.Modules[1].Err, "go list failed")
+}
+
+func TestRecursiveSyncVersions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{Recursive: true, SyncVersions: true}
+	console := mocks.NewMockConsole(ctrl)
+	apiMgr := mocks.NewMockManager(ctrl)
+	workerMgr := mocks.NewMockManager(ctrl)
+	apiUpd := mocks.NewMockUpdater(ctrl)
+	workerUpd := mocks.NewMockUpdater(ctrl)
+
+	// The worker only found an older release, it is raised to the one of the api
+	apiGin := dependency.Dependency{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.10.0", HasUpdate: true}
+	workerGin := dependency.Dependency{Path: "github.com/gin-gonic/gin", Version: "v1.8.0", NewVersion: "v1.8.2", HasUpdate: true}
+	alignedGin := workerGin
+	alignedGin.NewVersion = "v1.10.0"
+
+	console.EXPECT().Header().Times(1)
+	console.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().Success(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().PrintDependencies(gomock.Any(), gomock.Any()).Times(2)
+	console.EXPECT().ProgressBar(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().PrintUpdateResult(1, 1, false).Times(2)
+	console.EXPECT().PrintReport(gomock.Any()).Times(1)
+
+	// Each module lists its updates once, even though sync mode inspects them first
+	for _, m := range []struct {
+		mgr     *mocks.MockManager
+		upd     *mocks.MockUpdater
+		listed  dependency.Dependency
+		updated dependency.Dependency
+	}{
+		{apiMgr, apiUpd, apiGin, apiGin},
+		{workerMgr, workerUpd, workerGin, alignedGin},
+	} {
+		m.mgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return([]dependency.Dependency{m.listed}, nil).Times(1)
+		m.mgr.EXPECT().FilterDependencies(gomock.Any(), false).DoAndReturn(
+			func(deps []dependency.Dependency, _ bool) []dependency.Dependency { return deps })
+		m.upd.EXPECT().Snapshot().Return(nil)
+		m.upd.EXPECT().UpdateDependencies(gomock.Any(), []dependency.Dependency{m.updated}, false).Return(updater.UpdateResult{
+			Updated: []dependency.Dependency{m.updated},
+			Success: true,
+		})
+		m.upd.EXPECT().RunModTidy(gomock.Any(), false).Return(nil)
+	}
+	workerMgr.EXPECT().GetAvailableVersions(gomock.Any(), []string{"github.com/gin-gonic/gin"}).Return(map[string][]string{
+		"github.com/gin-gonic/gin": {"v1.8.0", "v1.8.2", "v1.9.1", "v1.10.0"},
+	}, nil).Times(1)
+
+	recursive := NewRecursive(cfg, console, []Module{
+		{Dir: "api", App: New(cfg, console, apiMgr, mocks.NewMockSelector(ctrl), apiUpd)},
+		{Dir: "worker", App: New(cfg, console, workerMgr, mocks.NewMockSelector(ctrl), workerUpd)},
+	})
+
+	assert.NoError(t, recursive.Run(context.Background()))
+}
+
+func TestAlignVersions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	console := mocks.NewMockConsole(ctrl)
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	depMgr := mocks.NewMockManager(ctrl)
+	depMgr.EXPECT().GetAvailableVersions(gomock.Any(), []string{"github.com/gin-gonic/gin"}).Return(map[string][]string{
+		"github.com/gin-gonic/gin": {"v1.8.0", "v1.8.2", "v1.10.0"},
+	}, nil).Times(1)
+
+	app := New(&config.Config{}, console, depMgr, nil, nil)
+	app.targets = map[string]string{
+		"github.com/gin-gonic/gin": "v1.10.0",
+		"golang.org/x/crypto":      "v0.16.0",
+	}
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.8.0", NewVersion: "v1.8.2"},
+		{Path: "golang.org/x/crypto", Version: "v0.14.0", NewVersion: "v0.17.0"},
+		{Path: "github.com/foo/bar", Version: "v1.0.0", NewPath: "github.com/foo/bar/v2", NewVersion: "v2.0.0"},
+	}
+
+	aligned := app.alignVersions(context.Background(), deps)
+
+	assert.Equal(t, "v1.10.0", aligned[0].NewVersion, "Raised to the shared version")
+	assert.Equal(t, "v0.17.0", aligned[1].NewVersion, "A higher target is kept")
+	assert.Equal(t, "v2.0.0", aligned[2].NewVersion, "Major upgrades are not aligned")
+	assert.Equal(t, "v1.8.2", deps[0].NewVersion, "The input is not modified")
+}
+
+func TestAlignVersionsKeepsDisallowedTargets(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       *config.Config
+		available []string
+	}{
+		{
+			name:      "patch policy",
+			cfg:       &config.Config{Policy: dependency.PolicyPatch},
+			available: []string{"v1.2.3", "v1.2.5", "v1.4.1"},
+		},
+		{
+			name:      "allow rule",
+			cfg:       &config.Config{Rules: []config.Rule{{Module: "github.com/foo/bar", Allow: "patch"}}},
+			available: []string{"v1.2.3", "v1.2.5", "v1.4.1"},
+		},
+		{
+			name:      "pinned range",
+			cfg:       &config.Config{Rules: []config.Rule{{Module: "github.com/foo/*", Pin: "<v1.3.0"}}},
+			available: []string{"v1.2.3", "v1.2.5", "v1.4.1"},
+		},
+		{
+			name:      "excluded version",
+			cfg:       &config.Config{},
+			available: []string{"v1.2.3", "v1.2.5"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			console := mocks.NewMockConsole(ctrl)
+			console.EXPECT().Warning(gomock.Any(), "github.com/foo/bar", "v1.2.5", "v1.4.1").Times(1)
+			depMgr := mocks.NewMockManager(ctrl)
+			depMgr.EXPECT().GetAvailableVersions(gomock.Any(), []string{"github.com/foo/bar"}).Return(map[string][]string{
+				"github.com/foo/bar": tt.available,
+			}, nil).Times(1)
+
+			app := New(tt.cfg, console, depMgr, nil, nil)
+			app.targets = map[string]string{"github.com/foo/bar": "v1.4.1"}
+
+			aligned := app.alignVersions(context.Background(), []dependency.Dependency{
+				{Path: "github.com/foo/bar", Version: "v1.2.3", NewVersion: "v1.2.5"},
+			})
+
+			assert.Equal(t, "v1.2.5", aligned[0].NewVersion)
+		})
+	}
+}
+
+func TestAlignVersionsLookupFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	console := mocks.NewMockConsole(ctrl)
+	console.EXPECT().Warning(gomock.Any(), errors.New("proxy unavailable")).Times(1)
+	depMgr := mocks.NewMockManager(ctrl)
+	depMgr.EXPECT().GetAvailableVersions(gomock.Any(), gomock.Any()).Return(nil, errors.New("proxy unavailable"))
+
+	app := New(&config.Config{}, console, depMgr, nil, nil)
+	app.targets = map[string]string{"github.com/foo/bar": "v1.4.1"}
+
+	aligned := app.alignVersions(context.Background(), []dependency.Dependency{
+		{Path: "github.com/foo/bar", Version: "v1.2.3", NewVersion: "v1.2.5"},
+	})
+
+	assert.Equal(t, "v1.2.5", aligned[0].NewVersion)
+}
+
+func TestCombineModuleErrors(t *testing.T) {
+	updated := &updater.UpdateResult{Updated: []dependency.Dependency{{Path: "github.com/gin-gonic/gin"}}}
+
+	tests := []struct {
+		name     string
+		modules  []ui.ModuleReport
+		expected int
+	}{
+		{
+			name:     "every module succeeded",
+			modules:  []ui.ModuleReport{{Dir: "a"}, {Dir: "b", Report: ui.Report{Update: updated}}},
+			expected: ExitOK,
+		},
+		{
+			name: "updates available",
+			modules: []ui.ModuleReport{
+				{Dir: "a"},
+				{Dir: "b", Report: ui.Report{ExitCode: ExitUpdatesAvailable}},
+			},
+			expected: ExitUpdatesAvailable,
+		},
+		{
+			name: "one module failed entirely",
+			modules: []ui.ModuleReport{
+				{Dir: "a", Report: ui.Report{Update: updated}},
+				{Dir: "b", Report: ui.Report{ExitCode: ExitTotalFailure}},
+			},
+			expected: ExitPartialFailure,
+		},
+		{
+			name: "every update failed",
+			modules: []ui.ModuleReport{
+				{Dir: "a", Report: ui.Report{ExitCode: ExitTotalFailure}},
+				{Dir: "b"},
+			},
+			expected: ExitTotalFailure,
+		},
+		{
+			name: "module error wins",
+			modules: []ui.ModuleReport{
+				{Dir: "a", Report: ui.Report{ExitCode: ExitPartialFailure}},
+				{Dir: "b", Report: ui.Report{ExitCode: ExitError}},
+			},
+			expected: ExitError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ExitCode(combineModuleErrors(tt.modules)))
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package app
+
+import (
+	"context"
+
+	"golang.org/x/mod/semver"
+
+	"goup/internal/dependency"
+)
+
+// skipReplaced drops the dependencies replaced in go.mod: updating their
+// requirement has no effect on the build. They are kept with --replaced and
+// by goup drop-replaces, which only finds replaced modules.
+func (a *App) skipReplaced(deps []dependency.Dependency) []dependency.Dependency {
+	if a.config.Replaced || a.config.DropReplaces {
+		return deps
+	}
+
+	var kept []dependency.Dependency
+	for _, dep := range deps {
+		if dep.Replace == nil {
+			kept = append(kept, dep)
+		} else {
+			a.console.Debug("Skipping %s, replaced by %s", dep.Path, dep.Replace)
+		}
+	}
+
+	if skipped := len(deps) - len(kept); skipped > 0 {
+		a.console.Info("(%d replaced modules were skipped, use --replaced to include them)", skipped)
+	}
+	return kept
+}
+
+// withReplacementUpdates swaps the upstream updates of the modules replaced
+// by a fork for the updates of the fork, which is the code the build uses
+func (a *App) withReplacementUpdates(ctx context.Context, deps []dependency.Dependency) ([]dependency.Dependency, error) {
+	forks, err := a.replacedByForks()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []dependency.Dependency
+	for _, dep := range deps {
+		if !dep.IsFork() {
+			result = append(result, dep)
+		}
+	}
+	if len(forks) == 0 {
+		return result, nil
+	}
+
+	a.console.Debug("Looking for new versions of %d forks...", len(forks))
+	updates, err := a.depMgr.GetReplacementUpdates(ctx, forks)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(result, updates...), nil
+}
+
+// findStaleReplaces returns the modules replaced by a fork whose upstream
+// has published a release newer than the required version: the fix the fork
+// was made for is expected to be released. The version of the fork itself is
+// not compared, as forks are versioned independently of upstream. Each is
+// updated to the first such release, and its replace directive is dropped.
+func (a *App) findStaleReplaces(ctx context.Context) ([]dependency.Dependency, error) {
+	forks, err := a.replacedByForks()
+	if err != nil || len(forks) == 0 {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(forks))
+	for _, dep := range forks {
+		paths = append(paths, dep.Path)
+	}
+	versions, err := a.depMgr.GetAvailableVersions(ctx, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	var stale []dependency.Dependency
+	for _, dep := range forks {
+		target := upstreamTarget(dep, versions[dep.Path])
+		if target == "" {
+			a.console.Debug("%s has no release including %s", dep.Path, dep.Replace)
+			continue
+		}
+
+		dep.NewVersion = target
+		dep.HasUpdate = true
+		dep.DropReplace = true
+		stale = append(stale, dep)
+	}
+
+	return stale, nil
+}
+
+// replacedByForks returns the go.mod requirements replaced by a fork that no
+// rule ignores
+func (a *App) replacedByForks() ([]dependency.Dependency, error) {
+	deps, err := a.depMgr.GetDependencies()
+	if err != nil {
+		return nil, err
+	}
+
+	var forks []dependency.Dependency
+	for _, dep := range deps {
+		if rule := a.config.RuleFor(dep.Path); rule != nil && rule.Ignore {
+			continue
+		}
+		if dep.IsFork() {
+			forks = append(forks, dep)
+		}
+	}
+	return forks, nil
+}
+
+// upstreamTarget returns the oldest upstream release newer than the required
+// version, which the fork was created to avoid, or "" if there is none
+func upstreamTarget(dep dependency.Dependency, versions []string) string {
+	var target string
+	for _, version := range versions {
+		if semver.Prerelease(version) != "" || semver.Compare(version, dep.Version) <= 0 {
+			continue
+		}
+		if target == "" || semver.Compare(version, target) < 0 {
+			target = version
+		}
+	}
+	return target
+}
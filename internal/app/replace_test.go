@@ -0,0 +1,188 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"goup/internal/config"
+	"goup/internal/dependency"
+	"goup/internal/mocks"
+	"goup/internal/ui"
+	"goup/internal/updater"
+)
+
+var (
+	testFork  = &dependency.Replace{Path: "github.com/me/bar", Version: "v1.2.1-fix"}
+	testLocal = &dependency.Replace{Path: "./lib"}
+)
+
+func TestRunListReplaced(t *testing.T) {
+	gin := dependency.Dependency{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true}
+	bar := dependency.Dependency{Path: "github.com/foo/bar", Version: "v1.2.0", NewVersion: "v1.5.0", HasUpdate: true, Replace: testFork}
+	lib := dependency.Dependency{Path: "example.com/lib", Version: "v1.0.0", NewVersion: "v1.1.0", HasUpdate: true, Replace: testLocal}
+	required := []dependency.Dependency{
+		{Path: "example.com/lib", Version: "v1.0.0", Replace: testLocal},
+		{Path: "github.com/foo/bar", Version: "v1.2.0", Replace: testFork},
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1"},
+	}
+	forkUpdate := dependency.Dependency{Path: "github.com/foo/bar", Version: "v1.2.1-fix", NewVersion: "v1.3.0", HasUpdate: true, Replace: testFork}
+
+	tests := []struct {
+		name   string
+		cfg    *config.Config
+		expect func(*mocks.MockConsole, *mocks.MockManager)
+	}{
+		{
+			name: "skipped by default",
+			cfg:  &config.Config{List: true},
+			expect: func(console *mocks.MockConsole, depMgr *mocks.MockManager) {
+				deps := []dependency.Dependency{gin}
+				console.EXPECT().Info("(%d replaced modules were skipped, use --replaced to include them)", 2)
+				depMgr.EXPECT().FilterDependencies(deps, false).Return(deps)
+				console.EXPECT().PrintDependencies(deps, "Found 1 direct dependencies with available updates:")
+			},
+		},
+		{
+			name: "forks updated with --replaced",
+			cfg:  &config.Config{List: true, Replaced: true},
+			expect: func(console *mocks.MockConsole, depMgr *mocks.MockManager) {
+				deps := []dependency.Dependency{gin, lib, forkUpdate}
+				depMgr.EXPECT().GetDependencies().Return(required, nil)
+				depMgr.EXPECT().GetReplacementUpdates(gomock.Any(), required[1:2]).Return([]dependency.Dependency{forkUpdate}, nil)
+				depMgr.EXPECT().FilterDependencies(deps, false).Return(deps)
+				console.EXPECT().PrintDependencies(deps, "Found 3 direct dependencies with available updates:")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			console := mocks.NewMockConsole(ctrl)
+			depMgr := mocks.NewMockManager(ctrl)
+
+			console.EXPECT().Header()
+			console.EXPECT().PrintReport(gomock.Any())
+			console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+			depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return([]dependency.Dependency{gin, bar, lib}, nil)
+			tt.expect(console, depMgr)
+
+			app := New(tt.cfg, console, depMgr, mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl))
+			err := app.Run(context.Background())
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRunDropReplaces(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{DropReplaces: true}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+	upd := mocks.NewMockUpdater(ctrl)
+
+	baz := &dependency.Replace{OldVersion: "v0.1.0", Path: "github.com/me/baz", Version: "v0.3.0"}
+	required := []dependency.Dependency{
+		{Path: "example.com/lib", Version: "v1.0.0", Replace: testLocal},
+		{Path: "github.com/foo/bar", Version: "v1.2.0", Replace: testFork},
+		{Path: "github.com/foo/baz", Version: "v0.1.0", Replace: baz},
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1"},
+	}
+	stale := []dependency.Dependency{
+		{Path: "github.com/foo/bar", Version: "v1.2.0", NewVersion: "v1.2.1", HasUpdate: true, Replace: testFork, DropReplace: true},
+	}
+	result := updater.UpdateResult{Success: true, Updated: stale}
+
+	var report ui.Report
+	console.EXPECT().Header()
+	console.EXPECT().PrintReport(gomock.Any()).Do(func(r ui.Report) { report = r })
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().Info(gomock.Any()).AnyTimes()
+	console.EXPECT().Success(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().ProgressBar(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	depMgr.EXPECT().GetDependencies().Return(required, nil)
+	depMgr.EXPECT().GetAvailableVersions(gomock.Any(), []string{"github.com/foo/bar", "github.com/foo/baz"}).Return(map[string][]string{
+		"github.com/foo/bar": {"v1.2.0", "v1.2.1", "v1.3.0", "v1.4.0-rc.1"},
+		"github.com/foo/baz": {"v0.1.0"},
+	}, nil)
+	depMgr.EXPECT().FilterDependencies(stale, false).Return(stale)
+	console.EXPECT().PrintDependencies(stale, "Found 1 direct dependencies with stale replace directives:")
+	upd.EXPECT().Snapshot()
+	upd.EXPECT().UpdateDependencies(gomock.Any(), stale, false).Return(result)
+	console.EXPECT().PrintUpdateResult(1, 1, false)
+	upd.EXPECT().RunModTidy(gomock.Any(), false)
+
+	err := New(cfg, console, depMgr, mocks.NewMockSelector(ctrl), upd).Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, stale, report.Dependencies)
+}
+
+func TestRunDropReplacesNothingStale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{DropReplaces: true}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+
+	console.EXPECT().Header()
+	console.EXPECT().PrintReport(gomock.Any())
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	depMgr.EXPECT().GetDependencies().Return([]dependency.Dependency{{Path: "example.com/lib", Version: "v1.0.0", Replace: testLocal}}, nil)
+	console.EXPECT().Info("No replace directive is stale 🎉")
+
+	err := New(cfg, console, depMgr, mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl)).Run(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func TestUpstreamTarget(t *testing.T) {
+	versions := []string{"v1.1.0", "v1.2.0", "v1.2.1", "v1.3.0-rc.1", "v1.3.0"}
+
+	tests := []struct {
+		name string
+		dep  dependency.Dependency
+		want string
+	}{
+		{
+			name: "first release after the required version",
+			dep:  dependency.Dependency{Version: "v1.1.0", Replace: &dependency.Replace{Version: "v1.2.1-0.20240101000000-abcdef123456"}},
+			want: "v1.2.0",
+		},
+		{
+			name: "pseudo-version fork of the required version",
+			dep:  dependency.Dependency{Version: "v1.2.1", Replace: &dependency.Replace{Version: "v1.2.1-0.20240101000000-abcdef123456"}},
+			want: "v1.3.0",
+		},
+		{
+			name: "fork versioned ahead of upstream",
+			dep:  dependency.Dependency{Version: "v1.2.0", Replace: &dependency.Replace{Version: "v3.0.0"}},
+			want: "v1.2.1",
+		},
+		{
+			name: "fork versioned behind the required version",
+			dep:  dependency.Dependency{Version: "v1.2.1", Replace: &dependency.Replace{Version: "v0.0.1"}},
+			want: "v1.3.0",
+		},
+		{
+			name: "no release newer than the required version",
+			dep:  dependency.Dependency{Version: "v1.3.0", Replace: &dependency.Replace{Version: "v1.3.1"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, upstreamTarget(tt.dep, versions))
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package app
+
+import (
+	"context"
+
+	"goup/internal/config"
+	"goup/internal/dependency"
+)
+
+// findUpdates returns the dependencies with an update allowed by the
+// configuration, including new major versions when discovery is enabled and
+// new fork versions with --replaced, the fixes of vulnerable modules in
+// security mode, or the stale replace directives for goup drop-replaces. The
+// up-to-date dependencies with a notice are kept apart for reportNotices. The
+// result is computed once, so recursive mode can inspect it before the run.
+func (a *App) findUpdates(ctx context.Context) ([]dependency.Dependency, error) {
+	if a.updates != nil {
+		return a.updates, nil
+	}
+
+	if a.config.Security {
+		deps, err := a.findSecurityUpdates(ctx)
+		if err != nil {
+			return nil, err
+		}
+		a.updates = append([]dependency.Dependency{}, deps...)
+		return a.updates, nil
+	}
+
+	if a.config.DropReplaces {
+		deps, err := a.findStaleReplaces(ctx)
+		if err != nil {
+			return nil, err
+		}
+		a.updates = append([]dependency.Dependency{}, deps...)
+		return a.updates, nil
+	}
+
+	// Get only updatable dependencies
+	deps, err := a.depMgr.GetUpdatableDependencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deps, a.notices = a.splitNotices(deps)
+
+	// Apply configuration rules and recompute targets restricted by a policy or pin
+	deps, err = a.resolveVersions(ctx, deps)
+	if err != nil {
+		return nil, err
+	}
+
+	// A fork is updated by its replace directive, not by the upstream requirement
+	if a.config.Replaced {
+		deps, err = a.withReplacementUpdates(ctx, deps)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Newer major versions live under different module paths and need their own lookup
+	if a.config.ShouldDiscoverMajors() {
+		majorDeps, err := a.findMajorUpgrades(ctx)
+		if err != nil {
+			return nil, err
+		}
+		deps = append(deps, majorDeps...)
+	}
+
+	a.updates = append([]dependency.Dependency{}, deps...)
+	return a.updates, nil
+}
+
+// resolveVersions applies the configuration rules and the update policy to the
+// dependencies reported by go list. Ignored modules are dropped, and targets
+// restricted by a policy or a pinned range are recomputed from every
+// published version; dependencies left without an allowed target are dropped.
+func (a *App) resolveVersions(ctx context.Context, deps []dependency.Dependency) ([]dependency.Dependency, error) {
+	var paths []string
+	for _, dep := range deps {
+		rule := a.config.RuleFor(dep.Path)
+		if a.needsVersionList(rule) {
+			paths = append(paths, dep.Path)
+		}
+	}
+
+	var versions map[string][]string
+	if len(paths) > 0 {
+		var err error
+		versions, err = a.depMgr.GetAvailableVersions(ctx, paths)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var resolved []dependency.Dependency
+	for _, dep := range deps {
+		rule := a.config.RuleFor(dep.Path)
+		if rule != nil && rule.Ignore {
+			a.console.Debug("Ignoring %s (rule %q)", dep.Path, rule.Module)
+			continue
+		}
+
+		if !a.needsVersionList(rule) {
+			resolved = append(resolved, dep)
+			continue
+		}
+
+		policy := a.config.Policy
+		candidates := versions[dep.Path]
+		if rule != nil {
+			policy = rule.AllowPolicy(policy)
+			versionRange, ok, err := rule.PinRange()
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				candidates = versionRange.Filter(candidates)
+			}
+		}
+
+		target := dependency.SelectVersion(dep.Version, candidates, policy)
+		if target == "" {
+			a.console.Debug("No %s update of %s is allowed", policy, dep.Path)
+			continue
+		}
+
+		dep.NewVersion = target
+		dep.HasUpdate = true
+		resolved = append(resolved, dep)
+	}
+
+	return resolved, nil
+}
+
+// needsVersionList reports whether the target of a dependency must be chosen
+// from its published versions rather than taken from 'go list -u'
+func (a *App) needsVersionList(rule *config.Rule) bool {
+	if rule != nil && rule.Ignore {
+		return false
+	}
+	if a.config.Policy != dependency.PolicyLatest {
+		return true
+	}
+	return rule != nil && (rule.Pin != "" || rule.Allow != "")
+}
+
+func (a *App) findMajorUpgrades(ctx context.Context) ([]dependency.Dependency, error) {
+	deps, err := a.depMgr.GetDependencies()
+	if err != nil {
+		return nil, err
+	}
+
+	// Only direct dependencies are imported by our code and can be rewritten,
+	// and a new major version path would not be replaced
+	var direct []dependency.Dependency
+	for _, dep := range deps {
+		if !dep.Indirect && dep.Replace == nil && a.majorAllowed(dep) {
+			direct = append(direct, dep)
+		}
+	}
+
+	a.console.Debug("Looking for new major versions of %d direct dependencies...", len(direct))
+	upgrades, err := a.depMgr.GetMajorUpgrades(ctx, direct)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pinned ranges can only be checked once the new major version is known
+	var allowed []dependency.Dependency
+	for _, dep := range upgrades {
+		if a.majorAllowed(dep) {
+			allowed = append(allowed, dep)
+		}
+	}
+
+	return allowed, nil
+}
+
+// majorAllowed reports whether the configuration rules permit a major upgrade
+// of a dependency. Without a known target only ignore and allow rules apply.
+func (a *App) majorAllowed(dep dependency.Dependency) bool {
+	rule := a.config.RuleFor(dep.Path)
+	if rule == nil {
+		return true
+	}
+
+	if rule.Ignore || rule.AllowPolicy(dependency.PolicyMajor) != dependency.PolicyMajor {
+		return false
+	}
+
+	if versionRange, ok, err := rule.PinRange(); ok && dep.NewVersion != "" {
+		return versionRange.Contains(dep.NewVersion)
+	} else if err != nil {
+		return false
+	}
+
+	return true
+}
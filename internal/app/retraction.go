@@ -0,0 +1,82 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"goup/internal/dependency"
+)
+
+// retractedOnly keeps the dependencies whose current version is retracted
+func retractedOnly(deps []dependency.Dependency) []dependency.Dependency {
+	var retracted []dependency.Dependency
+	for _, dep := range deps {
+		if dep.IsRetracted() {
+			retracted = append(retracted, dep)
+		}
+	}
+	return retracted
+}
+
+// warnNotices warns about the retracted current versions, which should be
+// updated first, and the deprecated modules, naming the module their
+// deprecation notice recommends when there is one
+func (a *App) warnNotices(deps []dependency.Dependency) {
+	for _, dep := range deps {
+		if dep.IsRetracted() {
+			a.console.Warning("%s %s is retracted by its author, update it first: %s",
+				dep.Path, dep.Version, strings.Join(dep.Retracted, "; "))
+		}
+
+		if dep.Deprecated == "" {
+			continue
+		}
+		if replacement := dep.Replacement(); replacement != "" {
+			a.console.Warning("%s is deprecated, consider switching to %s: %s", dep.Path, replacement, dep.Deprecated)
+		} else {
+			a.console.Warning("%s is deprecated: %s", dep.Path, dep.Deprecated)
+		}
+	}
+}
+
+// splitNotices separates the up-to-date dependencies, listed only for the
+// retraction of their current version or the deprecation of their module,
+// from the updates. Notices of ignored and skipped replaced modules are dropped.
+func (a *App) splitNotices(deps []dependency.Dependency) ([]dependency.Dependency, []dependency.Dependency) {
+	var updates, notices []dependency.Dependency
+	for _, dep := range deps {
+		if !dep.IsNoticeOnly() {
+			updates = append(updates, dep)
+			continue
+		}
+		if rule := a.config.RuleFor(dep.Path); rule != nil && rule.Ignore {
+			continue
+		}
+		if dep.Replace != nil && !a.config.Replaced {
+			continue
+		}
+		notices = append(notices, dep)
+	}
+	return updates, notices
+}
+
+// reportNotices shows the up-to-date dependencies that are retracted or
+// deprecated: no update fixes them, so they are listed apart from the updates
+func (a *App) reportNotices() {
+	var notices []dependency.Dependency
+	for _, dep := range a.notices {
+		if dep.Indirect && !a.config.ShouldIncludeIndirect() {
+			continue
+		}
+		if a.config.OnlyRetracted && !dep.IsRetracted() {
+			continue
+		}
+		notices = append(notices, dep)
+	}
+	if len(notices) == 0 {
+		return
+	}
+
+	a.console.PrintDependencies(notices, fmt.Sprintf("Found %d up-to-date dependencies that are retracted or deprecated:", len(notices)))
+	a.warnNotices(notices)
+}
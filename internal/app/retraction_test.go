@@ -0,0 +1,137 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"goup/internal/config"
+	"goup/internal/dependency"
+	"goup/internal/mocks"
+)
+
+func TestRunListWarnsAboutRetractedAndDeprecated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{List: true}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/old/lib", Version: "v1.4.0", NewVersion: "v1.4.1", HasUpdate: true,
+			Retracted: []string{"Data race in the pool"}, Deprecated: "use github.com/new/lib instead"},
+		{Path: "github.com/spf13/cobra", Version: "v1.7.0", NewVersion: "v1.8.0", HasUpdate: true, Deprecated: "unmaintained"},
+	}
+
+	console.EXPECT().Header()
+	console.EXPECT().PrintReport(gomock.Any())
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil)
+	depMgr.EXPECT().FilterDependencies(deps, false).Return(deps)
+	gomock.InOrder(
+		console.EXPECT().PrintDependencies(deps, "Found 2 direct dependencies with available updates:"),
+		console.EXPECT().Warning("%s %s is retracted by its author, update it first: %s",
+			"github.com/old/lib", "v1.4.0", "Data race in the pool"),
+		console.EXPECT().Warning("%s is deprecated, consider switching to %s: %s",
+			"github.com/old/lib", "github.com/new/lib", "use github.com/new/lib instead"),
+		console.EXPECT().Warning("%s is deprecated: %s", "github.com/spf13/cobra", "unmaintained"),
+	)
+
+	app := New(cfg, console, depMgr, mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl))
+	err := app.Run(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func TestRunListReportsUpToDateDeprecatedModules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{List: true, Rules: []config.Rule{{Module: "github.com/ignored/*", Ignore: true}}}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+
+	gin := dependency.Dependency{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true}
+	deprecated := dependency.Dependency{Path: "github.com/old/lib", Version: "v1.4.0", Deprecated: "use github.com/new/lib instead"}
+	retracted := dependency.Dependency{Path: "github.com/last/lib", Version: "v0.3.0", Retracted: []string{"Broken build"}, Indirect: true}
+	ignored := dependency.Dependency{Path: "github.com/ignored/lib", Version: "v1.0.0", Deprecated: "unmaintained"}
+
+	console.EXPECT().Header()
+	console.EXPECT().PrintReport(gomock.Any())
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return([]dependency.Dependency{gin, deprecated, ignored, retracted}, nil)
+	depMgr.EXPECT().FilterDependencies([]dependency.Dependency{gin}, false).Return([]dependency.Dependency{gin})
+	// The indirect notice is left out like the indirect updates
+	gomock.InOrder(
+		console.EXPECT().PrintDependencies([]dependency.Dependency{deprecated}, "Found 1 up-to-date dependencies that are retracted or deprecated:"),
+		console.EXPECT().Warning("%s is deprecated, consider switching to %s: %s",
+			"github.com/old/lib", "github.com/new/lib", "use github.com/new/lib instead"),
+		console.EXPECT().PrintDependencies([]dependency.Dependency{gin}, "Found 1 direct dependencies with available updates:"),
+	)
+
+	app := New(cfg, console, depMgr, mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl))
+	err := app.Run(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func TestRunOnlyRetracted(t *testing.T) {
+	retracted := dependency.Dependency{Path: "github.com/old/lib", Version: "v1.4.0", NewVersion: "v1.4.1", HasUpdate: true,
+		Retracted: []string{"Data race in the pool"}}
+	gin := dependency.Dependency{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true}
+
+	tests := []struct {
+		name    string
+		updates []dependency.Dependency
+		expect  func(*mocks.MockConsole, *mocks.MockManager)
+		wantErr error
+	}{
+		{
+			name:    "retracted version",
+			updates: []dependency.Dependency{gin, retracted},
+			expect: func(console *mocks.MockConsole, depMgr *mocks.MockManager) {
+				only := []dependency.Dependency{retracted}
+				depMgr.EXPECT().FilterDependencies(only, false).Return(only)
+				console.EXPECT().PrintDependencies(only, "Found 1 direct dependencies with available updates:")
+				console.EXPECT().Warning(gomock.Any(), gomock.Any()).AnyTimes()
+			},
+			wantErr: ErrUpdatesAvailable,
+		},
+		{
+			name:    "no retracted version",
+			updates: []dependency.Dependency{gin},
+			expect: func(console *mocks.MockConsole, depMgr *mocks.MockManager) {
+				console.EXPECT().Info("No dependency uses a retracted version 🎉")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			cfg := &config.Config{List: true, OnlyRetracted: true, FailOnUpdates: true}
+			console := mocks.NewMockConsole(ctrl)
+			depMgr := mocks.NewMockManager(ctrl)
+
+			console.EXPECT().Header()
+			console.EXPECT().PrintReport(gomock.Any())
+			console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+			depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(tt.updates, nil)
+			tt.expect(console, depMgr)
+
+			app := New(cfg, console, depMgr, mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl))
+			err := app.Run(context.Background())
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package app
+
+import (
+	"context"
+	"strings"
+
+	"goup/internal/dependency"
+	"goup/internal/vuln"
+)
+
+// findSecurityUpdates checks the version of every required module against the
+// vulnerability database. Affected modules are updated to the lowest version
+// that fixes them among those the update policy, the rules and the go.mod
+// exclude directives allow, instead of the latest one, and every other module
+// is left alone.
+func (a *App) findSecurityUpdates(ctx context.Context) ([]dependency.Dependency, error) {
+	db, err := a.loadVulnDB()
+	if err != nil {
+		return nil, err
+	}
+
+	deps, err := a.depMgr.GetDependencies()
+	if err != nil {
+		return nil, err
+	}
+
+	var affected []dependency.Dependency
+	var paths []string
+	for _, dep := range deps {
+		finding, ok := db.Check(dep.Path, dep.Version)
+		if !ok {
+			continue
+		}
+
+		if rule := a.config.RuleFor(dep.Path); rule != nil && rule.Ignore {
+			a.console.Warning("%s %s is affected by %s but ignored by rule %q",
+				dep.Path, dep.Version, strings.Join(finding.Advisories, ", "), rule.Module)
+			continue
+		}
+
+		if finding.Fixed == "" {
+			a.console.Warning("%s %s is affected by %s and no fixed version is known",
+				dep.Path, dep.Version, strings.Join(finding.Advisories, ", "))
+			continue
+		}
+
+		dep.Advisories = finding.Advisories
+		affected = append(affected, dep)
+		paths = append(paths, dep.Path)
+	}
+	if len(affected) == 0 {
+		return nil, nil
+	}
+
+	// The published versions leave out the excluded and retracted ones
+	versions, err := a.depMgr.GetAvailableVersions(ctx, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	var updates []dependency.Dependency
+	for _, dep := range affected {
+		candidates, err := a.allowedVersions(dep, versions[dep.Path])
+		if err != nil {
+			return nil, err
+		}
+
+		fixed := db.FirstUnaffected(dep.Path, dep.Version, candidates)
+		if fixed == "" {
+			a.console.Warning("%s %s is affected by %s and no fixed version is allowed by the update policy, rules or exclude directives",
+				dep.Path, dep.Version, strings.Join(dep.Advisories, ", "))
+			continue
+		}
+
+		dep.NewVersion = fixed
+		dep.HasUpdate = true
+		updates = append(updates, dep)
+	}
+
+	return updates, nil
+}
+
+// allowedVersions returns the versions a dependency may be updated to under
+// the update policy and the pinned range and policy of its rule
+func (a *App) allowedVersions(dep dependency.Dependency, versions []string) ([]string, error) {
+	policy := a.config.Policy
+	if rule := a.config.RuleFor(dep.Path); rule != nil {
+		policy = rule.AllowPolicy(policy)
+		versionRange, ok, err := rule.PinRange()
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			versions = versionRange.Filter(versions)
+		}
+	}
+
+	return dependency.AllowedVersions(dep.Version, versions, policy), nil
+}
+
+// loadVulnDB reads the vulnerability database the first time it is needed
+func (a *App) loadVulnDB() (*vuln.Database, error) {
+	if a.vulnDB != nil {
+		return a.vulnDB, nil
+	}
+
+	db, err := vuln.Load(a.config.VulnDB)
+	if err != nil {
+		return nil, err
+	}
+	a.console.Debug("Loaded advisories for %d modules from %s", db.Len(), a.config.VulnDB)
+
+	a.vulnDB = db
+	return db, nil
+}
@@ -0,0 +1,210 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"goup/internal/config"
+	"goup/internal/dependency"
+	"goup/internal/mocks"
+	"goup/internal/ui"
+)
+
+const testAdvisories = `[
+  {
+    "id": "GO-2022-1059",
+    "affected": [{
+      "package": {"ecosystem": "Go", "name": "golang.org/x/text"},
+      "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "0.3.8"}]}]
+    }]
+  },
+  {
+    "id": "GO-2023-0001",
+    "affected": [{
+      "package": {"ecosystem": "Go", "name": "example.com/abandoned"},
+      "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}]}]
+    }]
+  }
+]`
+
+func writeVulnDB(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "osv.json")
+	require.NoError(t, os.WriteFile(path, []byte(testAdvisories), 0644))
+	return path
+}
+
+func TestRunListSecurity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{List: true, Security: true, VulnDB: writeVulnDB(t)}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+
+	deps := []dependency.Dependency{
+		{Path: "example.com/abandoned", Version: "v1.0.0"},
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1"},
+		{Path: "golang.org/x/text", Version: "v0.3.7", Indirect: true},
+	}
+	expected := []dependency.Dependency{
+		{Path: "golang.org/x/text", Version: "v0.3.7", NewVersion: "v0.3.8", Indirect: true, HasUpdate: true, Advisories: []string{"GO-2022-1059"}},
+	}
+
+	var report ui.Report
+	console.EXPECT().Header()
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().Warning("%s %s is affected by %s and no fixed version is known", "example.com/abandoned", "v1.0.0", "GO-2023-0001")
+	console.EXPECT().PrintReport(gomock.Any()).Do(func(r ui.Report) { report = r })
+	depMgr.EXPECT().GetDependencies().Return(deps, nil)
+	depMgr.EXPECT().GetAvailableVersions(gomock.Any(), []string{"golang.org/x/text"}).Return(map[string][]string{
+		"golang.org/x/text": {"v0.3.7", "v0.3.8", "v0.14.0"},
+	}, nil)
+	depMgr.EXPECT().FilterDependencies(expected, false).Return(expected)
+	console.EXPECT().PrintDependencies(expected, "Found 1 direct dependencies with known vulnerabilities:")
+
+	err := New(cfg, console, depMgr, mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl)).Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, expected, report.Dependencies)
+}
+
+func TestRunSecurityNoVulnerabilities(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{Security: true, VulnDB: writeVulnDB(t)}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+
+	console.EXPECT().Header()
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().PrintReport(gomock.Any())
+	depMgr.EXPECT().GetDependencies().Return([]dependency.Dependency{
+		{Path: "golang.org/x/text", Version: "v0.14.0"},
+	}, nil)
+	console.EXPECT().Info("No known vulnerabilities affect the dependencies 🎉")
+
+	err := New(cfg, console, depMgr, mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl)).Run(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func TestRunSecurityIgnoredByRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{
+		Security: true,
+		VulnDB:   writeVulnDB(t),
+		Rules:    []config.Rule{{Module: "golang.org/x/*", Ignore: true}},
+	}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+
+	console.EXPECT().Header()
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().PrintReport(gomock.Any())
+	depMgr.EXPECT().GetDependencies().Return([]dependency.Dependency{
+		{Path: "golang.org/x/text", Version: "v0.3.7"},
+	}, nil)
+	console.EXPECT().Warning("%s %s is affected by %s but ignored by rule %q", "golang.org/x/text", "v0.3.7", "GO-2022-1059", "golang.org/x/*")
+	console.EXPECT().Info("No known vulnerabilities affect the dependencies 🎉")
+
+	err := New(cfg, console, depMgr, mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl)).Run(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func TestRunListSecurityFixesAllowedVersions(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       config.Config
+		available []string
+		want      string
+	}{
+		{name: "fix excluded", available: []string{"v0.3.7", "v0.3.9", "v0.4.0"}, want: "v0.3.9"},
+		{name: "pinned range", cfg: config.Config{Rules: []config.Rule{{Module: "golang.org/x/text", Pin: ">=v0.4.0"}}},
+			available: []string{"v0.3.8", "v0.4.0"}, want: "v0.4.0"},
+		{name: "pre-releases are skipped", available: []string{"v0.14.0-rc.1", "v0.14.0"}, want: "v0.14.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			cfg := tt.cfg
+			cfg.List, cfg.Security, cfg.VulnDB = true, true, writeVulnDB(t)
+			console := mocks.NewMockConsole(ctrl)
+			depMgr := mocks.NewMockManager(ctrl)
+
+			var report ui.Report
+			console.EXPECT().Header()
+			console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+			console.EXPECT().PrintDependencies(gomock.Any(), gomock.Any())
+			console.EXPECT().PrintReport(gomock.Any()).Do(func(r ui.Report) { report = r })
+			depMgr.EXPECT().GetDependencies().Return([]dependency.Dependency{{Path: "golang.org/x/text", Version: "v0.3.7"}}, nil)
+			depMgr.EXPECT().GetAvailableVersions(gomock.Any(), []string{"golang.org/x/text"}).Return(map[string][]string{
+				"golang.org/x/text": tt.available,
+			}, nil)
+			depMgr.EXPECT().FilterDependencies(gomock.Any(), false).DoAndReturn(
+				func(deps []dependency.Dependency, _ bool) []dependency.Dependency { return deps })
+
+			err := New(&cfg, console, depMgr, mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl)).Run(context.Background())
+
+			require.NoError(t, err)
+			require.Len(t, report.Dependencies, 1)
+			assert.Equal(t, tt.want, report.Dependencies[0].NewVersion)
+		})
+	}
+}
+
+func TestRunSecurityNoAllowedFix(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{Security: true, VulnDB: writeVulnDB(t), Policy: dependency.PolicyPatch}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+
+	console.EXPECT().Header()
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().PrintReport(gomock.Any())
+	depMgr.EXPECT().GetDependencies().Return([]dependency.Dependency{
+		{Path: "golang.org/x/text", Version: "v0.3.7"},
+	}, nil)
+	// v0.3.8 is excluded and --patch rules out v0.4.0
+	depMgr.EXPECT().GetAvailableVersions(gomock.Any(), []string{"golang.org/x/text"}).Return(map[string][]string{
+		"golang.org/x/text": {"v0.3.7", "v0.4.0"},
+	}, nil)
+	console.EXPECT().Warning("%s %s is affected by %s and no fixed version is allowed by the update policy, rules or exclude directives",
+		"golang.org/x/text", "v0.3.7", "GO-2022-1059")
+	console.EXPECT().Info("No known vulnerabilities affect the dependencies 🎉")
+
+	err := New(cfg, console, depMgr, mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl)).Run(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func TestRunSecurityMissingDatabase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{Security: true, VulnDB: filepath.Join(t.TempDir(), "missing")}
+	console := mocks.NewMockConsole(ctrl)
+
+	console.EXPECT().Header()
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().PrintReport(gomock.Any())
+
+	err := New(cfg, console, mocks.NewMockManager(ctrl), mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl)).Run(context.Background())
+
+	assert.ErrorContains(t, err, "reading vulnerability database")
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"context"
+
+	"goup/internal/apidiff"
+	"goup/internal/dependency"
+	"goup/internal/modgraph"
+)
+
+// Sources reads the content of the updated module versions. Unset sources
+// disable the features relying on them.
+type Sources struct {
+	Changelogs Changelogs // Used by --changelog
+	APIs       APIs       // Used by --api-diff and goup diff
+	Impacts    Impacts    // Used by --impact
+	Requires   Requires   // Used to explain indirect dependencies and by goup why
+}
+
+// Changelogs finds what changed in the changelog of a dependency between its
+// current and its new version
+type Changelogs interface {
+	// Changes returns the unified diff of the changelog, empty if it did not change
+	Changes(ctx context.Context, dep dependency.Dependency) (string, error)
+}
+
+// APIs compares the exported API of the current and the new version of a dependency
+type APIs interface {
+	// Changes returns the added, removed and changed exported identifiers
+	Changes(ctx context.Context, dep dependency.Dependency) ([]apidiff.Change, error)
+}
+
+// Impacts analyzes which packages of the main module use its dependencies
+type Impacts interface {
+	// Analyze returns the impact of every dependency used by the main module, by module path
+	Analyze(ctx context.Context) (map[string]*dependency.Impact, error)
+}
+
+// Requires explains why modules are in the build list
+type Requires interface {
+	// Graph returns the module requirement graph of the main module
+	Graph(ctx context.Context) (*modgraph.Graph, error)
+	// Requires returns the version of every module required by a module version
+	Requires(ctx context.Context, path, version string) (map[string]string, error)
+}
+
+// annotate calls fn with a copy of every dependency, showing the progress,
+// and returns the copies. Each call is given up after --command-timeout and
+// no call is made once ctx is done.
+func (a *App) annotate(ctx context.Context, deps []dependency.Dependency, what string, fn func(ctx context.Context, dep *dependency.Dependency)) []dependency.Dependency {
+	result := make([]dependency.Dependency, len(deps))
+	copy(result, deps)
+
+	for i := range result {
+		if ctx.Err() != nil {
+			break
+		}
+
+		a.console.ProgressBar(i, len(result), what+" of "+result[i].TargetPath())
+		a.withCommandTimeout(ctx, func(ctx context.Context) {
+			fn(ctx, &result[i])
+		})
+	}
+	a.console.ProgressBar(len(result), len(result), what)
+
+	return result
+}
+
+// withCommandTimeout runs fn with a context that expires after --command-timeout
+func (a *App) withCommandTimeout(ctx context.Context, fn func(ctx context.Context)) {
+	if a.config.CommandTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.config.CommandTimeout)
+		defer cancel()
+	}
+	fn(ctx)
+}
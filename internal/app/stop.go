@@ -0,0 +1,45 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"goup/internal/config"
+	"goup/internal/updater"
+)
+
+// stopError returns why the run has to stop before its next step: Ctrl-C was
+// pressed or the --timeout of the whole run expired. It is nil while ctx is live.
+func stopError(ctx context.Context, cfg *config.Config) error {
+	err := ctx.Err()
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("run timed out after %s: %w", cfg.Timeout, err)
+	default:
+		return ErrInterrupted
+	}
+}
+
+// uninterruptible returns a context for a step that must not be stopped
+// halfway, such as an update writing go.mod. It ignores Ctrl-C but still
+// expires with the deadline of ctx.
+func uninterruptible(ctx context.Context) (context.Context, context.CancelFunc) {
+	detached := context.WithoutCancel(ctx)
+	if deadline, ok := ctx.Deadline(); ok {
+		return context.WithDeadline(detached, deadline)
+	}
+	return detached, func() {}
+}
+
+// reportStop explains which updates were applied before the run stopped
+func (a *App) reportStop(result updater.UpdateResult, total int, err error) error {
+	attempted := len(result.Updated) + len(result.Failed) + len(result.Reverted)
+	a.console.Warning("Stopped after %d of %d updates, go mod tidy was skipped: %v", attempted, total, err)
+	if len(result.Updated) > 0 {
+		a.console.Info("Run goup --rollback to undo the %d applied updates", len(result.Updated))
+	}
+	return err
+}
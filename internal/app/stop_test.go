@@ -0,0 +1,157 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"goup/internal/config"
+	"goup/internal/dependency"
+	"goup/internal/mocks"
+	"goup/internal/ui"
+	"goup/internal/updater"
+)
+
+func TestStopError(t *testing.T) {
+	cfg := &config.Config{Timeout: time.Minute}
+
+	assert.NoError(t, stopError(context.Background(), cfg))
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, stopError(cancelled, cfg), ErrInterrupted)
+
+	expired, cancel := context.WithDeadline(context.Background(), time.Now())
+	defer cancel()
+	err := stopError(expired, cfg)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.EqualError(t, err, "run timed out after 1m0s: context deadline exceeded")
+	assert.Equal(t, ExitError, ExitCode(err))
+}
+
+func TestUninterruptible(t *testing.T) {
+	deadline := time.Now().Add(time.Hour)
+	parent, cancelParent := context.WithDeadline(context.Background(), deadline)
+
+	ctx, cancel := uninterruptible(parent)
+	defer cancel()
+	cancelParent()
+
+	assert.NoError(t, ctx.Err(), "Cancelling the run must not stop the current step")
+	got, ok := ctx.Deadline()
+	require.True(t, ok)
+	assert.Equal(t, deadline, got, "The step still expires with the run")
+}
+
+func TestRunStopsAfterCurrentUpdateWhenInterrupted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+	upd := mocks.NewMockUpdater(ctrl)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+		{Path: "golang.org/x/crypto", Version: "v0.14.0", NewVersion: "v0.17.0", HasUpdate: true},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var report ui.Report
+	console.EXPECT().Header().Times(1)
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().ProgressBar(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().PrintDependencies(gomock.Any(), gomock.Any()).Times(1)
+	console.EXPECT().PrintUpdateResult(1, 2, false).Times(1)
+	console.EXPECT().Warning("Stopped after %d of %d updates, go mod tidy was skipped: %v", 1, 2, ErrInterrupted).Times(1)
+	console.EXPECT().PrintReport(gomock.Any()).Do(func(r ui.Report) { report = r }).Times(1)
+
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil)
+	depMgr.EXPECT().FilterDependencies(deps, false).Return(deps)
+
+	upd.EXPECT().Snapshot().Return(nil)
+	upd.EXPECT().UpdateDependencies(gomock.Any(), []dependency.Dependency{deps[0]}, false).DoAndReturn(
+		func(updateCtx context.Context, deps []dependency.Dependency, _ bool) updater.UpdateResult {
+			// Ctrl-C during the first update lets it finish
+			cancel()
+			assert.NoError(t, updateCtx.Err())
+			return updater.UpdateResult{Updated: deps, Success: true}
+		}).Times(1)
+
+	err := New(cfg, console, depMgr, mocks.NewMockSelector(ctrl), upd).Run(ctx)
+
+	assert.ErrorIs(t, err, ErrInterrupted)
+	assert.Equal(t, ExitInterrupted, report.ExitCode)
+	require.NotNil(t, report.Update)
+	assert.Equal(t, []dependency.Dependency{deps[0]}, report.Update.Updated)
+	assert.Nil(t, report.Tidy, "go mod tidy must not run after an interruption")
+}
+
+func TestRunTimesOut(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{Timeout: time.Millisecond}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+	<-ctx.Done()
+
+	var report ui.Report
+	console.EXPECT().Header().Times(1)
+	console.EXPECT().PrintReport(gomock.Any()).Do(func(r ui.Report) { report = r }).Times(1)
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).DoAndReturn(
+		func(ctx context.Context) ([]dependency.Dependency, error) { return nil, ctx.Err() })
+
+	err := New(cfg, console, depMgr, mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl)).Run(ctx)
+
+	require.Error(t, err)
+	assert.Equal(t, "run timed out after 1ms: context deadline exceeded", err.Error())
+	assert.Equal(t, ExitError, report.ExitCode)
+}
+
+func TestRecursiveRunStopsAfterCurrentModuleWhenInterrupted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{List: true, Recursive: true}
+	console := mocks.NewMockConsole(ctrl)
+	apiMgr := mocks.NewMockManager(ctrl)
+	toolsMgr := mocks.NewMockManager(ctrl)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var report ui.Report
+	console.EXPECT().Header().Times(1)
+	console.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().Warning("Stopped before module %s, %d of %d modules were not processed: %v", "tools", 1, 2, ErrInterrupted).Times(1)
+	console.EXPECT().PrintReport(gomock.Any()).Do(func(r ui.Report) { report = r }).Times(1)
+	apiMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).DoAndReturn(
+		func(moduleCtx context.Context) ([]dependency.Dependency, error) {
+			// The current module carries on after Ctrl-C
+			cancel()
+			return nil, moduleCtx.Err()
+		})
+
+	recursive := NewRecursive(cfg, console, []Module{
+		{Dir: "services/api", App: New(cfg, console, apiMgr, mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl))},
+		{Dir: "tools", App: New(cfg, console, toolsMgr, mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl))},
+	})
+	err := recursive.Run(ctx)
+
+	assert.ErrorIs(t, err, ErrInterrupted)
+	assert.Equal(t, ExitInterrupted, report.ExitCode)
+	require.Len(t, report.Modules, 1, "Only the modules processed before Ctrl-C are reported")
+	assert.Equal(t, ExitOK, report.Modules[0].ExitCode)
+}
@@ -0,0 +1,171 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"golang.org/x/mod/semver"
+
+	"goup/internal/dependency"
+	"goup/internal/modgraph"
+	"goup/internal/ui"
+)
+
+// attachRequirements explains the indirect dependencies: the shortest chain
+// of requirements leading to them, and the updates of direct dependencies
+// that require a newer version. They are shown unexplained if the module
+// graph cannot be read.
+func (a *App) attachRequirements(ctx context.Context, deps, updates []dependency.Dependency) []dependency.Dependency {
+	if a.sources.Requires == nil || !hasIndirect(deps) {
+		return deps
+	}
+
+	graph, required, err := a.requirementGraph(ctx)
+	if err != nil {
+		a.console.Debug("Cannot explain the indirect dependencies: %v", err)
+		return deps
+	}
+	direct := directPaths(required)
+	pulls := a.findPulls(ctx, updates)
+
+	result := make([]dependency.Dependency, len(deps))
+	for i, dep := range deps {
+		if dep.Indirect {
+			dep.Chain = graph.Chain(dep.Path, direct)
+			dep.PulledBy = pullsOf(pulls, dep)
+		}
+		result[i] = dep
+	}
+	return result
+}
+
+// explainModule reports why the module given to goup why is required, and
+// which direct dependency updates would raise its version
+func (a *App) explainModule(ctx context.Context, report *ui.Report) error {
+	if a.sources.Requires == nil {
+		return fmt.Errorf("the module graph is not available")
+	}
+
+	graph, required, err := a.requirementGraph(ctx)
+	if err != nil {
+		return err
+	}
+
+	path := a.config.WhyModule
+	chain := graph.Chain(path, directPaths(required))
+	if chain == nil {
+		return fmt.Errorf("%s is not required by the module", path)
+	}
+
+	updates, err := a.findUpdates(ctx)
+	if err != nil {
+		return err
+	}
+
+	dep := whyTarget(path, chain, required, updates)
+	dep.Chain = chain
+	if dep.Indirect {
+		dep.PulledBy = pullsOf(a.findPulls(ctx, updates), dep)
+	}
+
+	report.Dependencies = []dependency.Dependency{dep}
+	a.console.PrintRequirements(dep)
+	return nil
+}
+
+// whyTarget returns the dependency explained by goup why: its update if it
+// has one, its go.mod requirement otherwise, or the version at the end of
+// its chain for modules go.mod does not list
+func whyTarget(path string, chain []string, required, updates []dependency.Dependency) dependency.Dependency {
+	for _, dep := range updates {
+		if dep.Path == path && !dep.IsMajorUpgrade() {
+			return dep
+		}
+	}
+	for _, dep := range required {
+		if dep.Path == path {
+			return dep
+		}
+	}
+
+	// Not listed by go.mod, so only required by other modules
+	_, version, _ := strings.Cut(chain[len(chain)-1], "@")
+	return dependency.Dependency{Path: path, Version: version, Indirect: true}
+}
+
+// requirementGraph returns the module graph and the requirements of go.mod
+func (a *App) requirementGraph(ctx context.Context) (*modgraph.Graph, []dependency.Dependency, error) {
+	required, err := a.depMgr.GetDependencies()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var graph *modgraph.Graph
+	a.withCommandTimeout(ctx, func(ctx context.Context) {
+		graph, err = a.sources.Requires.Graph(ctx)
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading the module graph: %w", err)
+	}
+	return graph, required, nil
+}
+
+// findPulls reads the requirements of the new version of every direct
+// dependency update, by required module path
+func (a *App) findPulls(ctx context.Context, updates []dependency.Dependency) map[string][]dependency.Pull {
+	var direct []dependency.Dependency
+	for _, dep := range updates {
+		if !dep.Indirect && dep.HasUpdate {
+			direct = append(direct, dep)
+		}
+	}
+
+	pulls := make(map[string][]dependency.Pull)
+	if len(direct) == 0 {
+		return pulls
+	}
+
+	a.annotate(ctx, direct, "requirements", func(ctx context.Context, dep *dependency.Dependency) {
+		requires, err := a.sources.Requires.Requires(ctx, dep.TargetPath(), dep.NewVersion)
+		if err != nil {
+			a.console.Debug("Cannot read the requirements of %s@%s: %v", dep.TargetPath(), dep.NewVersion, err)
+			return
+		}
+		for path, version := range requires {
+			pulls[path] = append(pulls[path], dependency.Pull{Path: dep.TargetPath(), Version: dep.NewVersion, Requires: version})
+		}
+	})
+	return pulls
+}
+
+// pullsOf returns the updates requiring a newer version of dep than its current one
+func pullsOf(pulls map[string][]dependency.Pull, dep dependency.Dependency) []dependency.Pull {
+	var result []dependency.Pull
+	for _, pull := range pulls[dep.Path] {
+		if semver.Compare(pull.Requires, dep.Version) > 0 {
+			result = append(result, pull)
+		}
+	}
+	return result
+}
+
+func hasIndirect(deps []dependency.Dependency) bool {
+	for _, dep := range deps {
+		if dep.Indirect {
+			return true
+		}
+	}
+	return false
+}
+
+// directPaths returns the paths of the direct requirements of go.mod
+func directPaths(required []dependency.Dependency) map[string]bool {
+	direct := make(map[string]bool)
+	for _, dep := range required {
+		if !dep.Indirect {
+			direct[dep.Path] = true
+		}
+	}
+	return direct
+}
@@ -0,0 +1,185 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"goup/internal/config"
+	"goup/internal/dependency"
+	"goup/internal/mocks"
+	"goup/internal/modgraph"
+	"goup/internal/ui"
+)
+
+// fakeRequires serves a canned module graph and go.mod requirements keyed by "path@version"
+type fakeRequires struct {
+	graph    string
+	requires map[string]map[string]string
+}
+
+func (f fakeRequires) Graph(ctx context.Context) (*modgraph.Graph, error) {
+	return modgraph.Parse(strings.NewReader(f.graph))
+}
+
+func (f fakeRequires) Requires(ctx context.Context, path, version string) (map[string]string, error) {
+	requires, ok := f.requires[path+"@"+version]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return requires, nil
+}
+
+var testRequires = fakeRequires{
+	graph: `example.com/app github.com/gin-gonic/gin@v1.9.1
+example.com/app golang.org/x/net@v0.10.0
+example.com/app golang.org/x/text@v0.9.0
+github.com/gin-gonic/gin@v1.9.1 golang.org/x/net@v0.10.0
+golang.org/x/net@v0.10.0 golang.org/x/text@v0.9.0
+`,
+	requires: map[string]map[string]string{
+		"github.com/gin-gonic/gin@v1.9.2": {"golang.org/x/net": "v0.17.0", "golang.org/x/text": "v0.9.0"},
+	},
+}
+
+// testRequired are the go.mod requirements of the graph above
+var testRequired = []dependency.Dependency{
+	{Path: "github.com/gin-gonic/gin", Version: "v1.9.1"},
+	{Path: "golang.org/x/net", Version: "v0.10.0", Indirect: true},
+	{Path: "golang.org/x/text", Version: "v0.9.0", Indirect: true},
+}
+
+func TestRunListExplainsIndirectDependencies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{List: true, All: true}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+		{Path: "golang.org/x/net", Version: "v0.10.0", NewVersion: "v0.17.0", HasUpdate: true, Indirect: true},
+		{Path: "golang.org/x/text", Version: "v0.9.0", NewVersion: "v0.14.0", HasUpdate: true, Indirect: true},
+	}
+
+	explained := append([]dependency.Dependency(nil), deps...)
+	explained[1].Chain = []string{"example.com/app", "github.com/gin-gonic/gin@v1.9.1", "golang.org/x/net@v0.10.0"}
+	explained[1].PulledBy = []dependency.Pull{{Path: "github.com/gin-gonic/gin", Version: "v1.9.2", Requires: "v0.17.0"}}
+	explained[2].Chain = []string{"example.com/app", "github.com/gin-gonic/gin@v1.9.1", "golang.org/x/net@v0.10.0", "golang.org/x/text@v0.9.0"}
+
+	console.EXPECT().Header()
+	console.EXPECT().PrintReport(gomock.Any())
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().ProgressBar(gomock.Any(), 1, gomock.Any()).Times(2)
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(deps, nil)
+	depMgr.EXPECT().FilterDependencies(deps, true).Return(deps)
+	depMgr.EXPECT().GetDependencies().Return(testRequired, nil)
+	console.EXPECT().PrintDependencies(explained, "Found 3 all dependencies with available updates:")
+
+	app := NewWithSources(cfg, console, depMgr, mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl), Sources{Requires: testRequires})
+	err := app.Run(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func TestRunWhy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{WhyModule: "golang.org/x/net"}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+
+	updates := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+		{Path: "golang.org/x/net", Version: "v0.10.0", NewVersion: "v0.17.0", HasUpdate: true, Indirect: true},
+	}
+	expected := updates[1]
+	expected.Chain = []string{"example.com/app", "github.com/gin-gonic/gin@v1.9.1", "golang.org/x/net@v0.10.0"}
+	expected.PulledBy = []dependency.Pull{{Path: "github.com/gin-gonic/gin", Version: "v1.9.2", Requires: "v0.17.0"}}
+
+	var report ui.Report
+	console.EXPECT().Header()
+	console.EXPECT().PrintReport(gomock.Any()).Do(func(r ui.Report) { report = r })
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	console.EXPECT().ProgressBar(gomock.Any(), 1, gomock.Any()).Times(2)
+	depMgr.EXPECT().GetDependencies().Return(testRequired, nil)
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(updates, nil)
+	console.EXPECT().PrintRequirements(expected)
+
+	app := NewWithSources(cfg, console, depMgr, mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl), Sources{Requires: testRequires})
+	err := app.Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, ui.ModeWhy, report.Mode)
+	assert.Equal(t, []dependency.Dependency{expected}, report.Dependencies)
+}
+
+func TestRunWhyDirectDependency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{WhyModule: "github.com/gin-gonic/gin"}
+	console := mocks.NewMockConsole(ctrl)
+	depMgr := mocks.NewMockManager(ctrl)
+
+	expected := dependency.Dependency{Path: "github.com/gin-gonic/gin", Version: "v1.9.1",
+		Chain: []string{"example.com/app", "github.com/gin-gonic/gin@v1.9.1"}}
+
+	console.EXPECT().Header()
+	console.EXPECT().PrintReport(gomock.Any())
+	console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	depMgr.EXPECT().GetDependencies().Return(testRequired, nil)
+	depMgr.EXPECT().GetUpdatableDependencies(gomock.Any()).Return(nil, nil)
+	console.EXPECT().PrintRequirements(expected)
+
+	app := NewWithSources(cfg, console, depMgr, mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl), Sources{Requires: testRequires})
+	err := app.Run(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func TestRunWhyErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources Sources
+		wantErr string
+	}{
+		{
+			name:    "module not required",
+			sources: Sources{Requires: testRequires},
+			wantErr: "example.com/other is not required by the module",
+		},
+		{
+			name:    "no module graph",
+			wantErr: "the module graph is not available",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			cfg := &config.Config{WhyModule: "example.com/other"}
+			console := mocks.NewMockConsole(ctrl)
+			depMgr := mocks.NewMockManager(ctrl)
+
+			console.EXPECT().Header()
+			console.EXPECT().PrintReport(gomock.Any())
+			console.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+			depMgr.EXPECT().GetDependencies().Return(testRequired, nil).AnyTimes()
+
+			app := NewWithSources(cfg, console, depMgr, mocks.NewMockSelector(ctrl), mocks.NewMockUpdater(ctrl), tt.sources)
+			err := app.Run(context.Background())
+
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// Package changelog reads the changelog shipped in module zips and compares
+// it between the current and the new version of a dependency.
+package changelog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"goup/internal/dependency"
+	"goup/internal/diff"
+	"goup/internal/modzip"
+)
+
+// ErrNoChangelog is returned when a module version has no changelog file
+var ErrNoChangelog = errors.New("no changelog in the module")
+
+// names are the changelog file names looked for at the root of a module, in
+// order of preference. They are matched without regard to case.
+var names = []string{
+	"CHANGELOG.md",
+	"CHANGELOG",
+	"CHANGELOG.txt",
+	"CHANGES.md",
+	"CHANGES",
+	"HISTORY.md",
+	"NEWS.md",
+	"RELEASE_NOTES.md",
+	"RELEASE-NOTES.md",
+	"RELEASES.md",
+}
+
+// contextLines is the number of unchanged lines shown around each change
+const contextLines = 3
+
+// Fetcher reads changelogs from module zips
+type Fetcher struct {
+	modules *modzip.Cache
+}
+
+// NewFetcher creates a fetcher reading the module zips from modules
+func NewFetcher(modules *modzip.Cache) *Fetcher {
+	return &Fetcher{modules: modules}
+}
+
+// Changes returns the unified diff of the changelog between the current and
+// the new version of dep, which is empty if the changelog did not change. A
+// changelog missing from the current version is compared as empty; one
+// missing from the new version is an ErrNoChangelog error.
+func (f *Fetcher) Changes(ctx context.Context, dep dependency.Dependency) (string, error) {
+	newName, newText, err := f.Read(ctx, dep.TargetPath(), dep.NewVersion)
+	if err != nil {
+		return "", err
+	}
+
+	oldName, oldText, err := f.Read(ctx, dep.Path, dep.Version)
+	switch {
+	case errors.Is(err, ErrNoChangelog):
+		oldName = newName
+	case err != nil:
+		return "", err
+	}
+
+	return diff.Unified(
+		dep.Path+"@"+dep.Version+"/"+oldName,
+		dep.TargetPath()+"@"+dep.NewVersion+"/"+newName,
+		oldText, newText, contextLines,
+	), nil
+}
+
+// Read returns the name and content of the changelog of a module version
+func (f *Fetcher) Read(ctx context.Context, path, version string) (string, string, error) {
+	m, err := f.modules.Open(ctx, path, version)
+	if err != nil {
+		return "", "", err
+	}
+
+	name := find(m.Files())
+	if name == "" {
+		return "", "", fmt.Errorf("%s@%s: %w", path, version, ErrNoChangelog)
+	}
+
+	data, err := m.ReadFile(name)
+	if err != nil {
+		return "", "", fmt.Errorf("reading changelog of %s@%s: %w", path, version, err)
+	}
+	// Changelogs written on Windows would differ on every line
+	return name, strings.ReplaceAll(string(data), "\r\n", "\n"), nil
+}
+
+// find returns the preferred changelog file at the root of a module
+func find(files []string) string {
+	best, bestRank := "", len(names)
+	for _, name := range files {
+		if strings.Contains(name, "/") {
+			continue
+		}
+
+		for rank, candidate := range names[:bestRank] {
+			if strings.EqualFold(name, candidate) {
+				best, bestRank = name, rank
+				break
+			}
+		}
+	}
+	return best
+}
@@ -0,0 +1,130 @@
+package changelog
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"goup/internal/dependency"
+	"goup/internal/modzip"
+	"goup/internal/proxy"
+)
+
+// fakeSource serves module zips keyed by "path@version"
+type fakeSource map[string][]byte
+
+func (s fakeSource) Zip(ctx context.Context, path, version string) ([]byte, error) {
+	data, ok := s[path+"@"+version]
+	if !ok {
+		return nil, proxy.ErrNotFound
+	}
+	return data, nil
+}
+
+// moduleZip builds a module zip holding the given files
+func moduleZip(t *testing.T, path, version string, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(path + "@" + version + "/" + name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestChanges(t *testing.T) {
+	source := fakeSource{
+		"example.com/lib@v1.0.0": moduleZip(t, "example.com/lib", "v1.0.0", map[string]string{
+			"CHANGELOG.md": "# Changelog\n\n## v1.0.0\n- First release\n",
+			"go.mod":       "module example.com/lib\n",
+		}),
+		"example.com/lib@v1.1.0": moduleZip(t, "example.com/lib", "v1.1.0", map[string]string{
+			"CHANGELOG.md": "# Changelog\n\n## v1.1.0\n- Add Foo\n\n## v1.0.0\n- First release\n",
+			"go.mod":       "module example.com/lib\n",
+		}),
+	}
+	fetcher := NewFetcher(modzip.NewCache(source, ""))
+
+	changes, err := fetcher.Changes(context.Background(), dependency.Dependency{
+		Path: "example.com/lib", Version: "v1.0.0", NewVersion: "v1.1.0", HasUpdate: true,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "--- example.com/lib@v1.0.0/CHANGELOG.md\n"+
+		"+++ example.com/lib@v1.1.0/CHANGELOG.md\n"+
+		"@@ -1,4 +1,7 @@\n"+
+		" # Changelog\n"+
+		" \n"+
+		"+## v1.1.0\n"+
+		"+- Add Foo\n"+
+		"+\n"+
+		" ## v1.0.0\n"+
+		" - First release\n", changes)
+}
+
+func TestChangesMajorUpgrade(t *testing.T) {
+	source := fakeSource{
+		"example.com/lib@v1.0.0":    moduleZip(t, "example.com/lib", "v1.0.0", map[string]string{"go.mod": "module example.com/lib\n"}),
+		"example.com/lib/v2@v2.0.0": moduleZip(t, "example.com/lib/v2", "v2.0.0", map[string]string{"changes": "v2.0.0: new API\n"}),
+	}
+	fetcher := NewFetcher(modzip.NewCache(source, ""))
+
+	changes, err := fetcher.Changes(context.Background(), dependency.Dependency{
+		Path: "example.com/lib", Version: "v1.0.0", NewPath: "example.com/lib/v2", NewVersion: "v2.0.0", HasUpdate: true,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "--- example.com/lib@v1.0.0/changes\n+++ example.com/lib/v2@v2.0.0/changes\n@@ -0,0 +1 @@\n+v2.0.0: new API\n", changes,
+		"A changelog missing from the current version is compared as empty")
+}
+
+func TestChangesWithoutChangelog(t *testing.T) {
+	source := fakeSource{
+		"example.com/lib@v1.1.0": moduleZip(t, "example.com/lib", "v1.1.0", map[string]string{
+			"docs/CHANGELOG.md": "Not at the root\n",
+		}),
+	}
+	fetcher := NewFetcher(modzip.NewCache(source, ""))
+
+	_, err := fetcher.Changes(context.Background(), dependency.Dependency{
+		Path: "example.com/lib", Version: "v1.0.0", NewVersion: "v1.1.0", HasUpdate: true,
+	})
+
+	assert.ErrorIs(t, err, ErrNoChangelog)
+}
+
+func TestChangesNotDownloadable(t *testing.T) {
+	fetcher := NewFetcher(modzip.NewCache(fakeSource{}, ""))
+
+	_, err := fetcher.Changes(context.Background(), dependency.Dependency{
+		Path: "example.com/lib", Version: "v1.0.0", NewVersion: "v1.1.0", HasUpdate: true,
+	})
+
+	assert.ErrorIs(t, err, proxy.ErrNotFound)
+	assert.ErrorContains(t, err, "downloading example.com/lib@v1.1.0")
+}
+
+func TestReadChoosesPreferredName(t *testing.T) {
+	source := fakeSource{
+		"example.com/lib@v1.0.0": moduleZip(t, "example.com/lib", "v1.0.0", map[string]string{
+			"NEWS.md":      "news\n",
+			"changelog.md": "changelog\r\nwith windows line endings\r\n",
+			"README.md":    "readme\n",
+		}),
+	}
+	fetcher := NewFetcher(modzip.NewCache(source, ""))
+
+	name, text, err := fetcher.Read(context.Background(), "example.com/lib", "v1.0.0")
+
+	require.NoError(t, err)
+	assert.Equal(t, "changelog.md", name)
+	assert.Equal(t, "changelog\nwith windows line endings\n", text)
+}
@@ -1,13 +1,59 @@
 package config
 
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"goup/internal/dependency"
+)
+
+// Output formats
+const (
+	FormatText = "text" // Colored tables for humans
+	FormatJSON = "json" // A single JSON document for tools
+)
+
+// DefaultVerifyCommand is the check run after each update in verify mode
+const DefaultVerifyCommand = "go build ./... && go test ./..."
+
 // Config holds all configuration options for the application
 type Config struct {
-	List        bool // List all updateable dependencies
-	Interactive bool // Ask for confirmation before updating
-	Verbose     bool // Show detailed output
-	NoColor     bool // Disable colored output
-	All         bool // Update indirect dependencies as well
-	Selective   bool // Interactively select which dependencies to update
+	List           bool              // List all updateable dependencies
+	Interactive    bool              // Ask for confirmation before updating
+	Verbose        bool              // Show detailed output
+	NoColor        bool              // Disable colored output
+	All            bool              // Update indirect dependencies as well
+	Selective      bool              // Interactively select which dependencies to update
+	Transitive     bool              // Use 'go get -u' so updated modules also upgrade their own dependencies
+	Batch          bool              // Apply all updates in a single 'go get', falling back to one at a time if it fails
+	Changelog      bool              // Show what changed in the changelog of each module before updating
+	APIDiff        bool              // Compare the exported API of each update and flag breaking changes
+	Impact         bool              // Report which packages of the main module use each dependency
+	DiffModule     string            // Module, optionally with @version, whose API changes 'goup diff' reports
+	WhyModule      string            // Module whose requirement chain 'goup why' reports
+	ExcludeModule  string            // Module@version 'goup exclude' adds an exclude directive for
+	ExcludeReason  string            // Comment of the exclude directive added by 'goup exclude'
+	Format         string            // Output format (text or json)
+	FailOnUpdates  bool              // Exit with a dedicated code when updates are available in list mode
+	Policy         dependency.Policy // Which newer versions are acceptable (patch, minor, major)
+	DiscoverMajors bool              // Offer upgrades to newer major version module paths (/v2, /v3...)
+	Rules          []Rule            // Per-module rules loaded from the configuration file
+	DryRun         bool              // Apply the updates to a copy of go.mod and go.sum and show the diff instead
+	Rollback       bool              // Restore go.mod and go.sum from the snapshot taken by the last run
+	KeepPartial    bool              // Keep the successful updates when others fail instead of rolling back
+	Verify         bool              // Run the verify command after each update and revert the failing ones
+	VerifyCommand  string            // Check used in verify mode (DefaultVerifyCommand if empty)
+	Recursive      bool              // Run on every module found below the target directory
+	SyncVersions   bool              // In recursive mode, update a dependency to the same version in every module
+	Security       bool              // Only update vulnerable modules, to the minimal fixed version
+	VulnDB         string            // Local OSV vulnerability database (directory or file) used in security mode
+	OnlyRetracted  bool              // Only update modules whose current version is retracted by their author
+	Replaced       bool              // Include modules replaced in go.mod, updating the replace directive of forks
+	DropReplaces   bool              // Drop the replace directives of forks whose upstream has caught up
+	Jobs           int               // Concurrent module version queries (dependency.DefaultJobs if zero)
+	Timeout        time.Duration     // Limit of the whole run, zero for none
+	CommandTimeout time.Duration     // Limit of each external command and module query, zero for none
 }
 
 // ShouldIncludeIndirect returns true if indirect dependencies should be included
@@ -15,7 +61,136 @@ func (c *Config) ShouldIncludeIndirect() bool {
 	return c.All
 }
 
+// ShouldDiscoverMajors returns true if newer major version module paths are
+// offered: with --discover-majors, or with the major policy, which would
+// otherwise pick the same versions as the default one
+func (c *Config) ShouldDiscoverMajors() bool {
+	return c.DiscoverMajors || c.Policy == dependency.PolicyMajor
+}
+
 // IsInteractiveMode returns true if any interactive mode is enabled
 func (c *Config) IsInteractiveMode() bool {
-	return c.Interactive || c.Selective
+	return c.IsSelective() || (c.Interactive && !c.List)
+}
+
+// IsSelective returns true if the dependencies to update are chosen
+// interactively. Listing never prompts, so --list wins over --select.
+func (c *Config) IsSelective() bool {
+	return c.Selective && !c.List
+}
+
+// IsJSON returns true if output should be a machine-readable JSON document
+func (c *Config) IsJSON() bool {
+	return c.Format == FormatJSON
+}
+
+// GetVerifyCommand returns the check to run after each update in verify mode
+func (c *Config) GetVerifyCommand() string {
+	if c.VerifyCommand == "" {
+		return DefaultVerifyCommand
+	}
+	return c.VerifyCommand
+}
+
+// GetJobs returns the number of module version queries to run concurrently
+func (c *Config) GetJobs() int {
+	if c.Jobs == 0 {
+		return dependency.DefaultJobs
+	}
+	return c.Jobs
+}
+
+// VerifySteps splits the verify command (e.g. "go build ./... && go test ./...")
+// into the commands to run in order. Arguments are separated by whitespace and
+// no shell is involved, so the check behaves the same on every platform.
+func (c *Config) VerifySteps() ([][]string, error) {
+	command := c.GetVerifyCommand()
+
+	var steps [][]string
+	for _, part := range strings.Split(command, "&&") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("invalid verify command %q: empty step", command)
+		}
+		steps = append(steps, fields)
+	}
+
+	return steps, nil
+}
+
+// RuleFor returns the first rule matching a module path, or nil if none applies
+func (c *Config) RuleFor(path string) *Rule {
+	for i := range c.Rules {
+		if c.Rules[i].Matches(path) {
+			return &c.Rules[i]
+		}
+	}
+	return nil
+}
+
+// Validate checks that the configured options can be used together
+func (c *Config) Validate() error {
+	switch c.Format {
+	case "", FormatText, FormatJSON:
+	default:
+		return fmt.Errorf("unknown output format %q (expected %q or %q)", c.Format, FormatText, FormatJSON)
+	}
+
+	if c.IsJSON() && c.IsInteractiveMode() {
+		return fmt.Errorf("--format=%s cannot be combined with --interactive or --select", FormatJSON)
+	}
+
+	if c.Rollback && (c.List || c.Selective) {
+		return fmt.Errorf("--rollback cannot be combined with --list or --select")
+	}
+
+	if c.DryRun && (c.List || c.Rollback || c.Verify) {
+		return fmt.Errorf("--dry-run cannot be combined with --list, --rollback or --verify")
+	}
+
+	if c.Jobs < 0 {
+		return fmt.Errorf("--jobs cannot be negative (0 uses the default)")
+	}
+
+	if c.Timeout < 0 || c.CommandTimeout < 0 {
+		return fmt.Errorf("--timeout and --command-timeout cannot be negative")
+	}
+
+	if c.Security && c.VulnDB == "" {
+		return fmt.Errorf("--security requires --vuln-db")
+	}
+
+	if c.Security && c.DiscoverMajors {
+		return fmt.Errorf("--security cannot be combined with --discover-majors")
+	}
+
+	if c.OnlyRetracted && c.Security {
+		return fmt.Errorf("--only-retracted cannot be combined with --security")
+	}
+
+	if c.DropReplaces && (c.Security || c.OnlyRetracted || c.DiscoverMajors || c.Rollback) {
+		return fmt.Errorf("goup drop-replaces cannot be combined with --security, --only-retracted, --discover-majors or --rollback")
+	}
+
+	if c.DiffModule != "" && (c.Recursive || c.Rollback) {
+		return fmt.Errorf("goup diff cannot be combined with --recursive or --rollback")
+	}
+
+	if c.WhyModule != "" && (c.Recursive || c.Rollback) {
+		return fmt.Errorf("goup why cannot be combined with --recursive or --rollback")
+	}
+
+	if c.ExcludeModule != "" && (c.Recursive || c.Rollback || c.DryRun) {
+		return fmt.Errorf("goup exclude cannot be combined with --recursive, --rollback or --dry-run")
+	}
+
+	if c.SyncVersions && !c.Recursive {
+		return fmt.Errorf("--sync-versions requires --recursive")
+	}
+
+	if _, err := c.VerifySteps(); c.Verify && err != nil {
+		return err
+	}
+
+	return nil
 }
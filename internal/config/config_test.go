@@ -4,6 +4,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"goup/internal/dependency"
 )
 
 func TestShouldIncludeIndirect(t *testing.T) {
@@ -58,6 +61,11 @@ func TestIsInteractiveMode(t *testing.T) {
 			config:   Config{Interactive: true, Selective: true},
 			expected: true,
 		},
+		{
+			name:     "list mode never prompts",
+			config:   Config{List: true, Interactive: true, Selective: true},
+			expected: false,
+		},
 		{
 			name:     "no interactive flags",
 			config:   Config{Interactive: false, Selective: false},
@@ -97,6 +105,7 @@ func TestDefaultValues(t *testing.T) {
 	assert.False(t, config.NoColor)
 	assert.False(t, config.All)
 	assert.False(t, config.Selective)
+	assert.False(t, config.Transitive)
 }
 
 func TestAllFieldsCombination(t *testing.T) {
@@ -116,5 +125,155 @@ func TestAllFieldsCombination(t *testing.T) {
 	assert.True(t, config.All)
 	assert.True(t, config.Selective)
 	assert.True(t, config.ShouldIncludeIndirect())
-	assert.True(t, config.IsInteractiveMode())
+	// Listing never prompts
+	assert.False(t, config.IsInteractiveMode())
+}
+
+func TestIsSelective(t *testing.T) {
+	assert.True(t, (&Config{Selective: true}).IsSelective())
+	assert.False(t, (&Config{Selective: true, List: true}).IsSelective())
+	assert.False(t, (&Config{}).IsSelective())
+}
+
+func TestIsJSON(t *testing.T) {
+	assert.True(t, (&Config{Format: FormatJSON}).IsJSON())
+	assert.False(t, (&Config{Format: FormatText}).IsJSON())
+	assert.False(t, (&Config{}).IsJSON())
+}
+
+func TestShouldDiscoverMajors(t *testing.T) {
+	assert.True(t, (&Config{DiscoverMajors: true}).ShouldDiscoverMajors())
+	assert.True(t, (&Config{Policy: dependency.PolicyMajor}).ShouldDiscoverMajors(), "--major implies --discover-majors")
+	assert.False(t, (&Config{Policy: dependency.PolicyMinor}).ShouldDiscoverMajors())
+	assert.False(t, (&Config{}).ShouldDiscoverMajors())
+}
+
+func TestGetJobs(t *testing.T) {
+	assert.Equal(t, dependency.DefaultJobs, (&Config{}).GetJobs())
+	assert.Equal(t, 32, (&Config{Jobs: 32}).GetJobs())
+	assert.NoError(t, (&Config{Jobs: 0}).Validate(), "0 uses the default")
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr string
+	}{
+		{
+			name:   "default config",
+			config: Config{},
+		},
+		{
+			name:   "text format",
+			config: Config{Format: FormatText, Interactive: true},
+		},
+		{
+			name:   "json format",
+			config: Config{Format: FormatJSON, List: true},
+		},
+		{
+			name:    "unknown format",
+			config:  Config{Format: "yaml"},
+			wantErr: `unknown output format "yaml"`,
+		},
+		{
+			name:    "json with interactive",
+			config:  Config{Format: FormatJSON, Interactive: true},
+			wantErr: "cannot be combined with --interactive or --select",
+		},
+		{
+			name:    "rollback with list",
+			config:  Config{Rollback: true, List: true},
+			wantErr: "--rollback cannot be combined with --list or --select",
+		},
+		{
+			name:    "only retracted with security",
+			config:  Config{OnlyRetracted: true, Security: true, VulnDB: "osv"},
+			wantErr: "--only-retracted cannot be combined with --security",
+		},
+		{
+			name:    "drop replaces with rollback",
+			config:  Config{DropReplaces: true, Rollback: true},
+			wantErr: "goup drop-replaces cannot be combined with --security, --only-retracted, --discover-majors or --rollback",
+		},
+		{
+			name:    "exclude with dry run",
+			config:  Config{ExcludeModule: "example.com/lib@v1.2.0", DryRun: true},
+			wantErr: "goup exclude cannot be combined with --recursive, --rollback or --dry-run",
+		},
+		{
+			name:    "dry run with verify",
+			config:  Config{DryRun: true, Verify: true},
+			wantErr: "--dry-run cannot be combined with --list, --rollback or --verify",
+		},
+		{
+			name:    "negative jobs",
+			config:  Config{Jobs: -1},
+			wantErr: "--jobs cannot be negative (0 uses the default)",
+		},
+		{
+			name:   "security with database",
+			config: Config{Security: true, VulnDB: "vulndb"},
+		},
+		{
+			name:    "security without database",
+			config:  Config{Security: true},
+			wantErr: "--security requires --vuln-db",
+		},
+		{
+			name:    "security with major discovery",
+			config:  Config{Security: true, VulnDB: "vulndb", DiscoverMajors: true},
+			wantErr: "--security cannot be combined with --discover-majors",
+		},
+		{
+			name:    "sync versions without recursive",
+			config:  Config{SyncVersions: true},
+			wantErr: "--sync-versions requires --recursive",
+		},
+		{
+			name:    "diff with recursive",
+			config:  Config{DiffModule: "example.com/lib", Recursive: true},
+			wantErr: "goup diff cannot be combined with --recursive or --rollback",
+		},
+		{
+			name:    "why with rollback",
+			config:  Config{WhyModule: "golang.org/x/net", Rollback: true},
+			wantErr: "goup why cannot be combined with --recursive or --rollback",
+		},
+		{
+			name:    "verify with empty step",
+			config:  Config{Verify: true, VerifyCommand: "go build ./... &&"},
+			wantErr: "invalid verify command",
+		},
+		{
+			name:    "json with select",
+			config:  Config{Format: FormatJSON, Selective: true},
+			wantErr: "cannot be combined with --interactive or --select",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestVerifySteps(t *testing.T) {
+	steps, err := (&Config{}).VerifySteps()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"go", "build", "./..."}, {"go", "test", "./..."}}, steps)
+
+	steps, err = (&Config{VerifyCommand: "make lint&&go test -race ./..."}).VerifySteps()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"make", "lint"}, {"go", "test", "-race", "./..."}}, steps)
+
+	_, err = (&Config{VerifyCommand: "  "}).VerifySteps()
+	assert.Error(t, err)
 }
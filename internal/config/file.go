@@ -0,0 +1,210 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"goup/internal/dependency"
+	"goup/internal/pattern"
+)
+
+// FileNames lists the configuration files looked up in the project directory, in order
+var FileNames = []string{".goup.yaml", ".goup.yml", ".goup.toml"}
+
+// File holds the contents of a repo-local configuration file. Unset fields
+// leave the corresponding CLI defaults untouched.
+type File struct {
+	All           *bool   `yaml:"all" toml:"all"`
+	Verbose       *bool   `yaml:"verbose" toml:"verbose"`
+	Policy        *string `yaml:"policy" toml:"policy"`
+	VerifyCommand *string `yaml:"verify_command" toml:"verify_command"`
+	VulnDB        *string `yaml:"vuln_db" toml:"vuln_db"`
+	Jobs          *int    `yaml:"jobs" toml:"jobs"`
+	Rules         []Rule  `yaml:"rules" toml:"rules"`
+}
+
+// Rule customises how goup treats the modules matching a pattern
+type Rule struct {
+	Module string `yaml:"module" toml:"module"` // Module path or pattern (e.g. "golang.org/x/*")
+	Ignore bool   `yaml:"ignore" toml:"ignore"` // Never offer updates for matching modules
+	Pin    string `yaml:"pin" toml:"pin"`       // Allowed version range (e.g. ">=v1.2.0 <v1.5.0")
+	Allow  string `yaml:"allow" toml:"allow"`   // Update policy for matching modules (patch, minor, major)
+}
+
+// Matches reports whether the rule applies to a module path. Rules use the
+// same pattern syntax as the interactive selector.
+func (r Rule) Matches(path string) bool {
+	return pattern.Select(path, r.Module)
+}
+
+// PinRange returns the parsed version range of the rule, if it has one
+func (r Rule) PinRange() (dependency.VersionRange, bool, error) {
+	if r.Pin == "" {
+		return dependency.VersionRange{}, false, nil
+	}
+	versionRange, err := dependency.ParseRange(r.Pin)
+	return versionRange, err == nil, err
+}
+
+// AllowPolicy returns the update policy of the rule, or the default policy if unset
+func (r Rule) AllowPolicy(defaultPolicy dependency.Policy) dependency.Policy {
+	if r.Allow == "" {
+		return defaultPolicy
+	}
+	return dependency.Policy(r.Allow)
+}
+
+// FindFile returns the path of the configuration file in dir, or an empty
+// string if there is none
+func FindFile(dir string) string {
+	for _, name := range FileNames {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// LoadFile reads, parses and validates a configuration file. The format is
+// chosen by extension: .toml files are TOML, anything else is YAML.
+func LoadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	var file *File
+	if filepath.Ext(path) == ".toml" {
+		file, err = parseTOML(data)
+	} else {
+		file, err = parseYAML(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	if err := file.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s:\n%w", path, err)
+	}
+
+	return file, nil
+}
+
+func parseYAML(data []byte) (*File, error) {
+	file := &File{}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+func parseTOML(data []byte) (*File, error) {
+	file := &File{}
+
+	meta, err := toml.Decode(string(data), file)
+	if err != nil {
+		return nil, err
+	}
+
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, 0, len(undecoded))
+		for _, key := range undecoded {
+			keys = append(keys, key.String())
+		}
+		return nil, fmt.Errorf("unknown fields: %s", strings.Join(keys, ", "))
+	}
+
+	return file, nil
+}
+
+// Validate checks the file against the schema and reports every problem found
+func (f *File) Validate() error {
+	var errs []error
+
+	if f.Policy != nil {
+		if _, err := dependency.ParsePolicy(*f.Policy); err != nil {
+			errs = append(errs, fmt.Errorf("  - policy: %w", err))
+		}
+	}
+
+	if f.VerifyCommand != nil {
+		cfg := Config{Verify: true, VerifyCommand: *f.VerifyCommand}
+		if _, err := cfg.VerifySteps(); err != nil || strings.TrimSpace(*f.VerifyCommand) == "" {
+			errs = append(errs, fmt.Errorf("  - verify_command: must be one or more commands separated by &&"))
+		}
+	}
+
+	if f.Jobs != nil && *f.Jobs < 1 {
+		errs = append(errs, fmt.Errorf("  - jobs: must be at least 1"))
+	}
+
+	for i, rule := range f.Rules {
+		field := fmt.Sprintf("rules[%d]", i)
+
+		if strings.TrimSpace(rule.Module) == "" {
+			errs = append(errs, fmt.Errorf("  - %s.module: is required", field))
+		}
+
+		if rule.Ignore && (rule.Pin != "" || rule.Allow != "") {
+			errs = append(errs, fmt.Errorf("  - %s: ignore cannot be combined with pin or allow", field))
+		}
+
+		if _, _, err := rule.PinRange(); err != nil {
+			errs = append(errs, fmt.Errorf("  - %s.pin: %w", field, err))
+		}
+
+		if rule.Allow != "" {
+			if policy, err := dependency.ParsePolicy(rule.Allow); err != nil || policy == dependency.PolicyLatest {
+				errs = append(errs, fmt.Errorf("  - %s.allow: unknown update policy %q (expected %q, %q or %q)",
+					field, rule.Allow, dependency.PolicyPatch, dependency.PolicyMinor, dependency.PolicyMajor))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Apply copies the file settings into the configuration. Settings whose CLI
+// flag was set explicitly (listed in explicit by flag name) are kept, so the
+// command line always overrides the file.
+func (f *File) Apply(cfg *Config, explicit map[string]bool) {
+	if f.All != nil && !explicit["all"] {
+		cfg.All = *f.All
+	}
+
+	if f.Verbose != nil && !explicit["verbose"] {
+		cfg.Verbose = *f.Verbose
+	}
+
+	if f.Policy != nil && !explicit["patch"] && !explicit["minor"] && !explicit["major"] {
+		// Validate has already rejected unknown policies
+		cfg.Policy, _ = dependency.ParsePolicy(*f.Policy)
+	}
+
+	if f.VerifyCommand != nil && !explicit["verify-cmd"] {
+		cfg.VerifyCommand = *f.VerifyCommand
+	}
+
+	if f.Jobs != nil && !explicit["jobs"] {
+		cfg.Jobs = *f.Jobs
+	}
+
+	if f.VulnDB != nil && !explicit["vuln-db"] {
+		cfg.VulnDB = *f.VulnDB
+	}
+
+	cfg.Rules = append(cfg.Rules, f.Rules...)
+}
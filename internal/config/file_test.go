@@ -0,0 +1,188 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"goup/internal/dependency"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoadFile(t *testing.T) {
+	yamlContent := `
+all: true
+policy: minor
+rules:
+  - module: github.com/aws/*
+    ignore: true
+  - module: golang.org/x/crypto
+    pin: ">=v0.17.0 <v0.20.0"
+  - module: github.com/gin-gonic/gin
+    allow: patch
+`
+	tomlContent := `
+all = true
+policy = "minor"
+
+[[rules]]
+module = "github.com/aws/*"
+ignore = true
+
+[[rules]]
+module = "golang.org/x/crypto"
+pin = ">=v0.17.0 <v0.20.0"
+
+[[rules]]
+module = "github.com/gin-gonic/gin"
+allow = "patch"
+`
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{name: "yaml", file: ".goup.yaml", content: yamlContent},
+		{name: "toml", file: ".goup.toml", content: tomlContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := LoadFile(writeConfigFile(t, tt.file, tt.content))
+			require.NoError(t, err)
+
+			require.NotNil(t, file.All)
+			assert.True(t, *file.All)
+			assert.Nil(t, file.Verbose)
+			require.NotNil(t, file.Policy)
+			assert.Equal(t, "minor", *file.Policy)
+			assert.Equal(t, []Rule{
+				{Module: "github.com/aws/*", Ignore: true},
+				{Module: "golang.org/x/crypto", Pin: ">=v0.17.0 <v0.20.0"},
+				{Module: "github.com/gin-gonic/gin", Allow: "patch"},
+			}, file.Rules)
+		})
+	}
+}
+
+func TestLoadFileEmpty(t *testing.T) {
+	file, err := LoadFile(writeConfigFile(t, ".goup.yaml", ""))
+
+	require.NoError(t, err)
+	assert.Empty(t, file.Rules)
+}
+
+func TestLoadFileUnknownFields(t *testing.T) {
+	t.Run("yaml", func(t *testing.T) {
+		_, err := LoadFile(writeConfigFile(t, ".goup.yaml", "rules:\n  - module: foo\n    ignored: true\n"))
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field ignored not found")
+	})
+
+	t.Run("toml", func(t *testing.T) {
+		_, err := LoadFile(writeConfigFile(t, ".goup.toml", "polcy = \"patch\"\n"))
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown fields: polcy")
+	})
+}
+
+func TestLoadFileValidation(t *testing.T) {
+	content := `
+policy: newest
+verify_command: "go build ./... && "
+jobs: 0
+rules:
+  - pin: ">=v1.0.0"
+  - module: github.com/foo/bar
+    ignore: true
+    allow: minor
+  - module: github.com/foo/baz
+    pin: "~v1.2"
+  - module: github.com/foo/qux
+    allow: latest
+`
+	path := writeConfigFile(t, ".goup.yaml", content)
+
+	_, err := LoadFile(path)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid "+path)
+	assert.Contains(t, err.Error(), "policy: unknown update policy")
+	assert.Contains(t, err.Error(), "verify_command: must be one or more commands separated by &&")
+	assert.Contains(t, err.Error(), "jobs: must be at least 1")
+	assert.Contains(t, err.Error(), "rules[0].module: is required")
+	assert.Contains(t, err.Error(), "rules[1]: ignore cannot be combined with pin or allow")
+	assert.Contains(t, err.Error(), "rules[2].pin:")
+	assert.Contains(t, err.Error(), "rules[3].allow: unknown update policy \"latest\"")
+}
+
+func TestFindFile(t *testing.T) {
+	dir := t.TempDir()
+	assert.Empty(t, FindFile(dir))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".goup.toml"), nil, 0644))
+	assert.Equal(t, filepath.Join(dir, ".goup.toml"), FindFile(dir))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".goup.yaml"), nil, 0644))
+	assert.Equal(t, filepath.Join(dir, ".goup.yaml"), FindFile(dir), "YAML takes precedence over TOML")
+}
+
+func TestFileApply(t *testing.T) {
+	yes := true
+	patch := "patch"
+	vulnDB := "vulndb"
+	file := &File{
+		All:     &yes,
+		Verbose: &yes,
+		Policy:  &patch,
+		VulnDB:  &vulnDB,
+		Rules:   []Rule{{Module: "github.com/aws/*", Ignore: true}},
+	}
+
+	t.Run("file values fill in defaults", func(t *testing.T) {
+		cfg := &Config{}
+		file.Apply(cfg, map[string]bool{})
+
+		assert.True(t, cfg.All)
+		assert.True(t, cfg.Verbose)
+		assert.Equal(t, dependency.PolicyPatch, cfg.Policy)
+		assert.Equal(t, "vulndb", cfg.VulnDB)
+		assert.Len(t, cfg.Rules, 1)
+	})
+
+	t.Run("explicit flags win", func(t *testing.T) {
+		cfg := &Config{Policy: dependency.PolicyMajor, VulnDB: "osv.json"}
+		file.Apply(cfg, map[string]bool{"all": true, "major": true, "vuln-db": true})
+
+		assert.False(t, cfg.All)
+		assert.Equal(t, "osv.json", cfg.VulnDB)
+		assert.True(t, cfg.Verbose)
+		assert.Equal(t, dependency.PolicyMajor, cfg.Policy)
+	})
+}
+
+func TestRuleFor(t *testing.T) {
+	cfg := &Config{Rules: []Rule{
+		{Module: "golang.org/x/crypto", Pin: "<v0.20.0"},
+		{Module: "golang.org/x/*", Allow: "patch"},
+	}}
+
+	assert.Equal(t, "golang.org/x/crypto", cfg.RuleFor("golang.org/x/crypto").Module)
+	assert.Equal(t, "golang.org/x/*", cfg.RuleFor("golang.org/x/net").Module)
+	assert.Equal(t, "golang.org/x/*", cfg.RuleFor("GOLANG.org/x/net").Module)
+	assert.Nil(t, cfg.RuleFor("github.com/gin-gonic/gin"))
+	// Like in a selection, a glob matches the whole path
+	assert.Nil(t, cfg.RuleFor("example.com/golang.org/x/net"))
+}
@@ -0,0 +1,58 @@
+package dependency
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// exclusions are the module versions excluded by the exclude directives of
+// the main modules, which the go command never selects
+type exclusions map[module.Version]bool
+
+// readExclusions returns the versions excluded by go.mod or, in a workspace,
+// by the go.mod of any workspace module
+func (m *manager) readExclusions() (exclusions, error) {
+	files := m.mainModules
+	if files == nil {
+		files = []string{m.goModPath}
+	}
+
+	excluded := make(exclusions)
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+
+		f, err := modfile.Parse(path, data, nil)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+		for _, x := range f.Exclude {
+			excluded[x.Mod] = true
+		}
+	}
+	return excluded, nil
+}
+
+// filter returns the versions of a module that are not excluded
+func (e exclusions) filter(path string, versions []string) []string {
+	if len(e) == 0 {
+		return versions
+	}
+
+	var kept []string
+	for _, version := range versions {
+		if !e[module.Version{Path: path, Version: version}] {
+			kept = append(kept, version)
+		}
+	}
+	return kept
+}
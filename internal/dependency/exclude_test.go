@@ -0,0 +1,85 @@
+package dependency
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadExclusions(t *testing.T) {
+	goModPath := filepath.Join(t.TempDir(), "go.mod")
+	require.NoError(t, os.WriteFile(goModPath, []byte(`module example.com/app
+
+go 1.21
+
+require example.com/lib v1.0.0
+
+exclude (
+	example.com/lib v1.2.0 // Panics on startup
+	example.com/other v0.3.0
+)
+`), 0644))
+
+	excluded, err := NewManagerWithPath(goModPath).(*manager).readExclusions()
+
+	require.NoError(t, err)
+	assert.Equal(t, exclusions{
+		{Path: "example.com/lib", Version: "v1.2.0"}:   true,
+		{Path: "example.com/other", Version: "v0.3.0"}: true,
+	}, excluded)
+	assert.Equal(t, []string{"v1.0.0", "v1.3.0"}, excluded.filter("example.com/lib", []string{"v1.0.0", "v1.2.0", "v1.3.0"}))
+	assert.Equal(t, []string{"v1.2.0"}, excluded.filter("example.com/third", []string{"v1.2.0"}))
+}
+
+func TestReadExclusionsWorkspace(t *testing.T) {
+	root := writeWorkspace(t)
+	goMod, err := os.ReadFile(filepath.Join(root, "api", "go.mod"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(root, "api", "go.mod"), append(goMod, "\nexclude golang.org/x/crypto v0.15.0\n"...), 0644))
+
+	ws, err := LoadWorkspace(filepath.Join(root, "go.work"))
+	require.NoError(t, err)
+	excluded, err := NewWorkspaceManager(ws).(*workspaceManager).readExclusions()
+
+	require.NoError(t, err)
+	assert.Equal(t, exclusions{{Path: "golang.org/x/crypto", Version: "v0.15.0"}: true}, excluded)
+}
+
+func TestLatestVersionSkipsExcluded(t *testing.T) {
+	source := &fakeSource{
+		versions: map[string][]string{"example.com/lib": {"v1.0.0", "v1.1.0", "v1.2.0"}, "example.com/untagged": {}},
+		latest:   map[string]string{"example.com/untagged": "v0.0.0-20240101000000-abcdefabcdef"},
+	}
+	excluded := exclusions{
+		{Path: "example.com/lib", Version: "v1.2.0"}:                                  true,
+		{Path: "example.com/untagged", Version: "v0.0.0-20240101000000-abcdefabcdef"}: true,
+	}
+
+	latest, err := latestVersion(context.Background(), source, "example.com/lib", "v1.0.0", excluded)
+	require.NoError(t, err)
+	assert.Equal(t, latestModule{Version: "v1.1.0"}, latest)
+
+	latest, err = latestVersion(context.Background(), source, "example.com/untagged", "v0.0.0-20230101000000-abcdefabcdef", excluded)
+	require.NoError(t, err)
+	assert.Equal(t, latestModule{Version: "v0.0.0-20230101000000-abcdefabcdef"}, latest)
+}
+
+func TestGetAvailableVersionsSkipsExcludedAndRetracted(t *testing.T) {
+	goModPath := filepath.Join(t.TempDir(), "go.mod")
+	require.NoError(t, os.WriteFile(goModPath, []byte("module example.com/app\n\ngo 1.21\n\nexclude example.com/lib v1.2.0\n"), 0644))
+
+	source := &fakeSource{
+		versions: map[string][]string{"example.com/lib": {"v1.3.0", "v1.0.0", "v1.1.0", "v1.2.0"}},
+		goMods:   map[string]string{"example.com/lib@v1.3.0": "module example.com/lib\n\nretract v1.1.0 // Broken build\n"},
+	}
+	manager := NewManagerWithLookup(goModPath, NewLookup(source, 4, 0, nil))
+
+	versions, err := manager.GetAvailableVersions(context.Background(), []string{"example.com/lib"})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"example.com/lib": {"v1.0.0", "v1.3.0"}}, versions)
+}
@@ -0,0 +1,38 @@
+package dependency
+
+import "sort"
+
+// Impact describes how much of the main module uses a dependency
+type Impact struct {
+	Importers  []string // Main module packages importing a package of the dependency
+	Transitive []string // Main module packages depending on it only through other packages
+	CallSites  int      // References to its packages from the files importing them
+}
+
+// Packages returns the number of main module packages depending on the
+// dependency, directly or transitively
+func (i *Impact) Packages() int {
+	if i == nil {
+		return 0
+	}
+	return len(i.Importers) + len(i.Transitive)
+}
+
+// SortByImpact orders dependencies by blast radius: the ones imported by the
+// most packages first, then the most referenced ones. Dependencies without
+// an impact analysis keep their order after the analyzed ones.
+func SortByImpact(deps []Dependency) {
+	sort.SliceStable(deps, func(i, j int) bool {
+		a, b := deps[i].Impact, deps[j].Impact
+		switch {
+		case a == nil || b == nil:
+			return a != nil && b == nil
+		case len(a.Importers) != len(b.Importers):
+			return len(a.Importers) > len(b.Importers)
+		case a.Packages() != b.Packages():
+			return a.Packages() > b.Packages()
+		default:
+			return a.CallSites > b.CallSites
+		}
+	})
+}
@@ -0,0 +1,39 @@
+package dependency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortByImpact(t *testing.T) {
+	deps := []Dependency{
+		{Path: "example.com/unanalyzed"},
+		{Path: "example.com/unused", Impact: &Impact{}},
+		{Path: "example.com/transitive", Impact: &Impact{Transitive: []string{"app/a", "app/b", "app/c"}}},
+		{Path: "example.com/few-calls", Impact: &Impact{Importers: []string{"app/a", "app/b"}, CallSites: 3}},
+		{Path: "example.com/many-calls", Impact: &Impact{Importers: []string{"app/a", "app/b"}, CallSites: 40}},
+		{Path: "example.com/single", Impact: &Impact{Importers: []string{"app"}, Transitive: []string{"app/a", "app/b"}, CallSites: 90}},
+	}
+
+	SortByImpact(deps)
+
+	var order []string
+	for _, dep := range deps {
+		order = append(order, dep.Path)
+	}
+	assert.Equal(t, []string{
+		"example.com/many-calls",
+		"example.com/few-calls",
+		"example.com/single",
+		"example.com/transitive",
+		"example.com/unused",
+		"example.com/unanalyzed",
+	}, order)
+}
+
+func TestImpactPackages(t *testing.T) {
+	var unanalyzed *Impact
+	assert.Equal(t, 0, unanalyzed.Packages())
+	assert.Equal(t, 3, (&Impact{Importers: []string{"app"}, Transitive: []string{"app/a", "app/b"}}).Packages())
+}
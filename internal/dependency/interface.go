@@ -1,12 +1,59 @@
 package dependency
 
+import "context"
+
 // Dependency represents a Go module dependency with update information
 type Dependency struct {
 	Path       string // Module path (e.g., "github.com/gin-gonic/gin")
 	Version    string // Current version (e.g., "v1.9.1")
 	NewVersion string // Available new version (e.g., "v1.9.2")
+	NewPath    string // Module path after a major version upgrade (e.g., "github.com/foo/bar/v3")
 	Indirect   bool   // Whether this is an indirect dependency
 	HasUpdate  bool   // Whether an update is available
+
+	// Replace is the replace directive applying to the dependency, nil if it
+	// is not replaced. When it points to a fork, Version and NewVersion are
+	// versions of the fork and updating rewrites the replace directive.
+	Replace     *Replace
+	DropReplace bool // Updating drops the replace directive and requires NewVersion of Path instead
+
+	Modules    []string // Workspace modules requiring the dependency, relative to the go.work directory
+	Advisories []string // IDs of the known vulnerabilities affecting the current version
+	Retracted  []string // Rationale of the retraction of the current version, nil unless it is retracted
+	Deprecated string   // Deprecation notice of the module, empty unless it is deprecated
+	Changelog  string   // Unified diff of the changelog between Version and NewVersion, empty if not fetched
+	Breaking   []string // Exported identifiers the update removes or changes, when the API was compared
+	Impact     *Impact  // How the main module uses the dependency, nil when not analyzed
+	Chain      []string // Shortest requirement chain from the main module to an indirect dependency, as path@version
+	PulledBy   []Pull   // Updates of direct dependencies requiring a newer version of an indirect dependency
+}
+
+// Replace is a replace directive of go.mod
+type Replace struct {
+	OldVersion string // Version of the dependency the directive is restricted to, empty for every version
+	Path       string // Replacement module path, or local directory
+	Version    string // Replacement version, empty for a local directory
+}
+
+// IsLocal returns true if the dependency is replaced by a local directory
+func (r *Replace) IsLocal() bool {
+	return r.Version == ""
+}
+
+// String returns the replacement as written in go.mod
+func (r *Replace) String() string {
+	if r.IsLocal() {
+		return r.Path
+	}
+	return r.Path + " " + r.Version
+}
+
+// Pull is an update of a direct dependency that raises the version of an
+// indirect one
+type Pull struct {
+	Path     string // Direct dependency
+	Version  string // New version of the direct dependency
+	Requires string // Version of the indirect dependency it requires
 }
 
 // String returns a string representation of the dependency
@@ -16,6 +63,9 @@ func (d Dependency) String() string {
 		suffix = " (indirect)"
 	}
 
+	if d.IsMajorUpgrade() {
+		return d.Path + "@" + d.Version + " → " + d.NewPath + "@" + d.NewVersion + suffix
+	}
 	if d.HasUpdate && d.NewVersion != "" {
 		return d.Path + "@" + d.Version + " → " + d.NewVersion + suffix
 	}
@@ -29,12 +79,56 @@ func (d Dependency) VersionInfo() string {
 	return d.Version
 }
 
+// IsMajorUpgrade returns true if updating moves to a new major version module path
+func (d Dependency) IsMajorUpgrade() bool {
+	return d.NewPath != "" && d.NewPath != d.Path
+}
+
+// IsFork returns true if the dependency is replaced by another module, rather
+// than by a local directory or another version of itself
+func (d Dependency) IsFork() bool {
+	return d.Replace != nil && !d.Replace.IsLocal() && d.Replace.Path != d.Path
+}
+
+// UpdatesReplacement returns true if updating moves the fork replacing the
+// dependency to a newer version
+func (d Dependency) UpdatesReplacement() bool {
+	return d.IsFork() && !d.DropReplace
+}
+
+// IsRetracted returns true if the author retracted the current version, which
+// makes updating it urgent
+func (d Dependency) IsRetracted() bool {
+	return len(d.Retracted) > 0
+}
+
+// IsNoticeOnly returns true if the dependency has no update and is only listed
+// for the retraction of its current version or the deprecation of its module
+func (d Dependency) IsNoticeOnly() bool {
+	return !d.HasUpdate && d.NewVersion == "" && (d.IsRetracted() || d.Deprecated != "")
+}
+
+// TargetPath returns the module path the dependency is updated to
+func (d Dependency) TargetPath() string {
+	if d.IsMajorUpgrade() {
+		return d.NewPath
+	}
+	return d.Path
+}
+
 // Manager defines the interface for managing Go module dependencies
 type Manager interface {
 	// GetDependencies reads and parses dependencies from go.mod
 	GetDependencies() ([]Dependency, error)
 	// FilterDependencies filters dependencies based on criteria
 	FilterDependencies(deps []Dependency, includeIndirect bool) []Dependency
-	// GetUpdatableDependencies returns only dependencies that have updates available
-	GetUpdatableDependencies() ([]Dependency, error)
+	// GetUpdatableDependencies returns the dependencies that have updates available,
+	// and the up-to-date ones with a retraction or deprecation notice
+	GetUpdatableDependencies(ctx context.Context) ([]Dependency, error)
+	// GetAvailableVersions returns every published version of the given modules
+	GetAvailableVersions(ctx context.Context, paths []string) (map[string][]string, error)
+	// GetMajorUpgrades returns newer major version module paths (e.g. /v3) for the given dependencies
+	GetMajorUpgrades(ctx context.Context, deps []Dependency) ([]Dependency, error)
+	// GetReplacementUpdates returns newer versions of the forks replacing the given dependencies
+	GetReplacementUpdates(ctx context.Context, deps []Dependency) ([]Dependency, error)
 }
@@ -0,0 +1,353 @@
+package dependency
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// DefaultJobs is the number of concurrent version queries used by default
+const DefaultJobs = 8
+
+// VersionSource queries the published versions of modules, e.g. a module proxy
+type VersionSource interface {
+	// Versions returns the tagged versions of a module
+	Versions(ctx context.Context, path string) ([]string, error)
+	// Latest returns the latest version of a module, a pseudo-version if it has no tags
+	Latest(ctx context.Context, path string) (string, error)
+	// GoMod returns the go.mod file of a module version
+	GoMod(ctx context.Context, path, version string) ([]byte, error)
+}
+
+// ProgressFunc reports that done out of total modules have been queried
+type ProgressFunc func(done, total int, path string)
+
+// Lookup queries the versions of many modules concurrently. Modules the
+// source cannot serve are left to the go command.
+type Lookup struct {
+	source   VersionSource
+	jobs     int
+	timeout  time.Duration // Limit of each query and go command, zero for none
+	progress ProgressFunc
+}
+
+// NewLookup creates a lookup running up to jobs queries at a time. Each
+// module query and each go command run by the manager is given up after
+// timeout, unless it is zero; progress may be nil.
+func NewLookup(source VersionSource, jobs int, timeout time.Duration, progress ProgressFunc) *Lookup {
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	return &Lookup{
+		source:   source,
+		jobs:     jobs,
+		timeout:  timeout,
+		progress: progress,
+	}
+}
+
+// withTimeout bounds a single query by the lookup timeout. It is safe to call
+// on a nil lookup, which has no timeout.
+func (l *Lookup) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if l == nil || l.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, l.timeout)
+}
+
+// lookupUpdatableDependencies lists the build list without network access,
+// then queries the latest version of every module concurrently
+func (m *manager) lookupUpdatableDependencies(ctx context.Context) ([]Dependency, error) {
+	cmd, cancel := m.goCommand(ctx, "list", "-m", "-json", "all")
+	defer cancel()
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list modules: %v\noutput:\n%s", err, string(out))
+	}
+
+	modules := decodeModules(out)
+	current := make(map[string]string)
+	var paths []string
+	for _, module := range modules {
+		if !module.Main && module.Version != "" {
+			current[module.Path] = module.Version
+			paths = append(paths, module.Path)
+		}
+	}
+
+	excluded, err := m.readExclusions()
+	if err != nil {
+		return nil, err
+	}
+
+	latest, failed, err := query(ctx, m.lookup, paths, func(ctx context.Context, path string) (latestModule, error) {
+		return latestVersion(ctx, m.lookup.source, path, current[path], excluded)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The modules with an update, or a retraction or deprecation notice
+	updates := make(map[string]latestModule)
+	for path, module := range latest {
+		if semver.Compare(module.Version, current[path]) > 0 || hasNotice(module) {
+			updates[path] = module
+		}
+	}
+
+	// Modules the source cannot serve (GONOPROXY, direct) are resolved by the go command
+	if len(failed) > 0 {
+		cmd, cancel := m.goCommand(ctx, append([]string{"list", "-u", "-m", "-json"}, failed...)...)
+		defer cancel()
+
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			return nil, fmt.Errorf("failed to check for updates: %v\noutput:\n%s", err, string(out))
+		}
+		for _, module := range decodeModules(out) {
+			listed := latestModule{Retracted: module.Retracted, Deprecated: module.Deprecated}
+			if module.Update != nil {
+				listed.Version = module.Update.Version
+			}
+			if listed.Version != "" || hasNotice(listed) {
+				updates[module.Path] = listed
+			}
+		}
+	}
+
+	for i, module := range modules {
+		if update, ok := updates[module.Path]; ok {
+			if semver.Compare(update.Version, module.Version) > 0 {
+				modules[i].Update = &listedUpdate{Path: module.Path, Version: update.Version}
+			}
+			modules[i].Retracted = update.Retracted
+			modules[i].Deprecated = update.Deprecated
+		}
+	}
+
+	updatableDeps := updatableDependencies(modules)
+	m.sortDependencies(updatableDeps)
+
+	return updatableDeps, nil
+}
+
+// lookupAvailableVersions queries the published versions of modules
+// concurrently. Like 'go list -m -versions', it leaves out the versions
+// retracted by the go.mod of the newest one.
+func (m *manager) lookupAvailableVersions(ctx context.Context, paths []string) (map[string][]string, error) {
+	versions, failed, err := query(ctx, m.lookup, paths, func(ctx context.Context, path string) ([]string, error) {
+		return publishedVersions(ctx, m.lookup.source, path)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(failed) > 0 {
+		listed, err := m.listVersions(ctx, failed)
+		if err != nil {
+			return nil, err
+		}
+		for path, list := range listed {
+			versions[path] = list
+		}
+	}
+
+	for path, list := range versions {
+		semver.Sort(list)
+		versions[path] = list
+	}
+
+	return versions, nil
+}
+
+// query runs fn for every path on a pool of workers and reports progress as
+// the results arrive. Paths whose query failed or timed out are returned
+// separately; the whole query fails only when ctx is done.
+func query[T any](ctx context.Context, l *Lookup, paths []string, fn func(ctx context.Context, path string) (T, error)) (map[string]T, []string, error) {
+	values := make(map[string]T, len(paths))
+	if len(paths) == 0 {
+		return values, nil, nil
+	}
+
+	type result struct {
+		path  string
+		value T
+		err   error
+	}
+
+	jobs := make(chan string)
+	results := make(chan result)
+
+	var wg sync.WaitGroup
+	for range min(l.jobs, len(paths)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				queryCtx, cancel := l.withTimeout(ctx)
+				value, err := fn(queryCtx, path)
+				cancel()
+				results <- result{path: path, value: value, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, path := range paths {
+			select {
+			case jobs <- path:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var failed []string
+	done := 0
+	for result := range results {
+		done++
+		if result.err != nil {
+			failed = append(failed, result.path)
+		} else {
+			values[result.path] = result.value
+		}
+		if l.progress != nil {
+			l.progress(done, len(paths), result.path)
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, nil, fmt.Errorf("querying module versions: %w", err)
+	}
+
+	sort.Strings(failed)
+	return values, failed, nil
+}
+
+// latestModule is the outcome of a latest query
+type latestModule struct {
+	Version    string   // Latest version
+	Retracted  []string // Rationale of the retraction of the current version, if it is retracted
+	Deprecated string   // Deprecation notice of the module, if it is deprecated
+}
+
+// hasNotice reports whether the current version is retracted or the module deprecated
+func hasNotice(module latestModule) bool {
+	return len(module.Retracted) > 0 || module.Deprecated != ""
+}
+
+// latestVersion mirrors the go command's "latest" query: the highest release
+// that is not retracted, else the highest pre-release, else the latest
+// pseudo-version. +incompatible versions are only considered when the
+// current version is one. Like go list -u, it also reports whether the
+// current version is retracted and whether the module is deprecated.
+func latestVersion(ctx context.Context, source VersionSource, path, current string, excluded exclusions) (latestModule, error) {
+	versions, err := source.Versions(ctx, path)
+	if err != nil {
+		return latestModule{}, err
+	}
+
+	candidates := latestCandidates(excluded.filter(path, versions), current)
+	if len(candidates) == 0 {
+		version, err := source.Latest(ctx, path)
+		if excluded[module.Version{Path: path, Version: version}] {
+			return latestModule{Version: current}, err
+		}
+		return latestModule{Version: version}, err
+	}
+	// Retractions and deprecations are declared in the go.mod of the newest
+	// version. They matter even without an update: the module may be deprecated
+	// while the current version is its latest.
+	var f *modfile.File
+	if data, err := source.GoMod(ctx, path, candidates[0]); err == nil {
+		f, _ = modfile.ParseLax("go.mod", data, nil)
+	}
+	if f == nil {
+		return latestModule{Version: candidates[0]}, nil
+	}
+
+	latest := latestModule{Version: current, Retracted: retractions(f, current)}
+	if f.Module != nil {
+		latest.Deprecated = f.Module.Deprecated
+	}
+	if semver.Compare(candidates[0], current) <= 0 {
+		latest.Version = candidates[0]
+		return latest, nil
+	}
+	for _, version := range candidates {
+		if retractions(f, version) == nil {
+			latest.Version = version
+			break
+		}
+	}
+	return latest, nil
+}
+
+// publishedVersions returns the versions of a module that its author has not
+// retracted. Retractions are read from the go.mod of the newest version; if
+// it cannot be read, every version is returned.
+func publishedVersions(ctx context.Context, source VersionSource, path string) ([]string, error) {
+	versions, err := source.Versions(ctx, path)
+	if err != nil || len(versions) == 0 {
+		return versions, err
+	}
+
+	newest := slices.MaxFunc(versions, semver.Compare)
+	data, err := source.GoMod(ctx, path, newest)
+	if err != nil {
+		return versions, nil
+	}
+	f, err := modfile.ParseLax("go.mod", data, nil)
+	if err != nil || len(f.Retract) == 0 {
+		return versions, nil
+	}
+
+	var published []string
+	for _, version := range versions {
+		if retractions(f, version) == nil {
+			published = append(published, version)
+		}
+	}
+	return published, nil
+}
+
+// latestCandidates orders versions the way the latest query prefers them:
+// releases from newest to oldest, then pre-releases from newest to oldest
+func latestCandidates(versions []string, current string) []string {
+	incompatible := semver.Build(current) == "+incompatible"
+
+	var releases, prereleases []string
+	for _, version := range versions {
+		switch {
+		case !semver.IsValid(version):
+		case semver.Build(version) == "+incompatible" && !incompatible:
+		case semver.Prerelease(version) != "":
+			prereleases = append(prereleases, version)
+		default:
+			releases = append(releases, version)
+		}
+	}
+
+	descending := func(list []string) {
+		sort.Slice(list, func(i, j int) bool { return semver.Compare(list[i], list[j]) > 0 })
+	}
+	descending(releases)
+	descending(prereleases)
+
+	return append(releases, prereleases...)
+}
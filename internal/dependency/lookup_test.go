@@ -0,0 +1,217 @@
+package dependency
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSource serves module versions from memory
+type fakeSource struct {
+	versions map[string][]string
+	latest   map[string]string
+	goMods   map[string]string // by path@version
+	delay    time.Duration
+
+	mu       sync.Mutex
+	inFlight int
+	maxSeen  int
+}
+
+func (s *fakeSource) Versions(ctx context.Context, path string) ([]string, error) {
+	s.mu.Lock()
+	s.inFlight++
+	s.maxSeen = max(s.maxSeen, s.inFlight)
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.inFlight--
+		s.mu.Unlock()
+	}()
+
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	versions, ok := s.versions[path]
+	if !ok {
+		return nil, errors.New("not served")
+	}
+	return versions, nil
+}
+
+func (s *fakeSource) Latest(ctx context.Context, path string) (string, error) {
+	return s.latest[path], nil
+}
+
+func (s *fakeSource) GoMod(ctx context.Context, path, version string) ([]byte, error) {
+	goMod, ok := s.goMods[path+"@"+version]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return []byte(goMod), nil
+}
+
+func TestLatestCandidates(t *testing.T) {
+	versions := []string{"v1.2.0", "v1.10.0-rc.1", "v1.3.0", "bad", "v2.0.0+incompatible", "v1.9.0"}
+
+	assert.Equal(t, []string{"v1.9.0", "v1.3.0", "v1.2.0", "v1.10.0-rc.1"}, latestCandidates(versions, "v1.2.0"))
+	assert.Equal(t, "v2.0.0+incompatible", latestCandidates(versions, "v1.0.0+incompatible")[0])
+}
+
+func TestLatestVersion(t *testing.T) {
+	source := &fakeSource{
+		versions: map[string][]string{
+			"example.com/lib":       {"v1.0.0", "v1.1.0", "v1.2.0"},
+			"example.com/retracted": {"v1.0.0", "v1.1.0", "v1.2.0"},
+			"example.com/untagged":  {},
+			"example.com/pre":       {"v0.1.0-alpha", "v0.2.0-beta"},
+		},
+		latest: map[string]string{
+			"example.com/untagged": "v0.0.0-20240101000000-abcdefabcdef",
+		},
+		goMods: map[string]string{
+			"example.com/retracted@v1.2.0":  "module example.com/retracted\n\nretract [v1.1.5, v1.2.0]\n\n// Data race in the pool\nretract v1.0.0\n",
+			"example.com/deprecated@v1.1.0": "// Deprecated: use example.com/lib instead.\nmodule example.com/deprecated\n",
+		},
+	}
+	source.versions["example.com/deprecated"] = []string{"v1.0.0", "v1.1.0"}
+
+	tests := []struct {
+		path     string
+		current  string
+		expected latestModule
+	}{
+		{path: "example.com/lib", current: "v1.0.0", expected: latestModule{Version: "v1.2.0"}},
+		{path: "example.com/retracted", current: "v1.0.0", expected: latestModule{Version: "v1.1.0", Retracted: []string{"Data race in the pool"}}},
+		{path: "example.com/retracted", current: "v1.1.0", expected: latestModule{Version: "v1.1.0"}},
+		{path: "example.com/deprecated", current: "v1.0.0", expected: latestModule{Version: "v1.1.0", Deprecated: "use example.com/lib instead."}},
+		{path: "example.com/deprecated", current: "v1.1.0", expected: latestModule{Version: "v1.1.0", Deprecated: "use example.com/lib instead."}},
+		{path: "example.com/untagged", current: "v0.0.0-20230101000000-abcdefabcdef", expected: latestModule{Version: "v0.0.0-20240101000000-abcdefabcdef"}},
+		{path: "example.com/pre", current: "v0.1.0-alpha", expected: latestModule{Version: "v0.2.0-beta"}},
+		{path: "example.com/lib", current: "v1.3.0-dev", expected: latestModule{Version: "v1.2.0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path+"@"+tt.current, func(t *testing.T) {
+			latest, err := latestVersion(context.Background(), source, tt.path, tt.current, nil)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, latest)
+		})
+	}
+}
+
+func TestQueryRespectsConcurrencyLimit(t *testing.T) {
+	source := &fakeSource{versions: map[string][]string{}, delay: 5 * time.Millisecond}
+	var paths []string
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"} {
+		path := "example.com/" + name
+		paths = append(paths, path)
+		source.versions[path] = []string{"v1.0.0"}
+	}
+	paths = append(paths, "example.com/private")
+
+	var calls, lastDone int32
+	progress := func(done, total int, path string) {
+		atomic.AddInt32(&calls, 1)
+		atomic.StoreInt32(&lastDone, int32(done))
+		assert.Equal(t, 11, total)
+	}
+	lookup := NewLookup(source, 3, 0, progress)
+
+	versions, failed, err := query(context.Background(), lookup, paths, source.Versions)
+
+	require.NoError(t, err)
+	assert.Len(t, versions, 10)
+	assert.Equal(t, []string{"example.com/private"}, failed)
+	assert.LessOrEqual(t, source.maxSeen, 3)
+	assert.Greater(t, source.maxSeen, 1, "Queries run concurrently")
+	assert.Equal(t, int32(11), calls)
+	assert.Equal(t, int32(11), lastDone)
+}
+
+func TestQueryCancelled(t *testing.T) {
+	source := &fakeSource{versions: map[string][]string{"example.com/a": {"v1.0.0"}}, delay: time.Hour}
+	ctx, cancel := context.WithCancel(context.Background())
+	lookup := NewLookup(source, 2, 0, nil)
+
+	time.AfterFunc(10*time.Millisecond, cancel)
+	_, _, err := query(ctx, lookup, []string{"example.com/a", "example.com/b", "example.com/c"}, source.Versions)
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestQueryTimeout(t *testing.T) {
+	source := &fakeSource{versions: map[string][]string{"example.com/a": {"v1.0.0"}}, delay: time.Hour}
+	lookup := NewLookup(source, 2, 10*time.Millisecond, nil)
+
+	versions, failed, err := query(context.Background(), lookup, []string{"example.com/a", "example.com/b"}, source.Versions)
+
+	require.NoError(t, err, "A query timing out is left to the go command")
+	assert.Empty(t, versions)
+	assert.Equal(t, []string{"example.com/a", "example.com/b"}, failed)
+}
+
+func TestLookupUpdatableDependencies(t *testing.T) {
+	t.Setenv("GOFLAGS", "")
+	t.Setenv("GOWORK", "off")
+
+	// Local replacements keep the build list available offline
+	root := t.TempDir()
+	for _, name := range []string{"lib", "tool"} {
+		dir := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/"+name+"\n\ngo 1.21\n"), 0644))
+	}
+	goMod := `module example.com/app
+
+go 1.21
+
+require example.com/lib v1.0.0
+
+require example.com/tool v0.3.0 // indirect
+
+replace example.com/lib => ./lib
+
+replace example.com/tool => ./tool
+`
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte(goMod), 0644))
+
+	source := &fakeSource{
+		versions: map[string][]string{
+			"example.com/lib":  {"v1.0.0", "v1.1.0"},
+			"example.com/tool": {"v0.3.0"},
+		},
+		goMods: map[string]string{
+			"example.com/lib@v1.1.0":  "module example.com/lib\n\nretract v1.0.0 // Broken build\n",
+			"example.com/tool@v0.3.0": "// Deprecated: unmaintained\nmodule example.com/tool\n",
+		},
+	}
+	manager := NewManagerWithLookup(filepath.Join(root, "go.mod"), NewLookup(source, 4, 0, nil))
+
+	deps, err := manager.GetUpdatableDependencies(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []Dependency{
+		{Path: "example.com/lib", Version: "v1.0.0", NewVersion: "v1.1.0", HasUpdate: true, Retracted: []string{"Broken build"},
+			Replace: &Replace{Path: "./lib"}},
+		// Listed for its deprecation, though it has no update
+		{Path: "example.com/tool", Version: "v0.3.0", Indirect: true, Deprecated: "unmaintained", Replace: &Replace{Path: "./tool"}},
+	}, deps)
+
+	versions, err := manager.GetAvailableVersions(context.Background(), []string{"example.com/lib"})
+	require.NoError(t, err)
+	// v1.0.0 is retracted by the go.mod of v1.1.0
+	assert.Equal(t, map[string][]string{"example.com/lib": {"v1.1.0"}}, versions)
+}
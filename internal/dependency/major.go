@@ -0,0 +1,111 @@
+package dependency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// maxMajorProbes bounds how many successive major versions are probed per module
+const maxMajorProbes = 20
+
+// latestQuery returns the latest version of a module path, or an error if the path does not exist
+type latestQuery func(ctx context.Context, path string) (string, error)
+
+// GetMajorUpgrades returns newer major version module paths for the given dependencies.
+// Go treats /v2, /v3... as different modules, so 'go list -u' never reports them.
+func (m *manager) GetMajorUpgrades(ctx context.Context, deps []Dependency) ([]Dependency, error) {
+	var upgrades []Dependency
+	for _, dep := range deps {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("discovering major versions: %w", err)
+		}
+
+		newPath, newVersion := findLatestMajor(ctx, dep.Path, dep.Version, m.queryLatestVersion)
+		if newPath == "" {
+			continue
+		}
+
+		upgrades = append(upgrades, Dependency{
+			Path:       dep.Path,
+			Version:    dep.Version,
+			NewVersion: newVersion,
+			NewPath:    newPath,
+			Indirect:   dep.Indirect,
+			HasUpdate:  true,
+			Modules:    dep.Modules,
+		})
+	}
+
+	m.sortDependencies(upgrades)
+
+	return upgrades, nil
+}
+
+// findLatestMajor probes successive major version paths of a module and returns
+// the newest one that exists, or empty strings if there is none.
+func findLatestMajor(ctx context.Context, path, version string, query latestQuery) (string, string) {
+	prefix, next, ok := nextMajor(path, version)
+	if !ok {
+		return "", ""
+	}
+
+	var newPath, newVersion string
+	for i := 0; i < maxMajorProbes; i++ {
+		candidate := fmt.Sprintf("%s/v%d", prefix, next+i)
+		latest, err := query(ctx, candidate)
+		if err != nil || semver.Major(latest) != fmt.Sprintf("v%d", next+i) {
+			break
+		}
+		newPath, newVersion = candidate, latest
+	}
+
+	return newPath, newVersion
+}
+
+// nextMajor splits a module path into its prefix and the next major version number
+func nextMajor(path, version string) (string, int, bool) {
+	prefix, pathMajor, ok := module.SplitPathVersion(path)
+	if !ok || strings.HasPrefix(path, "gopkg.in/") {
+		// gopkg.in encodes the major version as .vN and is not rewritten
+		return "", 0, false
+	}
+
+	current := 1
+	if pathMajor != "" {
+		if _, err := fmt.Sscanf(pathMajor, "/v%d", &current); err != nil {
+			return "", 0, false
+		}
+	} else if semver.Build(version) == "+incompatible" {
+		// v2+ tags without a go.mod: the next module path is above the tagged major
+		if _, err := fmt.Sscanf(semver.Major(version), "v%d", &current); err != nil {
+			return "", 0, false
+		}
+	}
+
+	return prefix, current + 1, true
+}
+
+// queryLatestVersion asks the go command for the latest version of a module path
+func (m *manager) queryLatestVersion(ctx context.Context, path string) (string, error) {
+	cmd, cancel := m.goCommand(ctx, "list", "-m", "-json", path+"@latest")
+	defer cancel()
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("querying %s: %w", path, err)
+	}
+
+	var module struct {
+		Version string `json:"Version"`
+	}
+	if err := json.Unmarshal(out, &module); err != nil {
+		return "", fmt.Errorf("parsing version of %s: %w", path, err)
+	}
+
+	return module.Version, nil
+}
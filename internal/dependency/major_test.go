@@ -0,0 +1,95 @@
+package dependency
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fakeLatest(versions map[string]string) latestQuery {
+	return func(ctx context.Context, path string) (string, error) {
+		if version, ok := versions[path]; ok {
+			return version, nil
+		}
+		return "", errors.New("module not found")
+	}
+}
+
+func TestNextMajor(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		version  string
+		prefix   string
+		next     int
+		expected bool
+	}{
+		{name: "v1 module", path: "github.com/foo/bar", version: "v1.4.0", prefix: "github.com/foo/bar", next: 2, expected: true},
+		{name: "v0 module", path: "github.com/foo/bar", version: "v0.3.0", prefix: "github.com/foo/bar", next: 2, expected: true},
+		{name: "v2 module", path: "github.com/foo/bar/v2", version: "v2.1.0", prefix: "github.com/foo/bar", next: 3, expected: true},
+		{name: "incompatible", path: "github.com/foo/bar", version: "v4.1.0+incompatible", prefix: "github.com/foo/bar", next: 5, expected: true},
+		{name: "gopkg.in", path: "gopkg.in/yaml.v3", version: "v3.0.1", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefix, next, ok := nextMajor(tt.path, tt.version)
+			assert.Equal(t, tt.expected, ok)
+			if tt.expected {
+				assert.Equal(t, tt.prefix, prefix)
+				assert.Equal(t, tt.next, next)
+			}
+		})
+	}
+}
+
+func TestFindLatestMajor(t *testing.T) {
+	query := fakeLatest(map[string]string{
+		"github.com/foo/bar/v3": "v3.2.0",
+		"github.com/foo/bar/v4": "v4.0.1",
+		"github.com/baz/qux/v2": "v2.0.0",
+		"github.com/odd/mod/v2": "v1.0.0",
+	})
+
+	tests := []struct {
+		name        string
+		path        string
+		version     string
+		wantPath    string
+		wantVersion string
+	}{
+		{name: "skips to newest major", path: "github.com/foo/bar/v2", version: "v2.5.0", wantPath: "github.com/foo/bar/v4", wantVersion: "v4.0.1"},
+		{name: "v1 to v2", path: "github.com/baz/qux", version: "v1.9.0", wantPath: "github.com/baz/qux/v2", wantVersion: "v2.0.0"},
+		{name: "already newest", path: "github.com/foo/bar/v4", version: "v4.0.1"},
+		{name: "no newer major", path: "github.com/none/here", version: "v1.0.0"},
+		{name: "mismatched major is ignored", path: "github.com/odd/mod", version: "v1.0.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, version := findLatestMajor(context.Background(), tt.path, tt.version, query)
+			assert.Equal(t, tt.wantPath, path)
+			assert.Equal(t, tt.wantVersion, version)
+		})
+	}
+}
+
+func TestMajorUpgradeDependency(t *testing.T) {
+	dep := Dependency{
+		Path:       "github.com/foo/bar/v2",
+		Version:    "v2.5.0",
+		NewPath:    "github.com/foo/bar/v4",
+		NewVersion: "v4.0.1",
+		HasUpdate:  true,
+	}
+
+	assert.True(t, dep.IsMajorUpgrade())
+	assert.Equal(t, "github.com/foo/bar/v4", dep.TargetPath())
+	assert.Equal(t, "github.com/foo/bar/v2@v2.5.0 → github.com/foo/bar/v4@v4.0.1", dep.String())
+
+	regular := Dependency{Path: "github.com/foo/bar/v2", Version: "v2.5.0", NewVersion: "v2.6.0", HasUpdate: true}
+	assert.False(t, regular.IsMajorUpgrade())
+	assert.Equal(t, "github.com/foo/bar/v2", regular.TargetPath())
+}
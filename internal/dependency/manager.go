@@ -1,10 +1,12 @@
 package dependency
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"sort"
 	"strings"
 
@@ -14,6 +16,12 @@ import (
 // manager implements the Manager interface
 type manager struct {
 	goModPath string
+	dir       string  // Directory the go commands run in, empty for the current directory
+	lookup    *Lookup // Concurrent version queries, nil to let the go command resolve versions
+
+	// mainModules are the go.mod files whose exclude directives apply, only
+	// goModPath if nil
+	mainModules []string
 }
 
 // NewManager creates a new dependency manager
@@ -23,10 +31,22 @@ func NewManager() Manager {
 	}
 }
 
-// NewManagerWithPath creates a new dependency manager with a custom go.mod path
+// NewManagerWithPath creates a new dependency manager with a custom go.mod
+// path. The go commands run in the directory of that go.mod.
 func NewManagerWithPath(path string) Manager {
 	return &manager{
 		goModPath: path,
+		dir:       filepath.Dir(path),
+	}
+}
+
+// NewManagerWithLookup creates a dependency manager for a go.mod path that
+// queries module versions concurrently through lookup
+func NewManagerWithLookup(path string, lookup *Lookup) Manager {
+	return &manager{
+		goModPath: path,
+		dir:       filepath.Dir(path),
+		lookup:    lookup,
 	}
 }
 
@@ -48,6 +68,7 @@ func (m *manager) GetDependencies() ([]Dependency, error) {
 			Path:     req.Mod.Path,
 			Version:  req.Mod.Version,
 			Indirect: req.Indirect,
+			Replace:  findReplace(f.Replace, req.Mod.Path, req.Mod.Version),
 		})
 	}
 
@@ -72,33 +93,70 @@ func (m *manager) FilterDependencies(deps []Dependency, includeIndirect bool) []
 	return filtered
 }
 
-// GetUpdatableDependencies returns ONLY dependencies that have updates available
-func (m *manager) GetUpdatableDependencies() ([]Dependency, error) {
+// GetUpdatableDependencies returns the dependencies that have updates available,
+// and the up-to-date ones whose current version is retracted or whose module
+// is deprecated, without a NewVersion
+func (m *manager) GetUpdatableDependencies(ctx context.Context) ([]Dependency, error) {
+	if m.lookup != nil {
+		return m.lookupUpdatableDependencies(ctx)
+	}
+
 	// Use 'go list -u -m all' to get ALL dependencies with their update info
-	out, err := exec.Command("go", "list", "-u", "-m", "-json", "all").CombinedOutput()
+	cmd, cancel := m.goCommand(ctx, "list", "-u", "-m", "-json", "all")
+	defer cancel()
+
+	out, err := cmd.CombinedOutput()
 	if err != nil {
 		return nil, fmt.Errorf("failed to check for updates: %v\noutput:\n%s", err, string(out))
 	}
 
-	var updatableDeps []Dependency
+	updatableDeps := updatableDependencies(decodeModules(out))
+
+	// Sort dependencies: first direct (alphabetically), then indirect (alphabetically)
+	m.sortDependencies(updatableDeps)
+
+	return updatableDeps, nil
+}
+
+// listedModule is a module printed by 'go list -m -json'
+type listedModule struct {
+	Path       string        `json:"Path"`
+	Version    string        `json:"Version"`
+	Indirect   bool          `json:"Indirect"`
+	Main       bool          `json:"Main"`
+	Update     *listedUpdate `json:"Update"`
+	Retracted  []string      `json:"Retracted"`  // Set by -u when the current version is retracted
+	Deprecated string        `json:"Deprecated"` // Set by -u when the module is deprecated
+	Replace    *listedModule `json:"Replace"`    // Replacement applied by a replace directive
+}
+
+// listedUpdate is the newer version printed by 'go list -m -u -json'
+type listedUpdate struct {
+	Path    string `json:"Path"`
+	Version string `json:"Version"`
+}
+
+// decodeModules parses the output of 'go list -m -json', skipping malformed entries
+func decodeModules(out []byte) []listedModule {
+	var modules []listedModule
 	decoder := json.NewDecoder(strings.NewReader(string(out)))
 
 	for decoder.More() {
-		var module struct {
-			Path     string `json:"Path"`
-			Version  string `json:"Version"`
-			Indirect bool   `json:"Indirect"`
-			Main     bool   `json:"Main"`
-			Update   *struct {
-				Path    string `json:"Path"`
-				Version string `json:"Version"`
-			} `json:"Update"`
-		}
-
+		var module listedModule
 		if err := decoder.Decode(&module); err != nil {
 			continue
 		}
+		modules = append(modules, module)
+	}
+
+	return modules
+}
 
+// updatableDependencies returns the listed modules that have an update, or a
+// retraction or deprecation notice
+func updatableDependencies(modules []listedModule) []Dependency {
+	var updatableDeps []Dependency
+	for _, module := range modules {
 		// Skip the main module
 		if module.Main {
 			continue
@@ -109,23 +167,95 @@ func (m *manager) GetUpdatableDependencies() ([]Dependency, error) {
 			continue
 		}
 
-		// ONLY add dependencies that have updates available
+		// Up-to-date modules are only kept for their notices
+		if module.Update == nil && len(module.Retracted) == 0 && module.Deprecated == "" {
+			continue
+		}
+
+		dep := Dependency{
+			Path:       module.Path,
+			Version:    module.Version,
+			Indirect:   module.Indirect,
+			Retracted:  module.Retracted,
+			Deprecated: module.Deprecated,
+		}
 		if module.Update != nil {
-			dep := Dependency{
-				Path:       module.Path,
-				Version:    module.Version,
-				NewVersion: module.Update.Version,
-				Indirect:   module.Indirect,
-				HasUpdate:  true,
-			}
-			updatableDeps = append(updatableDeps, dep)
+			dep.NewVersion = module.Update.Version
+			dep.HasUpdate = true
 		}
+		if module.Replace != nil {
+			dep.Replace = &Replace{Path: module.Replace.Path, Version: module.Replace.Version}
+		}
+		updatableDeps = append(updatableDeps, dep)
 	}
+	return updatableDeps
+}
 
-	// Sort dependencies: first direct (alphabetically), then indirect (alphabetically)
-	m.sortDependencies(updatableDeps)
+// GetAvailableVersions returns every published version of the given modules
+// that go.mod does not exclude and their author has not retracted
+func (m *manager) GetAvailableVersions(ctx context.Context, paths []string) (map[string][]string, error) {
+	excluded, err := m.readExclusions()
+	if err != nil {
+		return nil, err
+	}
 
-	return updatableDeps, nil
+	var versions map[string][]string
+	if m.lookup != nil {
+		versions, err = m.lookupAvailableVersions(ctx, paths)
+	} else {
+		versions, err = m.listVersions(ctx, paths)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for path, list := range versions {
+		versions[path] = excluded.filter(path, list)
+	}
+	return versions, nil
+}
+
+// listVersions returns the published versions of modules with 'go list -versions'
+func (m *manager) listVersions(ctx context.Context, paths []string) (map[string][]string, error) {
+	versions := make(map[string][]string, len(paths))
+	if len(paths) == 0 {
+		return versions, nil
+	}
+
+	args := append([]string{"list", "-m", "-versions", "-json"}, paths...)
+	cmd, cancel := m.goCommand(ctx, args...)
+	defer cancel()
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list versions: %v\noutput:\n%s", err, string(out))
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(string(out)))
+	for decoder.More() {
+		var module struct {
+			Path     string   `json:"Path"`
+			Versions []string `json:"Versions"`
+		}
+
+		if err := decoder.Decode(&module); err != nil {
+			return nil, fmt.Errorf("failed to parse version list: %w", err)
+		}
+
+		versions[module.Path] = module.Versions
+	}
+
+	return versions, nil
+}
+
+// goCommand prepares a go command in the module directory. The command is
+// killed when ctx is done or the query timeout expires; call cancel once it
+// has finished.
+func (m *manager) goCommand(ctx context.Context, args ...string) (*exec.Cmd, context.CancelFunc) {
+	ctx, cancel := m.lookup.withTimeout(ctx)
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = m.dir
+	return cmd, cancel
 }
 
 func (m *manager) sortDependencies(deps []Dependency) {
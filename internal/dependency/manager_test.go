@@ -1,6 +1,7 @@
 package dependency
 
 import (
+	"context"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -132,7 +133,7 @@ go 1.21
 
 	manager := NewManagerWithPath(goModPath)
 
-	updatableDeps, err := manager.GetUpdatableDependencies()
+	updatableDeps, err := manager.GetUpdatableDependencies(context.Background())
 
 	assert.NoError(t, err)
 	assert.Empty(t, updatableDeps, "Should have no updatable dependencies in empty module")
@@ -168,7 +169,7 @@ require (
 
 	manager := NewManagerWithPath(goModPath)
 
-	_, err = manager.GetUpdatableDependencies()
+	_, err = manager.GetUpdatableDependencies(context.Background())
 
 	// Should return an error when go list fails
 	assert.Error(t, err)
@@ -205,7 +206,7 @@ require (
 
 	manager := NewManagerWithPath(goModPath)
 
-	deps, err := manager.GetUpdatableDependencies()
+	deps, err := manager.GetUpdatableDependencies(context.Background())
 
 	assert.Error(t, err)
 	assert.Nil(t, deps)
@@ -240,7 +241,7 @@ require (
 
 	manager := NewManagerWithPath(goModPath)
 
-	deps, err := manager.GetUpdatableDependencies()
+	deps, err := manager.GetUpdatableDependencies(context.Background())
 	require.NoError(t, err)
 
 	for _, dep := range deps {
@@ -396,3 +397,56 @@ func TestFilterDependenciesMixedTypes(t *testing.T) {
 		assert.Equal(t, deps, filtered)
 	})
 }
+
+func TestGetAvailableVersionsNoPaths(t *testing.T) {
+	manager := NewManagerWithPath("nonexistent.mod")
+
+	versions, err := manager.GetAvailableVersions(context.Background(), nil)
+
+	require.NoError(t, err)
+	assert.Empty(t, versions)
+}
+
+func TestGetAvailableVersionsCommandFails(t *testing.T) {
+	tempDir := t.TempDir()
+	goModPath := filepath.Join(tempDir, "go.mod")
+
+	err := os.WriteFile(goModPath, []byte("module testmodule\n\ngo 1.21\n"), 0644)
+	require.NoError(t, err)
+
+	originalDir, err := os.Getwd()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.Chdir(originalDir) })
+	require.NoError(t, os.Chdir(tempDir))
+
+	manager := NewManagerWithPath(goModPath)
+
+	_, err = manager.GetAvailableVersions(context.Background(), []string{"invalid-module-path"})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to list versions")
+}
+
+func TestUpdatableDependenciesRetractedAndDeprecated(t *testing.T) {
+	out := `{"Path": "example.com/app", "Main": true}
+{
+	"Path": "example.com/lib",
+	"Version": "v1.0.0",
+	"Update": {"Path": "example.com/lib", "Version": "v1.0.1"},
+	"Retracted": ["Data race in the pool"],
+	"Deprecated": "use example.com/lib/v2 instead"
+}
+{"Path": "example.com/current", "Version": "v1.0.0", "Deprecated": "unmaintained"}
+`
+
+	deps := updatableDependencies(decodeModules([]byte(out)))
+
+	// A deprecated module is reported even on its latest version
+	assert.Equal(t, []Dependency{{
+		Path: "example.com/lib", Version: "v1.0.0", NewVersion: "v1.0.1", HasUpdate: true,
+		Retracted: []string{"Data race in the pool"}, Deprecated: "use example.com/lib/v2 instead",
+	}, {
+		Path: "example.com/current", Version: "v1.0.0", Deprecated: "unmaintained",
+	}}, deps)
+	assert.True(t, deps[1].IsNoticeOnly())
+}
@@ -0,0 +1,51 @@
+package dependency
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FindModules walks root and returns the directory of every go.mod below it,
+// relative to root and sorted. vendor and testdata directories are skipped, as
+// are the directories the go command ignores (names starting with . or _).
+func FindModules(root string) ([]string, error) {
+	var modules []string
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && skipModuleDir(d.Name()) {
+			return filepath.SkipDir
+		}
+
+		info, err := os.Stat(filepath.Join(path, "go.mod"))
+		if err != nil || info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		modules = append(modules, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(modules)
+	return modules, nil
+}
+
+// skipModuleDir reports whether a directory cannot contain modules of the project
+func skipModuleDir(name string) bool {
+	return name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
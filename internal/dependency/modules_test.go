@@ -0,0 +1,51 @@
+package dependency
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindModules(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{
+		".",
+		"services/api",
+		"services/worker",
+		"tools",
+		"vendor/github.com/pkg/errors",
+		"internal/parser/testdata/sample",
+		".cache/mod",
+		"_examples/basic",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, dir, "go.mod"), []byte("module example.com/m\n"), 0644))
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "docs"), 0755))
+
+	modules, err := FindModules(root)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{".", "services/api", "services/worker", "tools"}, modules)
+}
+
+func TestFindModulesWithoutRootModule(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "b"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "a"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "b", "go.mod"), []byte("module b\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a", "go.mod"), []byte("module a\n"), 0644))
+
+	modules, err := FindModules(root)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"a", "b"}, modules)
+}
+
+func TestFindModulesMissingRoot(t *testing.T) {
+	_, err := FindModules(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
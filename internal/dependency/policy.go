@@ -0,0 +1,97 @@
+package dependency
+
+import (
+	"fmt"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// Policy controls which newer versions are acceptable update targets
+type Policy string
+
+const (
+	PolicyLatest Policy = ""      // Latest version reported by 'go list -u' (default)
+	PolicyPatch  Policy = "patch" // Only newer patch releases of the current major.minor
+	PolicyMinor  Policy = "minor" // Only newer minor or patch releases of the current major
+	PolicyMajor  Policy = "major" // Any newer release of the module path
+)
+
+// ParsePolicy converts a policy name into a Policy
+func ParsePolicy(name string) (Policy, error) {
+	if name == "latest" {
+		return PolicyLatest, nil
+	}
+
+	switch policy := Policy(name); policy {
+	case PolicyLatest, PolicyPatch, PolicyMinor, PolicyMajor:
+		return policy, nil
+	default:
+		return PolicyLatest, fmt.Errorf("unknown update policy %q (expected latest, %q, %q or %q)",
+			name, PolicyPatch, PolicyMinor, PolicyMajor)
+	}
+}
+
+// String returns the policy name, "latest" for the default policy
+func (p Policy) String() string {
+	if p == PolicyLatest {
+		return "latest"
+	}
+	return string(p)
+}
+
+// Allows reports whether moving from current to candidate is permitted by the policy
+func (p Policy) Allows(current, candidate string) bool {
+	switch p {
+	case PolicyPatch:
+		return semver.MajorMinor(candidate) == semver.MajorMinor(current)
+	case PolicyMinor:
+		return semver.Major(candidate) == semver.Major(current)
+	default:
+		return true
+	}
+}
+
+// SelectVersion returns the highest version newer than current that the policy
+// allows, or an empty string if there is none. Pre-releases are only considered
+// when the current version is itself a pre-release, and +incompatible versions
+// only when the current version is +incompatible.
+func SelectVersion(current string, versions []string, policy Policy) string {
+	best := ""
+	for _, candidate := range AllowedVersions(current, versions, policy) {
+		if best == "" || semver.Compare(candidate, best) > 0 {
+			best = candidate
+		}
+	}
+	return best
+}
+
+// AllowedVersions returns the versions SelectVersion may choose from, in
+// their original order
+func AllowedVersions(current string, versions []string, policy Policy) []string {
+	if !semver.IsValid(current) {
+		return nil
+	}
+
+	currentIsPrerelease := semver.Prerelease(current) != "" && !module.IsPseudoVersion(current)
+	currentIsIncompatible := semver.Build(current) == "+incompatible"
+
+	var allowed []string
+	for _, candidate := range versions {
+		if !semver.IsValid(candidate) || semver.Compare(candidate, current) <= 0 {
+			continue
+		}
+		if semver.Prerelease(candidate) != "" && !currentIsPrerelease {
+			continue
+		}
+		if (semver.Build(candidate) == "+incompatible") != currentIsIncompatible {
+			continue
+		}
+		if !policy.Allows(current, candidate) {
+			continue
+		}
+		allowed = append(allowed, candidate)
+	}
+
+	return allowed
+}
@@ -0,0 +1,66 @@
+package dependency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePolicy(t *testing.T) {
+	for _, name := range []string{"", "patch", "minor", "major"} {
+		policy, err := ParsePolicy(name)
+		require.NoError(t, err)
+		assert.Equal(t, Policy(name), policy)
+	}
+
+	policy, err := ParsePolicy("latest")
+	require.NoError(t, err)
+	assert.Equal(t, PolicyLatest, policy)
+
+	_, err = ParsePolicy("newest")
+	assert.ErrorContains(t, err, `unknown update policy "newest"`)
+}
+
+func TestPolicyString(t *testing.T) {
+	assert.Equal(t, "latest", PolicyLatest.String())
+	assert.Equal(t, "patch", PolicyPatch.String())
+}
+
+func TestSelectVersion(t *testing.T) {
+	versions := []string{
+		"v1.8.0", "v1.8.1", "v1.8.4",
+		"v1.9.0", "v1.9.1", "v1.10.0-rc.1",
+		"v1.10.0", "v1.10.2",
+		"v2.0.0+incompatible",
+	}
+
+	tests := []struct {
+		name     string
+		current  string
+		versions []string
+		policy   Policy
+		expected string
+	}{
+		{name: "patch stays on minor", current: "v1.8.1", versions: versions, policy: PolicyPatch, expected: "v1.8.4"},
+		{name: "minor stays on major", current: "v1.8.1", versions: versions, policy: PolicyMinor, expected: "v1.10.2"},
+		{name: "major takes newest", current: "v1.8.1", versions: versions, policy: PolicyMajor, expected: "v1.10.2"},
+		{name: "latest takes newest", current: "v1.8.1", versions: versions, policy: PolicyLatest, expected: "v1.10.2"},
+		{name: "no newer patch", current: "v1.9.1", versions: versions, policy: PolicyPatch, expected: ""},
+		{name: "already newest", current: "v1.10.2", versions: versions, policy: PolicyMajor, expected: ""},
+		{name: "unordered versions", current: "v1.0.0", versions: []string{"v1.0.2", "v1.0.5", "v1.0.1"}, policy: PolicyPatch, expected: "v1.0.5"},
+		{name: "major crosses v0 to v1", current: "v0.9.0", versions: []string{"v0.9.1", "v1.0.0"}, policy: PolicyMajor, expected: "v1.0.0"},
+		{name: "minor keeps v0", current: "v0.9.0", versions: []string{"v0.9.1", "v0.10.0", "v1.0.0"}, policy: PolicyMinor, expected: "v0.10.0"},
+		{name: "prerelease current accepts prereleases", current: "v1.10.0-beta.1", versions: []string{"v1.10.0-rc.1"}, policy: PolicyPatch, expected: "v1.10.0-rc.1"},
+		{name: "pseudo version ignores prereleases", current: "v0.0.0-20230101000000-abcdefabcdef", versions: []string{"v0.0.1-rc.1", "v0.0.1"}, policy: PolicyPatch, expected: "v0.0.1"},
+		{name: "incompatible current", current: "v2.0.0+incompatible", versions: []string{"v2.1.0+incompatible", "v3.0.0+incompatible"}, policy: PolicyMinor, expected: "v2.1.0+incompatible"},
+		{name: "invalid current", current: "latest", versions: versions, policy: PolicyMajor, expected: ""},
+		{name: "invalid candidates ignored", current: "v1.0.0", versions: []string{"garbage", "v1.0.1"}, policy: PolicyPatch, expected: "v1.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SelectVersion(tt.current, tt.versions, tt.policy))
+		})
+	}
+}
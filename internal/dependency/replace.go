@@ -0,0 +1,79 @@
+package dependency
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+// findReplace returns the replace directive applying to a module version, or
+// nil if there is none. Like the go command, a directive restricted to the
+// version wins over one applying to every version.
+func findReplace(replaces []*modfile.Replace, path, version string) *Replace {
+	var found *modfile.Replace
+	for _, r := range replaces {
+		if r.Old.Path != path {
+			continue
+		}
+		if r.Old.Version == version {
+			found = r
+			break
+		}
+		if r.Old.Version == "" {
+			found = r
+		}
+	}
+
+	if found == nil {
+		return nil
+	}
+	return &Replace{OldVersion: found.Old.Version, Path: found.New.Path, Version: found.New.Version}
+}
+
+// GetReplacementUpdates returns the forks replacing the given dependencies
+// that have a newer version. Local directories and replacements by another
+// version of the same module, which pin it on purpose, are left alone.
+func (m *manager) GetReplacementUpdates(ctx context.Context, deps []Dependency) ([]Dependency, error) {
+	var updates []Dependency
+	for _, dep := range deps {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("checking replacements: %w", err)
+		}
+		if !dep.IsFork() {
+			continue
+		}
+
+		latest, err := m.latestReplacement(ctx, dep.Replace)
+		if err != nil || semver.Compare(latest, dep.Replace.Version) <= 0 {
+			continue
+		}
+
+		updates = append(updates, Dependency{
+			Path:       dep.Path,
+			Version:    dep.Replace.Version,
+			NewVersion: latest,
+			Indirect:   dep.Indirect,
+			HasUpdate:  true,
+			Replace:    dep.Replace,
+			Modules:    dep.Modules,
+		})
+	}
+
+	m.sortDependencies(updates)
+
+	return updates, nil
+}
+
+// latestReplacement returns the latest version of the module replacing a dependency
+func (m *manager) latestReplacement(ctx context.Context, r *Replace) (string, error) {
+	if m.lookup == nil {
+		return m.queryLatestVersion(ctx, r.Path)
+	}
+
+	ctx, cancel := m.lookup.withTimeout(ctx)
+	defer cancel()
+	latest, err := latestVersion(ctx, m.lookup.source, r.Path, r.Version, nil)
+	return latest.Version, err
+}
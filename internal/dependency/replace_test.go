@@ -0,0 +1,130 @@
+package dependency
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/mod/modfile"
+)
+
+func TestFindReplace(t *testing.T) {
+	f, err := modfile.Parse("go.mod", []byte(`module example.com/app
+
+replace example.com/lib => ./lib
+
+replace example.com/fork v1.0.0 => github.com/me/fork v1.0.1
+
+replace example.com/fork => github.com/me/fork v1.2.0
+`), nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		path    string
+		version string
+		want    *Replace
+	}{
+		{
+			name:    "local directory",
+			path:    "example.com/lib",
+			version: "v1.0.0",
+			want:    &Replace{Path: "./lib"},
+		},
+		{
+			name:    "version-specific replacement wins",
+			path:    "example.com/fork",
+			version: "v1.0.0",
+			want:    &Replace{OldVersion: "v1.0.0", Path: "github.com/me/fork", Version: "v1.0.1"},
+		},
+		{
+			name:    "replacement of every version",
+			path:    "example.com/fork",
+			version: "v1.1.0",
+			want:    &Replace{Path: "github.com/me/fork", Version: "v1.2.0"},
+		},
+		{
+			name:    "not replaced",
+			path:    "example.com/other",
+			version: "v1.0.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, findReplace(f.Replace, tt.path, tt.version))
+		})
+	}
+}
+
+func TestGetDependenciesReplace(t *testing.T) {
+	goModPath := filepath.Join(t.TempDir(), "go.mod")
+	require.NoError(t, os.WriteFile(goModPath, []byte(`module example.com/app
+
+go 1.21
+
+require (
+	example.com/fork v1.0.0
+	example.com/lib v1.0.0
+	example.com/other v1.0.0
+)
+
+replace example.com/lib => ./lib
+
+replace example.com/fork => github.com/me/fork v1.0.1
+`), 0644))
+
+	deps, err := NewManagerWithPath(goModPath).GetDependencies()
+
+	require.NoError(t, err)
+	require.Len(t, deps, 3)
+	assert.Equal(t, &Replace{Path: "github.com/me/fork", Version: "v1.0.1"}, deps[0].Replace)
+	assert.True(t, deps[0].IsFork())
+	assert.Equal(t, &Replace{Path: "./lib"}, deps[1].Replace)
+	assert.False(t, deps[1].IsFork())
+	assert.Nil(t, deps[2].Replace)
+}
+
+func TestReplaceString(t *testing.T) {
+	assert.Equal(t, "./lib", (&Replace{Path: "./lib"}).String())
+	assert.Equal(t, "github.com/me/fork v1.0.1", (&Replace{Path: "github.com/me/fork", Version: "v1.0.1"}).String())
+}
+
+func TestGetReplacementUpdates(t *testing.T) {
+	source := &fakeSource{versions: map[string][]string{
+		"github.com/me/fork": {"v1.0.0", "v1.0.1", "v1.1.0"},
+		"github.com/me/same": {"v0.2.0"},
+	}}
+	manager := NewManagerWithLookup("go.mod", NewLookup(source, 4, 0, nil))
+
+	fork := &Replace{Path: "github.com/me/fork", Version: "v1.0.1"}
+	deps := []Dependency{
+		{Path: "example.com/fork", Version: "v1.0.0", Indirect: true, Replace: fork},
+		{Path: "example.com/lib", Version: "v1.0.0", Replace: &Replace{Path: "./lib"}},
+		{Path: "example.com/pinned", Version: "v1.0.0", Replace: &Replace{Path: "example.com/pinned", Version: "v0.9.0"}},
+		{Path: "example.com/same", Version: "v0.1.0", Replace: &Replace{Path: "github.com/me/same", Version: "v0.2.0"}},
+		{Path: "example.com/plain", Version: "v1.0.0"},
+	}
+
+	updates, err := manager.GetReplacementUpdates(context.Background(), deps)
+
+	require.NoError(t, err)
+	assert.Equal(t, []Dependency{
+		{Path: "example.com/fork", Version: "v1.0.1", NewVersion: "v1.1.0", Indirect: true, HasUpdate: true, Replace: fork},
+	}, updates)
+	assert.True(t, updates[0].UpdatesReplacement())
+}
+
+func TestWorkspaceGetReplacementUpdates(t *testing.T) {
+	ws, err := LoadWorkspace(filepath.Join(writeWorkspace(t), "go.work"))
+	require.NoError(t, err)
+
+	deps := []Dependency{{Path: "example.com/fork", Version: "v1.0.0", Replace: &Replace{Path: "github.com/me/fork", Version: "v1.0.1"}}}
+	updates, err := NewWorkspaceManager(ws).GetReplacementUpdates(context.Background(), deps)
+
+	require.NoError(t, err)
+	assert.Empty(t, updates)
+}
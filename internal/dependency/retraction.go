@@ -0,0 +1,72 @@
+package dependency
+
+import (
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// defaultRationale is reported, like the go command does, for retractions
+// without a comment
+const defaultRationale = "retracted by module author"
+
+// replacementKeywords introduce the module a deprecation notice recommends
+var replacementKeywords = []string{"use", "moved to", "replaced by", "superseded by", "switch to", "migrate to", "in favor of", "in favour of"}
+
+// retractions returns the rationale of every retract directive of a go.mod
+// covering version, or nil if the version is not retracted
+func retractions(f *modfile.File, version string) []string {
+	var rationale []string
+	for _, r := range f.Retract {
+		if semver.Compare(r.Low, version) <= 0 && semver.Compare(version, r.High) <= 0 {
+			text := r.Rationale
+			if text == "" {
+				text = defaultRationale
+			}
+			rationale = append(rationale, text)
+		}
+	}
+	return rationale
+}
+
+// Replacement returns the module the deprecation notice of the dependency
+// recommends instead, e.g. "github.com/new/lib" for "Deprecated: use
+// github.com/new/lib instead.", or an empty string if it names none
+func (d Dependency) Replacement() string {
+	words := strings.Fields(strings.ToLower(d.Deprecated))
+	original := strings.Fields(d.Deprecated)
+
+	for i := range words {
+		for _, keyword := range replacementKeywords {
+			n := len(strings.Fields(keyword))
+			if i+n > len(words) || strings.Join(words[i:i+n], " ") != keyword {
+				continue
+			}
+
+			// The module may follow a few words later: "use the github.com/new/lib module"
+			for _, word := range original[i+n : min(i+n+3, len(original))] {
+				if path := modulePathIn(word); path != "" && path != d.Path {
+					return path
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// modulePathIn returns the module path written in a word of a deprecation
+// notice, without quotes, punctuation, URL scheme or version, or an empty
+// string if the word is not a module path
+func modulePathIn(word string) string {
+	word = strings.Trim(word, "`'\"()[]<>,;:!?.")
+	word = strings.TrimPrefix(strings.TrimPrefix(word, "https://"), "http://")
+	word, _, _ = strings.Cut(word, "@")
+	word = strings.TrimSuffix(word, "/")
+
+	if !strings.Contains(word, "/") || module.CheckPath(word) != nil {
+		return ""
+	}
+	return word
+}
@@ -0,0 +1,52 @@
+package dependency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/mod/modfile"
+)
+
+func TestRetractions(t *testing.T) {
+	f, err := modfile.ParseLax("go.mod", []byte(`module example.com/lib
+
+retract (
+	// Data race in the pool
+	[v1.1.0, v1.1.3]
+	v1.2.0
+	v1.1.2 // Published by mistake
+)
+`), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Data race in the pool", "Published by mistake"}, retractions(f, "v1.1.2"))
+	assert.Equal(t, []string{"retracted by module author"}, retractions(f, "v1.2.0"))
+	assert.Nil(t, retractions(f, "v1.1.4"))
+}
+
+func TestReplacement(t *testing.T) {
+	tests := []struct {
+		deprecated string
+		expected   string
+	}{
+		{deprecated: "use github.com/new/lib instead.", expected: "github.com/new/lib"},
+		{deprecated: "Use `github.com/new/lib/v2`.", expected: "github.com/new/lib/v2"},
+		{deprecated: "This module has moved to https://gitlab.com/new/lib.", expected: "gitlab.com/new/lib"},
+		{deprecated: "Replaced by the github.com/new/lib@v1.2.0 module", expected: "github.com/new/lib"},
+		{deprecated: "Deprecated in favor of golang.org/x/exp/slog", expected: "golang.org/x/exp/slog"},
+		{deprecated: "Use github.com/old/lib/v2 of this module instead", expected: "github.com/old/lib/v2"},
+		{deprecated: "use the v2 module", expected: ""},
+		{deprecated: "Unmaintained, see https://github.com/old/lib/issues/12", expected: ""},
+		{deprecated: "Do not use github.com/old/lib anymore", expected: ""},
+		{deprecated: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.deprecated, func(t *testing.T) {
+			dep := Dependency{Path: "github.com/old/lib", Deprecated: tt.deprecated}
+
+			assert.Equal(t, tt.expected, dep.Replacement())
+		})
+	}
+}
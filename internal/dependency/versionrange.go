@@ -0,0 +1,111 @@
+package dependency
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// rangeOperators lists the supported comparison operators, longest first
+var rangeOperators = []string{">=", "<=", ">", "<", "="}
+
+// VersionRange is a set of version constraints that must all hold, written
+// like ">=v1.2.0 <v1.5.0" or ">=v1.2.0, <v1.5.0"
+type VersionRange struct {
+	raw         string
+	constraints []versionConstraint
+}
+
+type versionConstraint struct {
+	op      string
+	version string
+}
+
+// ParseRange parses a version range. A version without an operator must match exactly.
+func ParseRange(s string) (VersionRange, error) {
+	r := VersionRange{raw: strings.TrimSpace(s)}
+
+	fields := strings.Fields(strings.ReplaceAll(s, ",", " "))
+	if len(fields) == 0 {
+		return r, fmt.Errorf("empty version range")
+	}
+
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+
+		op := ""
+		for _, candidate := range rangeOperators {
+			if strings.HasPrefix(field, candidate) {
+				op = candidate
+				break
+			}
+		}
+
+		version := strings.TrimPrefix(field, op)
+		// Allow a space between the operator and the version (">= v1.2.0")
+		if version == "" && i+1 < len(fields) {
+			i++
+			version = fields[i]
+		}
+
+		if version != "" && !strings.HasPrefix(version, "v") {
+			version = "v" + version
+		}
+		if !semver.IsValid(version) {
+			return r, fmt.Errorf("invalid version %q in range %q", strings.TrimPrefix(field, op), r.raw)
+		}
+
+		if op == "" {
+			op = "="
+		}
+		r.constraints = append(r.constraints, versionConstraint{op: op, version: version})
+	}
+
+	return r, nil
+}
+
+// Contains reports whether a version satisfies every constraint of the range
+func (r VersionRange) Contains(version string) bool {
+	if !semver.IsValid(version) {
+		return false
+	}
+
+	for _, c := range r.constraints {
+		cmp := semver.Compare(version, c.version)
+		var ok bool
+		switch c.op {
+		case ">=":
+			ok = cmp >= 0
+		case "<=":
+			ok = cmp <= 0
+		case ">":
+			ok = cmp > 0
+		case "<":
+			ok = cmp < 0
+		default:
+			ok = cmp == 0
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Filter returns the versions contained in the range
+func (r VersionRange) Filter(versions []string) []string {
+	var result []string
+	for _, version := range versions {
+		if r.Contains(version) {
+			result = append(result, version)
+		}
+	}
+	return result
+}
+
+// String returns the range as it was written
+func (r VersionRange) String() string {
+	return r.raw
+}
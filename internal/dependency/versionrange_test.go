@@ -0,0 +1,52 @@
+package dependency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		input   string
+		in      []string
+		out     []string
+		wantErr string
+	}{
+		{input: ">=v1.2.0 <v1.5.0", in: []string{"v1.2.0", "v1.4.9"}, out: []string{"v1.1.9", "v1.5.0", "v2.0.0"}},
+		{input: ">=v1.2.0, <v1.5.0", in: []string{"v1.3.0"}, out: []string{"v1.5.0"}},
+		{input: ">= 1.2.0 < 1.5.0", in: []string{"v1.3.0"}, out: []string{"v1.1.0"}},
+		{input: "v1.4.2", in: []string{"v1.4.2"}, out: []string{"v1.4.3"}},
+		{input: "<=v0.9.0", in: []string{"v0.9.0", "v0.1.0"}, out: []string{"v0.9.1"}},
+		{input: ">v1.0.0", in: []string{"v1.0.1"}, out: []string{"v1.0.0", "not-a-version"}},
+		{input: "", wantErr: "empty version range"},
+		{input: ">=latest", wantErr: `invalid version "latest"`},
+		{input: ">=", wantErr: `invalid version ""`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			r, err := ParseRange(tt.input)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			for _, version := range tt.in {
+				assert.True(t, r.Contains(version), "%s should be in %s", version, tt.input)
+			}
+			for _, version := range tt.out {
+				assert.False(t, r.Contains(version), "%s should not be in %s", version, tt.input)
+			}
+		})
+	}
+}
+
+func TestVersionRangeFilter(t *testing.T) {
+	r, err := ParseRange(">=v1.2.0 <v1.5.0")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"v1.2.0", "v1.4.0"}, r.Filter([]string{"v1.1.0", "v1.2.0", "v1.4.0", "v1.5.0"}))
+	assert.Equal(t, ">=v1.2.0 <v1.5.0", r.String())
+}
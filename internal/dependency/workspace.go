@@ -0,0 +1,182 @@
+package dependency
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+// WorkspaceFile is the name of the file declaring a Go workspace
+const WorkspaceFile = "go.work"
+
+// Workspace describes a go.work file and the modules it uses
+type Workspace struct {
+	Root    string   // Directory containing go.work
+	Modules []string // Directories of the used modules, relative to Root
+}
+
+// FindWorkspace returns the path of the go.work file that applies to dir, or
+// an empty string if dir is not in workspace mode. Like the go command, it
+// honours GOWORK=off and an explicit GOWORK path.
+func FindWorkspace(dir string) string {
+	switch gowork := os.Getenv("GOWORK"); gowork {
+	case "off":
+		return ""
+	case "":
+	default:
+		return gowork
+	}
+
+	path := filepath.Join(dir, WorkspaceFile)
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		return path
+	}
+	return ""
+}
+
+// LoadWorkspace parses a go.work file
+func LoadWorkspace(path string) (*Workspace, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	f, err := modfile.ParseWork(path, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	root, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path '%s': %w", path, err)
+	}
+
+	ws := &Workspace{Root: root}
+	for _, use := range f.Use {
+		dir := filepath.FromSlash(use.Path)
+		if filepath.IsAbs(dir) {
+			if dir, err = filepath.Rel(root, dir); err != nil {
+				return nil, fmt.Errorf("resolving module %s: %w", use.Path, err)
+			}
+		}
+		ws.Modules = append(ws.Modules, filepath.ToSlash(filepath.Clean(dir)))
+	}
+
+	if len(ws.Modules) == 0 {
+		return nil, fmt.Errorf("%s does not use any module", path)
+	}
+
+	return ws, nil
+}
+
+// workspaceManager implements the Manager interface for every module of a
+// workspace. The go commands run in the workspace, so the selected versions
+// are shared by all modules.
+type workspaceManager struct {
+	*manager
+	workspace *Workspace
+}
+
+// NewWorkspaceManager creates a dependency manager for the modules of a workspace
+func NewWorkspaceManager(ws *Workspace) Manager {
+	return NewWorkspaceManagerWithLookup(ws, nil)
+}
+
+// NewWorkspaceManagerWithLookup creates a workspace manager that queries
+// module versions concurrently through lookup
+func NewWorkspaceManagerWithLookup(ws *Workspace, lookup *Lookup) Manager {
+	mainModules := make([]string, 0, len(ws.Modules))
+	for _, module := range ws.Modules {
+		mainModules = append(mainModules, filepath.Join(ws.Root, module, "go.mod"))
+	}
+
+	return &workspaceManager{
+		manager:   &manager{goModPath: filepath.Join(ws.Root, WorkspaceFile), dir: ws.Root, lookup: lookup, mainModules: mainModules},
+		workspace: ws,
+	}
+}
+
+// GetDependencies merges the requirements of every workspace module. A
+// dependency is direct if any module requires it directly.
+func (w *workspaceManager) GetDependencies() ([]Dependency, error) {
+	merged := make(map[string]*Dependency)
+	var order []string
+
+	for _, module := range w.workspace.Modules {
+		deps, err := NewManagerWithPath(filepath.Join(w.workspace.Root, module, "go.mod")).GetDependencies()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, dep := range deps {
+			existing, ok := merged[dep.Path]
+			if !ok {
+				dep.Modules = []string{module}
+				merged[dep.Path] = &dep
+				order = append(order, dep.Path)
+				continue
+			}
+
+			existing.Modules = append(existing.Modules, module)
+			existing.Indirect = existing.Indirect && dep.Indirect
+			if semver.Compare(dep.Version, existing.Version) > 0 {
+				existing.Version = dep.Version
+			}
+		}
+	}
+
+	deps := make([]Dependency, 0, len(order))
+	for _, path := range order {
+		deps = append(deps, *merged[path])
+	}
+
+	w.sortDependencies(deps)
+
+	return deps, nil
+}
+
+// GetUpdatableDependencies lists the updates of the workspace build list and
+// records which modules require each dependency. Dependencies no workspace
+// module requires cannot be updated in a go.mod and are left out.
+func (w *workspaceManager) GetUpdatableDependencies(ctx context.Context) ([]Dependency, error) {
+	updatable, err := w.manager.GetUpdatableDependencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	required, err := w.GetDependencies()
+	if err != nil {
+		return nil, err
+	}
+
+	requirements := make(map[string]Dependency, len(required))
+	for _, dep := range required {
+		requirements[dep.Path] = dep
+	}
+
+	var deps []Dependency
+	for _, dep := range updatable {
+		requirement, ok := requirements[dep.Path]
+		if !ok {
+			continue
+		}
+		dep.Modules = requirement.Modules
+		dep.Indirect = requirement.Indirect
+		deps = append(deps, dep)
+	}
+
+	w.sortDependencies(deps)
+
+	return deps, nil
+}
+
+// GetReplacementUpdates returns no update: the replace directives of a
+// workspace may live in go.work or in any of its modules, so goup does not
+// rewrite them
+func (w *workspaceManager) GetReplacementUpdates(ctx context.Context, deps []Dependency) ([]Dependency, error) {
+	return nil, nil
+}
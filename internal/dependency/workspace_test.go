@@ -0,0 +1,93 @@
+package dependency
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeWorkspace(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+
+	files := map[string]string{
+		"go.work": "go 1.21\n\nuse (\n\t./api\n\t./tools/worker\n)\n",
+		"api/go.mod": `module example.com/api
+
+go 1.21
+
+require (
+	github.com/gin-gonic/gin v1.9.1
+	golang.org/x/crypto v0.14.0 // indirect
+)
+`,
+		"tools/worker/go.mod": `module example.com/worker
+
+go 1.21
+
+require (
+	github.com/gin-gonic/gin v1.9.0 // indirect
+	golang.org/x/crypto v0.17.0 // indirect
+	github.com/stretchr/testify v1.8.4
+)
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	return root
+}
+
+func TestLoadWorkspace(t *testing.T) {
+	root := writeWorkspace(t)
+
+	ws, err := LoadWorkspace(filepath.Join(root, "go.work"))
+
+	require.NoError(t, err)
+	assert.Equal(t, root, ws.Root)
+	assert.Equal(t, []string{"api", "tools/worker"}, ws.Modules)
+}
+
+func TestLoadWorkspaceWithoutModules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go.work")
+	require.NoError(t, os.WriteFile(path, []byte("go 1.21\n"), 0644))
+
+	_, err := LoadWorkspace(path)
+
+	assert.ErrorContains(t, err, "does not use any module")
+}
+
+func TestFindWorkspace(t *testing.T) {
+	root := writeWorkspace(t)
+
+	t.Setenv("GOWORK", "")
+	assert.Equal(t, filepath.Join(root, "go.work"), FindWorkspace(root))
+	assert.Empty(t, FindWorkspace(filepath.Join(root, "api")))
+
+	t.Setenv("GOWORK", "off")
+	assert.Empty(t, FindWorkspace(root))
+
+	t.Setenv("GOWORK", "/elsewhere/go.work")
+	assert.Equal(t, "/elsewhere/go.work", FindWorkspace(root))
+}
+
+func TestWorkspaceGetDependencies(t *testing.T) {
+	root := writeWorkspace(t)
+	ws, err := LoadWorkspace(filepath.Join(root, "go.work"))
+	require.NoError(t, err)
+
+	deps, err := NewWorkspaceManager(ws).GetDependencies()
+
+	require.NoError(t, err)
+	assert.Equal(t, []Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", Modules: []string{"api", "tools/worker"}},
+		{Path: "github.com/stretchr/testify", Version: "v1.8.4", Modules: []string{"tools/worker"}},
+		{Path: "golang.org/x/crypto", Version: "v0.17.0", Indirect: true, Modules: []string{"api", "tools/worker"}},
+	}, deps)
+}
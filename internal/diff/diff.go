@@ -0,0 +1,281 @@
+// Package diff computes line-based differences between two texts and
+// formats them as unified diffs.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Op is the kind of a line in a diff
+type Op int
+
+const (
+	Equal  Op = iota // The line is in both texts
+	Delete           // The line is only in the old text
+	Insert           // The line is only in the new text
+)
+
+// Line is one line of a diff
+type Line struct {
+	Op   Op
+	Text string // Without the trailing newline
+}
+
+// Lines returns the shortest edit script turning old into new, using the
+// Myers algorithm on lines. Lines shared at the start and the end of both
+// texts are matched first, so texts that grew at one end are cheap to compare.
+func Lines(old, new string) []Line {
+	a, b := splitLines(old), splitLines(new)
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]Line, 0, len(a)+len(b)-prefix-suffix)
+	for _, text := range a[:prefix] {
+		lines = append(lines, Line{Op: Equal, Text: text})
+	}
+	lines = append(lines, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, text := range a[len(a)-suffix:] {
+		lines = append(lines, Line{Op: Equal, Text: text})
+	}
+
+	return lines
+}
+
+// Unified formats the differences between old and new as a unified diff with
+// context lines around each change. It returns an empty string if the texts
+// are equal.
+func Unified(oldName, newName, old, new string, context int) string {
+	lines := Lines(old, new)
+
+	var sb strings.Builder
+	for _, h := range hunks(lines, context) {
+		if sb.Len() == 0 {
+			fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(h.oldStart, h.oldLines), hunkRange(h.newStart, h.newLines))
+		for _, line := range lines[h.from:h.to] {
+			sb.WriteString(prefix(line.Op))
+			sb.WriteString(line.Text)
+			sb.WriteByte('\n')
+		}
+	}
+
+	return sb.String()
+}
+
+// Added returns the lines of new that are not in old, which is what was added
+// to a file that only grows, such as a changelog
+func Added(old, new string) []string {
+	var added []string
+	for _, line := range Lines(old, new) {
+		if line.Op == Insert {
+			added = append(added, line.Text)
+		}
+	}
+	return added
+}
+
+// hunk is a run of changes with its context, as a range of diff lines
+type hunk struct {
+	from, to           int // Diff lines covered by the hunk
+	oldStart, oldLines int
+	newStart, newLines int
+}
+
+// hunks groups the changes of a diff, merging changes whose context overlaps
+func hunks(lines []Line, context int) []hunk {
+	var result []hunk
+	oldLine, newLine := 1, 1 // Line numbers before lines[i]
+
+	for i := 0; i < len(lines); {
+		if lines[i].Op == Equal {
+			oldLine++
+			newLine++
+			i++
+			continue
+		}
+
+		// Start the hunk up to context lines before the change
+		start := max(i-context, 0)
+		if len(result) > 0 {
+			start = max(start, result[len(result)-1].to)
+		}
+		h := hunk{from: start, oldStart: oldLine - (i - start), newStart: newLine - (i - start)}
+
+		// Extend it while the next change is within twice the context
+		end := i
+		for end < len(lines) {
+			if lines[end].Op != Equal {
+				end++
+				continue
+			}
+			next := end
+			for next < len(lines) && lines[next].Op == Equal {
+				next++
+			}
+			if next == len(lines) || next-end > 2*context {
+				end = min(end+context, len(lines))
+				break
+			}
+			end = next
+		}
+		h.to = end
+
+		for _, line := range lines[h.from:h.to] {
+			if line.Op != Insert {
+				h.oldLines++
+			}
+			if line.Op != Delete {
+				h.newLines++
+			}
+		}
+		result = append(result, h)
+
+		for _, line := range lines[i:end] {
+			if line.Op != Insert {
+				oldLine++
+			}
+			if line.Op != Delete {
+				newLine++
+			}
+		}
+		i = end
+	}
+
+	return result
+}
+
+// hunkRange formats the start and length of a hunk the way diff -u does
+func hunkRange(start, lines int) string {
+	switch lines {
+	case 0:
+		// An empty range refers to the line before it
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprintf("%d", start)
+	default:
+		return fmt.Sprintf("%d,%d", start, lines)
+	}
+}
+
+func prefix(op Op) string {
+	switch op {
+	case Delete:
+		return "-"
+	case Insert:
+		return "+"
+	default:
+		return " "
+	}
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// maxEdits bounds the edit distance searched by myers. Texts that differ more
+// are reported as entirely replaced, which keeps the trace small.
+const maxEdits = 1000
+
+// myers computes the shortest edit script between a and b. The furthest
+// reaching paths of every edit distance are kept to walk back the script,
+// which is cheap when the texts differ by a few lines.
+func myers(a, b []string) []Line {
+	n, m := len(a), len(b)
+	maxD := min(n+m, maxEdits)
+	offset := maxD + 1
+	v := make([]int, 2*maxD+3)
+	var trace [][]int
+
+	for d := 0; d <= maxD; d++ {
+		// Only diagonals -d..d can be reached with d edits
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace, d)
+			}
+		}
+	}
+
+	return replace(a, b)
+}
+
+// backtrack walks the trace from the end of both texts to build the script
+func backtrack(a, b []string, trace [][]int, d int) []Line {
+	x, y := len(a), len(b)
+	var reversed []Line
+
+	for ; d > 0; d-- {
+		// trace[d] holds the paths of d-1 edits, for diagonals -d..d
+		v := func(k int) int { return trace[d][k+d] }
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v(k-1) < v(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, Line{Op: Equal, Text: a[x]})
+		}
+		if x == prevX {
+			y--
+			reversed = append(reversed, Line{Op: Insert, Text: b[y]})
+		} else {
+			x--
+			reversed = append(reversed, Line{Op: Delete, Text: a[x]})
+		}
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		reversed = append(reversed, Line{Op: Equal, Text: a[x]})
+	}
+
+	lines := make([]Line, len(reversed))
+	for i, line := range reversed {
+		lines[len(reversed)-1-i] = line
+	}
+	return lines
+}
+
+// replace is the edit script deleting every line of a and inserting b
+func replace(a, b []string) []Line {
+	lines := make([]Line, 0, len(a)+len(b))
+	for _, text := range a {
+		lines = append(lines, Line{Op: Delete, Text: text})
+	}
+	for _, text := range b {
+		lines = append(lines, Line{Op: Insert, Text: text})
+	}
+	return lines
+}
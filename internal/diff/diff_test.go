@@ -0,0 +1,153 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		expected []Line
+	}{
+		{
+			name:     "equal",
+			old:      "a\nb\n",
+			new:      "a\nb\n",
+			expected: []Line{{Equal, "a"}, {Equal, "b"}},
+		},
+		{
+			name:     "both empty",
+			expected: []Line{},
+		},
+		{
+			name:     "from empty",
+			new:      "a\nb\n",
+			expected: []Line{{Insert, "a"}, {Insert, "b"}},
+		},
+		{
+			name:     "to empty",
+			old:      "a\n",
+			expected: []Line{{Delete, "a"}},
+		},
+		{
+			name:     "prepended",
+			old:      "b\nc\n",
+			new:      "a\nb\nc\n",
+			expected: []Line{{Insert, "a"}, {Equal, "b"}, {Equal, "c"}},
+		},
+		{
+			name:     "changed line",
+			old:      "a\nb\nc\n",
+			new:      "a\nx\nc\n",
+			expected: []Line{{Equal, "a"}, {Delete, "b"}, {Insert, "x"}, {Equal, "c"}},
+		},
+		{
+			name: "interleaved",
+			old:  "a\nb\nc\na\nb\nb\na\n",
+			new:  "c\nb\na\nb\na\nc\n",
+			expected: []Line{
+				{Delete, "a"}, {Delete, "b"}, {Equal, "c"}, {Insert, "b"}, {Equal, "a"},
+				{Equal, "b"}, {Delete, "b"}, {Equal, "a"}, {Insert, "c"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Lines(tt.old, tt.new))
+		})
+	}
+}
+
+func TestLinesRebuildsBothTexts(t *testing.T) {
+	old := "module example\n\ngo 1.21\n\nrequire (\n\tgithub.com/a v1.0.0\n\tgithub.com/b v1.2.0\n)\n"
+	new := "module example\n\ngo 1.22\n\nrequire (\n\tgithub.com/b v1.3.0\n\tgithub.com/c v0.1.0\n)\n"
+
+	var a, b []string
+	for _, line := range Lines(old, new) {
+		if line.Op != Insert {
+			a = append(a, line.Text)
+		}
+		if line.Op != Delete {
+			b = append(b, line.Text)
+		}
+	}
+
+	assert.Equal(t, old, strings.Join(a, "\n")+"\n")
+	assert.Equal(t, new, strings.Join(b, "\n")+"\n")
+}
+
+func TestLinesTooManyEdits(t *testing.T) {
+	var old, new strings.Builder
+	for i := 0; i < maxEdits; i++ {
+		old.WriteString("old\n")
+		new.WriteString("new\n")
+	}
+
+	lines := Lines(old.String(), new.String())
+
+	assert.Len(t, lines, 2*maxEdits)
+	assert.Equal(t, Line{Delete, "old"}, lines[0])
+	assert.Equal(t, Line{Insert, "new"}, lines[len(lines)-1])
+}
+
+func TestUnified(t *testing.T) {
+	old := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	new := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n"
+
+	expected := `--- a
++++ b
+@@ -1,5 +1,5 @@
+ 1
+ 2
+-3
++three
+ 4
+ 5
+@@ -11,2 +11,3 @@
+ 11
+ 12
++13
+`
+
+	assert.Equal(t, expected, Unified("a", "b", old, new, 2))
+}
+
+func TestUnifiedMergesCloseChanges(t *testing.T) {
+	old := "1\n2\n3\n4\n5\n"
+	new := "one\n2\n3\n4\nfive\n"
+
+	expected := `--- a
++++ b
+@@ -1,5 +1,5 @@
+-1
++one
+ 2
+ 3
+ 4
+-5
++five
+`
+
+	assert.Equal(t, expected, Unified("a", "b", old, new, 2))
+}
+
+func TestUnifiedEmptyRanges(t *testing.T) {
+	assert.Equal(t, "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+x\n+y\n", Unified("a", "b", "", "x\ny\n", 3))
+	assert.Equal(t, "--- a\n+++ b\n@@ -1 +0,0 @@\n-x\n", Unified("a", "b", "x\n", "", 3))
+}
+
+func TestUnifiedEqual(t *testing.T) {
+	assert.Empty(t, Unified("a", "b", "same\n", "same\n", 3))
+}
+
+func TestAdded(t *testing.T) {
+	old := "# Changelog\n\n## v1.0.0\n- First release\n"
+	new := "# Changelog\n\n## v1.1.0\n- Add Foo\n\n## v1.0.0\n- First release\n"
+
+	assert.Equal(t, []string{"## v1.1.0", "- Add Foo", ""}, Added(old, new))
+}
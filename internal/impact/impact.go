@@ -0,0 +1,240 @@
+// Package impact measures how much of the main module uses each of its
+// dependencies, from the package graph printed by go list -deps -test -json.
+// Test files count like the other files of their package.
+// Call sites are counted syntactically: every selector on the name of an
+// imported package is a reference to it.
+package impact
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"goup/internal/dependency"
+)
+
+// Package is a package listed by go list
+type Package struct {
+	ImportPath   string
+	Name         string
+	Dir          string
+	GoFiles      []string
+	CgoFiles     []string
+	TestGoFiles  []string
+	XTestGoFiles []string
+	Imports      []string // Packages imported directly
+	TestImports  []string
+	XTestImports []string
+	Deps         []string // Every package imported directly or transitively
+	ForTest      string   // Package under test, for the packages compiled for a test
+	Module       *Module
+	DepOnly      bool // Listed only as a dependency of the matched packages
+	Standard     bool
+}
+
+// Module is the module of a listed package
+type Module struct {
+	Path string
+	Main bool
+}
+
+// listFields are the fields go list prints, to keep the output small
+const listFields = "ImportPath,Name,Dir,GoFiles,CgoFiles,TestGoFiles,XTestGoFiles,Imports,TestImports,XTestImports,Deps,ForTest,Module,DepOnly,Standard"
+
+// Analyzer analyzes the packages of the module, or workspace, in a directory
+type Analyzer struct {
+	dir string
+}
+
+// NewAnalyzer creates an analyzer running go list in dir
+func NewAnalyzer(dir string) *Analyzer {
+	return &Analyzer{dir: dir}
+}
+
+// Analyze returns the impact of every dependency used by the packages of
+// the main modules, by module path. Dependencies no package uses are absent.
+func (a *Analyzer) Analyze(ctx context.Context) (map[string]*dependency.Impact, error) {
+	cmd := exec.CommandContext(ctx, "go", "list", "-e", "-deps", "-test", "-json="+listFields, "./...")
+	cmd.Dir = a.dir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list packages: %v\noutput:\n%s", err, stderr.String())
+	}
+
+	pkgs, err := Parse(bytes.NewReader(out))
+	if err != nil {
+		return nil, err
+	}
+	return Compute(pkgs)
+}
+
+// Parse decodes the JSON stream printed by go list -json
+func Parse(r io.Reader) ([]Package, error) {
+	var pkgs []Package
+	decoder := json.NewDecoder(r)
+	for decoder.More() {
+		var pkg Package
+		if err := decoder.Decode(&pkg); err != nil {
+			return nil, fmt.Errorf("failed to parse package list: %w", err)
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	return pkgs, nil
+}
+
+// Compute returns the impact of every dependency module on the packages of
+// the main modules. The source files of those packages, tests included, are
+// read to count the call sites.
+func Compute(pkgs []Package) (map[string]*dependency.Impact, error) {
+	// Module and name of every package of a dependency, and the dependencies
+	// of the test builds of each package under test
+	moduleOf := make(map[string]string)
+	names := make(map[string]string)
+	testDeps := make(map[string][]string)
+	for _, pkg := range pkgs {
+		if pkg.ForTest != "" {
+			testDeps[pkg.ForTest] = append(testDeps[pkg.ForTest], pkg.Deps...)
+			continue
+		}
+		if pkg.Standard || pkg.Module == nil || pkg.Module.Main {
+			continue
+		}
+		moduleOf[pkg.ImportPath] = pkg.Module.Path
+		names[pkg.ImportPath] = pkg.Name
+	}
+
+	impacts := make(map[string]*dependency.Impact)
+	impactOf := func(modulePath string) *dependency.Impact {
+		if impacts[modulePath] == nil {
+			impacts[modulePath] = &dependency.Impact{}
+		}
+		return impacts[modulePath]
+	}
+
+	for _, pkg := range pkgs {
+		if pkg.DepOnly || pkg.Module == nil || !pkg.Module.Main || isTestBuild(pkg, testDeps) {
+			continue
+		}
+
+		direct := make(map[string]bool)
+		for _, imported := range concat(pkg.Imports, pkg.TestImports, pkg.XTestImports) {
+			if modulePath := moduleOf[imported]; modulePath != "" {
+				direct[modulePath] = true
+			}
+		}
+		transitive := make(map[string]bool)
+		for _, dep := range concat(pkg.Deps, testDeps[pkg.ImportPath]) {
+			if modulePath := moduleOf[basePath(dep)]; modulePath != "" && !direct[modulePath] {
+				transitive[modulePath] = true
+			}
+		}
+
+		for modulePath := range direct {
+			impact := impactOf(modulePath)
+			impact.Importers = append(impact.Importers, pkg.ImportPath)
+		}
+		for modulePath := range transitive {
+			impact := impactOf(modulePath)
+			impact.Transitive = append(impact.Transitive, pkg.ImportPath)
+		}
+
+		calls, err := countCallSites(pkg, moduleOf, names)
+		if err != nil {
+			return nil, err
+		}
+		for modulePath, count := range calls {
+			impactOf(modulePath).CallSites += count
+		}
+	}
+
+	for _, impact := range impacts {
+		sort.Strings(impact.Importers)
+		sort.Strings(impact.Transitive)
+	}
+	return impacts, nil
+}
+
+// isTestBuild reports whether a package was only generated for a test: a
+// package recompiled for a test, or the main package of a test binary
+func isTestBuild(pkg Package, testDeps map[string][]string) bool {
+	if pkg.ForTest != "" {
+		return true
+	}
+	underTest, ok := strings.CutSuffix(pkg.ImportPath, ".test")
+	_, tested := testDeps[underTest]
+	return ok && pkg.Name == "main" && tested
+}
+
+// basePath strips the test binary go list appends to the import path of a
+// package recompiled for a test, e.g. "example.com/app [example.com/app.test]"
+func basePath(importPath string) string {
+	path, _, _ := strings.Cut(importPath, " ")
+	return path
+}
+
+// concat returns the elements of every list in a new slice
+func concat(lists ...[]string) []string {
+	var all []string
+	for _, list := range lists {
+		all = append(all, list...)
+	}
+	return all
+}
+
+// countCallSites counts the references to the packages of each dependency
+// module in the source files of a package. Dot and blank imports are not
+// counted.
+func countCallSites(pkg Package, moduleOf, names map[string]string) (map[string]int, error) {
+	calls := make(map[string]int)
+	fset := token.NewFileSet()
+
+	for _, name := range concat(pkg.GoFiles, pkg.CgoFiles, pkg.TestGoFiles, pkg.XTestGoFiles) {
+		file, err := parser.ParseFile(fset, filepath.Join(pkg.Dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", name, err)
+		}
+
+		// Module of the package behind each name the file imports
+		locals := make(map[string]string)
+		for _, spec := range file.Imports {
+			path, err := strconv.Unquote(spec.Path.Value)
+			if err != nil || moduleOf[path] == "" {
+				continue
+			}
+			local := names[path]
+			if spec.Name != nil {
+				local = spec.Name.Name
+			}
+			if local != "_" && local != "." && local != "" {
+				locals[local] = moduleOf[path]
+			}
+		}
+		if len(locals) == 0 {
+			continue
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				if id, ok := sel.X.(*ast.Ident); ok && locals[id.Name] != "" {
+					calls[locals[id.Name]]++
+				}
+			}
+			return true
+		})
+	}
+
+	return calls, nil
+}
@@ -0,0 +1,193 @@
+package impact
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"goup/internal/dependency"
+)
+
+// writeFile creates a source file below dir
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestParse(t *testing.T) {
+	out := `{
+	"ImportPath": "fmt",
+	"Name": "fmt",
+	"Standard": true,
+	"DepOnly": true
+}
+{
+	"ImportPath": "example.com/app",
+	"Name": "main",
+	"Dir": "/src/app",
+	"GoFiles": ["main.go"],
+	"Imports": ["fmt"],
+	"Deps": ["fmt"],
+	"Module": {"Path": "example.com/app", "Main": true, "GoMod": "/src/app/go.mod"}
+}
+`
+
+	pkgs, err := Parse(strings.NewReader(out))
+
+	require.NoError(t, err)
+	assert.Equal(t, []Package{
+		{ImportPath: "fmt", Name: "fmt", Standard: true, DepOnly: true},
+		{
+			ImportPath: "example.com/app", Name: "main", Dir: "/src/app", GoFiles: []string{"main.go"},
+			Imports: []string{"fmt"}, Deps: []string{"fmt"}, Module: &Module{Path: "example.com/app", Main: true},
+		},
+	}, pkgs)
+
+	_, err = Parse(strings.NewReader("{"))
+	assert.ErrorContains(t, err, "failed to parse package list")
+}
+
+func TestCompute(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "main.go", `package main
+
+import (
+	"context"
+	"fmt"
+
+	"example.com/app/store"
+	"github.com/gin-gonic/gin"
+	yaml "gopkg.in/yaml.v3"
+)
+
+func main() {
+	r := gin.Default()
+	r.GET("/", func(c *gin.Context) {})
+	var v map[string]any
+	_ = yaml.Unmarshal(nil, &v)
+	fmt.Println(store.Open())
+}
+`)
+	writeFile(t, dir, "routes.go", `package main
+
+import "github.com/gin-gonic/gin"
+
+func routes(r *gin.Engine) {}
+`)
+	writeFile(t, dir, "store/store.go", `package store
+
+import (
+	"context"
+	_ "github.com/lib/pq"
+	. "gopkg.in/yaml.v3"
+)
+
+func Open() error { _, err := Marshal(nil); return err }
+`)
+
+	app := &Module{Path: "example.com/app", Main: true}
+	pkgs := []Package{
+		{ImportPath: "fmt", Name: "fmt", Standard: true, DepOnly: true},
+		{ImportPath: "github.com/gin-gonic/gin/internal/json", Name: "json", Module: &Module{Path: "github.com/gin-gonic/gin"}, DepOnly: true},
+		{ImportPath: "github.com/gin-gonic/gin", Name: "gin", Module: &Module{Path: "github.com/gin-gonic/gin"}, DepOnly: true,
+			Imports: []string{"github.com/gin-gonic/gin/internal/json", "github.com/go-playground/validator/v10"}},
+		{ImportPath: "github.com/go-playground/validator/v10", Name: "validator", Module: &Module{Path: "github.com/go-playground/validator/v10"}, DepOnly: true},
+		{ImportPath: "github.com/lib/pq", Name: "pq", Module: &Module{Path: "github.com/lib/pq"}, DepOnly: true},
+		{ImportPath: "gopkg.in/yaml.v3", Name: "yaml", Module: &Module{Path: "gopkg.in/yaml.v3"}, DepOnly: true},
+		{
+			ImportPath: "example.com/app/store", Name: "store", Dir: filepath.Join(dir, "store"), GoFiles: []string{"store.go"}, Module: app,
+			Imports: []string{"github.com/lib/pq", "gopkg.in/yaml.v3"},
+			Deps:    []string{"github.com/lib/pq", "gopkg.in/yaml.v3"},
+		},
+		{
+			ImportPath: "example.com/app", Name: "main", Dir: dir, GoFiles: []string{"main.go", "routes.go"}, Module: app,
+			Imports: []string{"example.com/app/store", "fmt", "github.com/gin-gonic/gin", "gopkg.in/yaml.v3"},
+			Deps: []string{
+				"example.com/app/store", "fmt", "github.com/gin-gonic/gin", "github.com/gin-gonic/gin/internal/json",
+				"github.com/go-playground/validator/v10", "github.com/lib/pq", "gopkg.in/yaml.v3",
+			},
+		},
+	}
+
+	impacts, err := Compute(pkgs)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*dependency.Impact{
+		"github.com/gin-gonic/gin":               {Importers: []string{"example.com/app"}, CallSites: 3},
+		"github.com/go-playground/validator/v10": {Transitive: []string{"example.com/app"}},
+		"github.com/lib/pq":                      {Importers: []string{"example.com/app/store"}, Transitive: []string{"example.com/app"}},
+		"gopkg.in/yaml.v3":                       {Importers: []string{"example.com/app", "example.com/app/store"}, CallSites: 1},
+	}, impacts, "Blank and dot imports count as importers without call sites")
+}
+
+func TestComputeUnparsableFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "main.go", "package main\n\nfunc main() {\n")
+
+	_, err := Compute([]Package{
+		{ImportPath: "example.com/app", Name: "main", Dir: dir, GoFiles: []string{"main.go"}, Module: &Module{Path: "example.com/app", Main: true}},
+	})
+
+	assert.ErrorContains(t, err, "parsing main.go")
+}
+
+func TestAnalyzeTestOnlyImport(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "go.mod", `module example.com/app
+
+go 1.21
+
+require (
+	example.com/assert v1.0.0
+	example.com/mock v1.0.0
+)
+
+replace (
+	example.com/assert => ./assert
+	example.com/mock => ./mock
+)
+`)
+	writeFile(t, dir, "app.go", "package app\n\nfunc Sum(a, b int) int { return a + b }\n")
+	writeFile(t, dir, "app_test.go", `package app
+
+import (
+	"context"
+	"testing"
+
+	"example.com/assert"
+)
+
+func TestSum(t *testing.T) { assert.Equal(t, 3, Sum(1, 2)) }
+`)
+	writeFile(t, dir, "example_test.go", `package app_test
+
+import (
+	"context"
+	"testing"
+
+	"example.com/app"
+	"example.com/mock"
+)
+
+func TestMock(t *testing.T) { mock.New(app.Sum) }
+`)
+	writeFile(t, dir, "assert/go.mod", "module example.com/assert\n\ngo 1.21\n")
+	writeFile(t, dir, "assert/assert.go", "package assert\n\nimport \"testing\"\n\nfunc Equal(t *testing.T, want, got int) {}\n")
+	writeFile(t, dir, "mock/go.mod", "module example.com/mock\n\ngo 1.21\n")
+	writeFile(t, dir, "mock/mock.go", "package mock\n\nfunc New(any) {}\n")
+
+	impacts, err := NewAnalyzer(dir).Analyze(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*dependency.Impact{
+		"example.com/assert": {Importers: []string{"example.com/app"}, CallSites: 1},
+		"example.com/mock":   {Importers: []string{"example.com/app"}, CallSites: 1},
+	}, impacts, "Imports of test files count like the others")
+}
@@ -0,0 +1,180 @@
+// Package modgraph explains why modules are in the build list, from the
+// requirement graph printed by go mod graph and the go.mod files of the
+// module versions.
+package modgraph
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// GoModSource serves the go.mod files of module versions, e.g. a module proxy
+type GoModSource interface {
+	// GoMod returns the go.mod file of a module version
+	GoMod(ctx context.Context, path, version string) ([]byte, error)
+}
+
+// Graph is a module requirement graph. Nodes are "path@version", except for
+// the main modules which have no version.
+type Graph struct {
+	mains []string
+	edges map[string][]string // Requirements of each node, in go mod graph order
+}
+
+// Parse reads the output of go mod graph. The go and toolchain requirements
+// are left out.
+func Parse(r io.Reader) (*Graph, error) {
+	g := &Graph{edges: make(map[string][]string)}
+	mains := make(map[string]bool)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid module graph line: %q", line)
+		}
+		from, to := fields[0], fields[1]
+		if toolchain(to) {
+			continue
+		}
+
+		if isMain(from) && !mains[from] {
+			mains[from] = true
+			g.mains = append(g.mains, from)
+		}
+		g.edges[from] = append(g.edges[from], to)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading module graph: %w", err)
+	}
+
+	return g, nil
+}
+
+// Chain returns the shortest chain of requirements from a main module to a
+// version of the module at path, starting with the main module. The versions
+// are the ones required along the chain, which may be lower than the selected
+// ones. Since Go 1.17 go.mod also lists the indirect dependencies, so the
+// chain goes through the direct dependencies when possible. It returns nil if
+// no main module requires the module.
+func (g *Graph) Chain(path string, direct map[string]bool) []string {
+	if chain := g.shortest(path, direct); chain != nil {
+		return chain
+	}
+	return g.shortest(path, nil)
+}
+
+// shortest searches the graph breadth first. When direct is set, the main
+// modules only lead to the modules it contains.
+func (g *Graph) shortest(path string, direct map[string]bool) []string {
+	parent := make(map[string]string)
+	var queue []string
+	for _, main := range g.mains {
+		parent[main] = ""
+		queue = append(queue, main)
+	}
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		for _, next := range g.edges[node] {
+			if _, seen := parent[next]; seen {
+				continue
+			}
+			if direct != nil && isMain(node) && !direct[modulePath(next)] {
+				continue
+			}
+
+			parent[next] = node
+			if modulePath(next) == path {
+				var chain []string
+				for ; next != ""; next = parent[next] {
+					chain = append([]string{next}, chain...)
+				}
+				return chain
+			}
+			queue = append(queue, next)
+		}
+	}
+
+	return nil
+}
+
+// isMain returns true for the nodes of the main modules, which have no version
+func isMain(node string) bool {
+	return !strings.Contains(node, "@")
+}
+
+// modulePath returns the path of a node
+func modulePath(node string) string {
+	path, _, _ := strings.Cut(node, "@")
+	return path
+}
+
+// toolchain returns true for the go and toolchain version requirements
+func toolchain(node string) bool {
+	path := modulePath(node)
+	return path == "go" || path == "toolchain"
+}
+
+// Loader reads the requirement graph of the module, or workspace, in a
+// directory and the requirements of module versions
+type Loader struct {
+	dir    string
+	source GoModSource
+}
+
+// NewLoader creates a loader running go mod graph in dir and reading the
+// go.mod files of module versions from source
+func NewLoader(dir string, source GoModSource) *Loader {
+	return &Loader{
+		dir:    dir,
+		source: source,
+	}
+}
+
+// Graph returns the requirement graph of the main modules
+func (l *Loader) Graph(ctx context.Context) (*Graph, error) {
+	cmd := exec.CommandContext(ctx, "go", "mod", "graph")
+	cmd.Dir = l.dir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read module graph: %v\noutput:\n%s", err, stderr.String())
+	}
+	return Parse(bytes.NewReader(out))
+}
+
+// Requires returns the version of every module required by a module version
+func (l *Loader) Requires(ctx context.Context, path, version string) (map[string]string, error) {
+	data, err := l.source.GoMod(ctx, path, version)
+	if err != nil {
+		return nil, fmt.Errorf("downloading go.mod of %s@%s: %w", path, version, err)
+	}
+
+	f, err := modfile.ParseLax("go.mod", data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("parsing go.mod of %s@%s: %w", path, version, err)
+	}
+
+	requires := make(map[string]string, len(f.Require))
+	for _, req := range f.Require {
+		requires[req.Mod.Path] = req.Mod.Version
+	}
+	return requires, nil
+}
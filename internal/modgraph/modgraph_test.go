@@ -0,0 +1,140 @@
+package modgraph
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"goup/internal/proxy"
+)
+
+const graphOutput = `example.com/app go@1.22
+example.com/app github.com/gin-gonic/gin@v1.9.1
+example.com/app github.com/spf13/cobra@v1.8.0
+example.com/app golang.org/x/net@v0.17.0
+example.com/app golang.org/x/text@v0.13.0
+github.com/gin-gonic/gin@v1.9.1 go@1.20
+github.com/gin-gonic/gin@v1.9.1 golang.org/x/net@v0.10.0
+github.com/spf13/cobra@v1.8.0 github.com/spf13/pflag@v1.0.5
+github.com/spf13/pflag@v1.0.5 golang.org/x/text@v0.3.0
+golang.org/x/net@v0.10.0 golang.org/x/text@v0.9.0
+golang.org/x/net@v0.17.0 golang.org/x/text@v0.13.0
+`
+
+// direct are the direct dependencies of the graph above; x/net and x/text
+// are required by go.mod as indirect dependencies
+var direct = map[string]bool{"github.com/gin-gonic/gin": true, "github.com/spf13/cobra": true}
+
+func TestChain(t *testing.T) {
+	g, err := Parse(strings.NewReader(graphOutput))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		path   string
+		direct map[string]bool
+		want   []string
+	}{
+		{
+			name:   "direct dependency",
+			path:   "github.com/gin-gonic/gin",
+			direct: direct,
+			want:   []string{"example.com/app", "github.com/gin-gonic/gin@v1.9.1"},
+		},
+		{
+			name:   "through a direct dependency",
+			path:   "golang.org/x/net",
+			direct: direct,
+			want:   []string{"example.com/app", "github.com/gin-gonic/gin@v1.9.1", "golang.org/x/net@v0.10.0"},
+		},
+		{
+			name:   "shortest chain",
+			path:   "golang.org/x/text",
+			direct: direct,
+			want:   []string{"example.com/app", "github.com/gin-gonic/gin@v1.9.1", "golang.org/x/net@v0.10.0", "golang.org/x/text@v0.9.0"},
+		},
+		{
+			name: "without direct dependencies",
+			path: "golang.org/x/text",
+			want: []string{"example.com/app", "golang.org/x/text@v0.13.0"},
+		},
+		{
+			name:   "only required by go.mod",
+			path:   "golang.org/x/net",
+			direct: map[string]bool{"github.com/spf13/cobra": true},
+			want:   []string{"example.com/app", "golang.org/x/net@v0.17.0"},
+		},
+		{
+			name:   "not required",
+			path:   "example.com/other",
+			direct: direct,
+		},
+		{
+			name: "main module",
+			path: "example.com/app",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, g.Chain(tt.path, tt.direct))
+		})
+	}
+}
+
+func TestChainWorkspace(t *testing.T) {
+	g, err := Parse(strings.NewReader(`example.com/api github.com/gin-gonic/gin@v1.9.1
+example.com/worker github.com/spf13/cobra@v1.8.0
+github.com/spf13/cobra@v1.8.0 github.com/spf13/pflag@v1.0.5
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"example.com/worker", "github.com/spf13/cobra@v1.8.0", "github.com/spf13/pflag@v1.0.5"},
+		g.Chain("github.com/spf13/pflag", map[string]bool{"github.com/gin-gonic/gin": true, "github.com/spf13/cobra": true}))
+}
+
+func TestParseInvalidLine(t *testing.T) {
+	_, err := Parse(strings.NewReader("example.com/app\n"))
+
+	assert.EqualError(t, err, `invalid module graph line: "example.com/app"`)
+}
+
+// fakeSource serves go.mod files keyed by "path@version"
+type fakeSource map[string]string
+
+func (s fakeSource) GoMod(ctx context.Context, path, version string) ([]byte, error) {
+	data, ok := s[path+"@"+version]
+	if !ok {
+		return nil, proxy.ErrNotFound
+	}
+	return []byte(data), nil
+}
+
+func TestRequires(t *testing.T) {
+	loader := NewLoader(".", fakeSource{
+		"github.com/gin-gonic/gin@v1.9.2": `module github.com/gin-gonic/gin
+
+go 1.20
+
+require (
+	github.com/go-playground/validator/v10 v10.15.5
+	golang.org/x/net v0.17.0 // indirect
+)
+`,
+	})
+
+	requires, err := loader.Requires(context.Background(), "github.com/gin-gonic/gin", "v1.9.2")
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"github.com/go-playground/validator/v10": "v10.15.5",
+		"golang.org/x/net":                       "v0.17.0",
+	}, requires)
+
+	_, err = loader.Requires(context.Background(), "github.com/gin-gonic/gin", "v1.9.3")
+	assert.ErrorIs(t, err, proxy.ErrNotFound)
+	assert.ErrorContains(t, err, "downloading go.mod of github.com/gin-gonic/gin@v1.9.3")
+}
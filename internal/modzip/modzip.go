@@ -0,0 +1,143 @@
+// Package modzip opens module zips from the module cache, downloading the
+// ones that have not been cached through a module proxy.
+package modzip
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+// Source serves module zips, e.g. a module proxy
+type Source interface {
+	// Zip returns the zip archive of a module version
+	Zip(ctx context.Context, path, version string) ([]byte, error)
+}
+
+// Cache opens module zips from the module cache, or from the source when
+// they have not been downloaded yet
+type Cache struct {
+	source Source
+	dir    string // GOMODCACHE, empty to always use the source
+}
+
+// NewCache creates a cache reading the module cache in dir and downloading
+// missing zips from source. Downloaded zips are not written to dir.
+func NewCache(source Source, dir string) *Cache {
+	return &Cache{
+		source: source,
+		dir:    dir,
+	}
+}
+
+// Dir returns the module cache directory used by the go command
+func Dir() (string, error) {
+	out, err := exec.Command("go", "env", "GOMODCACHE").Output()
+	if err != nil {
+		return "", fmt.Errorf("reading go env: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Module is the content of a module zip
+type Module struct {
+	Path    string
+	Version string
+	files   map[string]*zip.File // By name relative to the module root
+}
+
+// Open returns the content of a module version
+func (c *Cache) Open(ctx context.Context, path, version string) (*Module, error) {
+	data, err := c.zip(ctx, path, version)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("reading zip of %s@%s: %w", path, version, err)
+	}
+
+	// Every file of a module zip is below "<path>@<version>/"
+	prefix := path + "@" + version + "/"
+	m := &Module{Path: path, Version: version, files: make(map[string]*zip.File, len(archive.File))}
+	for _, file := range archive.File {
+		if name, ok := strings.CutPrefix(file.Name, prefix); ok && name != "" && !strings.HasSuffix(name, "/") {
+			m.files[name] = file
+		}
+	}
+	return m, nil
+}
+
+// Files returns the names of the files of the module in lexical order,
+// relative to its root and separated by slashes
+func (m *Module) Files() []string {
+	names := make([]string, 0, len(m.files))
+	for name := range m.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ReadFile returns the content of a file of the module
+func (m *Module) ReadFile(name string) ([]byte, error) {
+	file, ok := m.files[name]
+	if !ok {
+		return nil, fmt.Errorf("%s@%s/%s: %w", m.Path, m.Version, name, os.ErrNotExist)
+	}
+
+	r, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// zip returns the zip of a module version, preferring the module cache
+func (c *Cache) zip(ctx context.Context, path, version string) ([]byte, error) {
+	if c.dir != "" {
+		cached, err := cachePath(c.dir, path, version)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := os.ReadFile(cached)
+		if err == nil {
+			return data, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("reading module cache: %w", err)
+		}
+	}
+
+	data, err := c.source.Zip(ctx, path, version)
+	if err != nil {
+		return nil, fmt.Errorf("downloading %s@%s: %w", path, version, err)
+	}
+	return data, nil
+}
+
+// cachePath returns where the go command stores the zip of a module version
+func cachePath(dir, path, version string) (string, error) {
+	escapedPath, err := module.EscapePath(path)
+	if err != nil {
+		return "", err
+	}
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cache", "download", filepath.FromSlash(escapedPath), "@v", escapedVersion+".zip"), nil
+}
@@ -0,0 +1,95 @@
+package modzip
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"goup/internal/proxy"
+)
+
+// fakeSource serves module zips keyed by "path@version"
+type fakeSource map[string][]byte
+
+func (s fakeSource) Zip(ctx context.Context, path, version string) ([]byte, error) {
+	data, ok := s[path+"@"+version]
+	if !ok {
+		return nil, proxy.ErrNotFound
+	}
+	return data, nil
+}
+
+// moduleZip builds a module zip holding the given files
+func moduleZip(t *testing.T, path, version string, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(path + "@" + version + "/" + name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestOpen(t *testing.T) {
+	cache := NewCache(fakeSource{
+		"example.com/lib@v1.0.0": moduleZip(t, "example.com/lib", "v1.0.0", map[string]string{
+			"go.mod":     "module example.com/lib\n",
+			"lib.go":     "package lib\n",
+			"sub/sub.go": "package sub\n",
+		}),
+	}, "")
+
+	m, err := cache.Open(context.Background(), "example.com/lib", "v1.0.0")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"go.mod", "lib.go", "sub/sub.go"}, m.Files())
+	data, err := m.ReadFile("sub/sub.go")
+	require.NoError(t, err)
+	assert.Equal(t, "package sub\n", string(data))
+
+	_, err = m.ReadFile("missing.go")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestOpenPrefersModuleCache(t *testing.T) {
+	dir := t.TempDir()
+	cached := filepath.Join(dir, "cache", "download", "github.com", "!burnt!sushi", "toml", "@v", "v1.3.2.zip")
+	require.NoError(t, os.MkdirAll(filepath.Dir(cached), 0755))
+	require.NoError(t, os.WriteFile(cached, moduleZip(t, "github.com/BurntSushi/toml", "v1.3.2", map[string]string{
+		"cached.go": "package toml\n",
+	}), 0644))
+
+	cache := NewCache(fakeSource{
+		"github.com/BurntSushi/toml@v1.3.2": moduleZip(t, "github.com/BurntSushi/toml", "v1.3.2", map[string]string{
+			"proxy.go": "package toml\n",
+		}),
+		"github.com/BurntSushi/toml@v1.4.0": moduleZip(t, "github.com/BurntSushi/toml", "v1.4.0", map[string]string{
+			"proxy.go": "package toml\n",
+		}),
+	}, dir)
+
+	m, err := cache.Open(context.Background(), "github.com/BurntSushi/toml", "v1.3.2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cached.go"}, m.Files(), "Module paths are case-encoded in the cache")
+
+	m, err = cache.Open(context.Background(), "github.com/BurntSushi/toml", "v1.4.0")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"proxy.go"}, m.Files(), "Versions missing from the cache are downloaded")
+}
+
+func TestOpenNotDownloadable(t *testing.T) {
+	_, err := NewCache(fakeSource{}, t.TempDir()).Open(context.Background(), "example.com/lib", "v1.0.0")
+
+	assert.ErrorIs(t, err, proxy.ErrNotFound)
+	assert.EqualError(t, err, "downloading example.com/lib@v1.0.0: module not found")
+}
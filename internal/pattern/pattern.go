@@ -0,0 +1,56 @@
+package pattern
+
+import "strings"
+
+// Match checks if a module path contains a pattern. Patterns with wildcards
+// are matched with Glob.
+func Match(path, pattern string) bool {
+	return strings.Contains(path, pattern)
+}
+
+// Glob checks if a whole module path matches a glob pattern: '*' matches any
+// sequence of characters, including '/', and '?' any single character
+func Glob(path, pattern string) bool {
+	p, s := []rune(pattern), []rune(path)
+	pi, si := 0, 0
+	// Position of the last '*' and of the path character it was tried against
+	star, mark := -1, 0
+
+	for si < len(s) {
+		switch {
+		case pi < len(p) && (p[pi] == '?' || p[pi] == s[si]):
+			pi++
+			si++
+		case pi < len(p) && p[pi] == '*':
+			star, mark = pi, si
+			pi++
+		case star >= 0:
+			// Let the last '*' match one more character
+			mark++
+			pi, si = star+1, mark
+		default:
+			return false
+		}
+	}
+
+	for pi < len(p) && p[pi] == '*' {
+		pi++
+	}
+	return pi == len(p)
+}
+
+// HasWildcards returns true if the pattern contains glob wildcards
+func HasWildcards(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?")
+}
+
+// Select checks if a module path is selected by a pattern, regardless of
+// case. A pattern with wildcards is a glob matching the whole path, e.g.
+// "golang.org/x/*"; any other pattern matches the paths containing it.
+func Select(path, pattern string) bool {
+	path, pattern = strings.ToLower(path), strings.ToLower(pattern)
+	if HasWildcards(pattern) {
+		return Glob(path, pattern)
+	}
+	return Match(path, pattern)
+}
@@ -0,0 +1,76 @@
+package pattern
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		path     string
+		pattern  string
+		expected bool
+	}{
+		{path: "github.com/gin-gonic/gin", pattern: "github.com/gin-gonic/gin", expected: true},
+		{path: "github.com/gin-gonic/gin", pattern: "gin-gonic", expected: true},
+		{path: "golang.org/x/crypto", pattern: "x/crypto", expected: true},
+		{path: "golang.org/x/crypto", pattern: "github.com", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Match(tt.path, tt.pattern))
+		})
+	}
+}
+
+func TestGlob(t *testing.T) {
+	tests := []struct {
+		path     string
+		pattern  string
+		expected bool
+	}{
+		{path: "golang.org/x/crypto", pattern: "golang.org/x/*", expected: true},
+		{path: "golang.org/x/exp/typeparams", pattern: "golang.org/x/*", expected: true},
+		{path: "example.com/golang.org/x/crypto", pattern: "golang.org/x/*", expected: false},
+		{path: "github.com/gin-gonic/gin", pattern: "github.com/gin*", expected: true},
+		{path: "github.com/gin-gonic/gin", pattern: "gin*", expected: false},
+		{path: "golang.org/x/crypto", pattern: "*crypto*", expected: true},
+		{path: "golang.org/x/crypto", pattern: "*crypt", expected: false},
+		{path: "github.com/redis/go-redis/v9", pattern: "*/v?", expected: true},
+		{path: "github.com/redis/go-redis/v9", pattern: "*/v", expected: false},
+		{path: "github.com/stretchr/testify", pattern: "*stretchr*testify", expected: true},
+		{path: "github.com/stretchr/testify", pattern: "*testify*stretchr*", expected: false},
+		{path: "github.com/aa/ab", pattern: "*a*b", expected: true},
+		{path: "github.com/gin-gonic/gin", pattern: "github.com/gin-gonic/gin", expected: true},
+		{path: "github.com/gin-gonic/gin", pattern: "github.com/gin-gonic", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path+" "+tt.pattern, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Glob(tt.path, tt.pattern))
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	tests := []struct {
+		path     string
+		pattern  string
+		expected bool
+	}{
+		{path: "github.com/gin-gonic/gin", pattern: "gin-gonic", expected: true},
+		{path: "github.com/gin-gonic/gin", pattern: "GitHub.com/Gin", expected: true},
+		{path: "golang.org/x/crypto", pattern: "golang.org/x/*", expected: true},
+		{path: "example.com/golang.org/x/crypto", pattern: "golang.org/x/*", expected: false},
+		{path: "github.com/redis/go-redis/v9", pattern: "*/v?", expected: true},
+		{path: "golang.org/x/crypto", pattern: "x/*/crypto", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path+" "+tt.pattern, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Select(tt.path, tt.pattern))
+		})
+	}
+}
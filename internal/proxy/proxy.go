@@ -0,0 +1,215 @@
+// Package proxy queries module versions with the Go module proxy protocol
+// (https://go.dev/ref/mod#goproxy-protocol), honouring GOPROXY and GONOPROXY
+// like the go command does.
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+// DefaultProxy is the GOPROXY value used when none is configured
+const DefaultProxy = "https://proxy.golang.org,direct"
+
+var (
+	// ErrDirect is returned for modules that must be fetched from their
+	// origin (GOPROXY "direct" or GONOPROXY); the go command resolves those
+	ErrDirect = errors.New("module is not served by a proxy")
+
+	// ErrNotFound is returned when no proxy knows the module or version
+	ErrNotFound = errors.New("module not found")
+
+	// ErrDisabled is returned when GOPROXY is "off"
+	ErrDisabled = errors.New("module lookup disabled by GOPROXY=off")
+)
+
+// Client fetches module information from the configured proxies
+type Client struct {
+	proxies []entry
+	noProxy string // GONOPROXY patterns
+	http    *http.Client
+}
+
+// entry is one element of the GOPROXY list
+type entry struct {
+	url         string
+	fallThrough bool // Followed by '|': try the next proxy after any error, not only "not found"
+}
+
+// NewClient creates a client configured like the go command, from 'go env'
+func NewClient() (*Client, error) {
+	out, err := exec.Command("go", "env", "-json", "GOPROXY", "GONOPROXY").Output()
+	if err != nil {
+		return nil, fmt.Errorf("reading go env: %w", err)
+	}
+
+	var env struct {
+		GOPROXY   string
+		GONOPROXY string
+	}
+	if err := json.Unmarshal(out, &env); err != nil {
+		return nil, fmt.Errorf("parsing go env: %w", err)
+	}
+
+	return NewClientWithConfig(env.GOPROXY, env.GONOPROXY, http.DefaultClient), nil
+}
+
+// NewClientWithConfig creates a client for explicit GOPROXY and GONOPROXY values
+func NewClientWithConfig(goproxy, noProxy string, httpClient *http.Client) *Client {
+	if strings.TrimSpace(goproxy) == "" {
+		goproxy = DefaultProxy
+	}
+
+	return &Client{
+		proxies: parseProxyList(goproxy),
+		noProxy: noProxy,
+		http:    httpClient,
+	}
+}
+
+// parseProxyList splits a GOPROXY value. Proxies separated by ',' are only
+// skipped when they do not know the module, those separated by '|' on any error.
+func parseProxyList(goproxy string) []entry {
+	var proxies []entry
+	for goproxy != "" {
+		end := strings.IndexAny(goproxy, ",|")
+		url, fallThrough := goproxy, false
+		if end >= 0 {
+			url, fallThrough = goproxy[:end], goproxy[end] == '|'
+			goproxy = goproxy[end+1:]
+		} else {
+			goproxy = ""
+		}
+
+		if url = strings.TrimSpace(url); url != "" {
+			proxies = append(proxies, entry{url: strings.TrimSuffix(url, "/"), fallThrough: fallThrough})
+		}
+	}
+	return proxies
+}
+
+// Versions returns the published versions of a module, in no particular order
+func (c *Client) Versions(ctx context.Context, path string) ([]string, error) {
+	data, err := c.fetch(ctx, path, "v/list")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(data)), nil
+}
+
+// Latest returns the version the proxy reports as the latest one, which is a
+// pseudo-version for modules without tags
+func (c *Client) Latest(ctx context.Context, path string) (string, error) {
+	data, err := c.fetch(ctx, path, "latest")
+	if err != nil {
+		return "", err
+	}
+
+	var info struct {
+		Version string
+	}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return "", fmt.Errorf("parsing latest version of %s: %w", path, err)
+	}
+	return info.Version, nil
+}
+
+// GoMod returns the go.mod file of a module version
+func (c *Client) GoMod(ctx context.Context, path, version string) ([]byte, error) {
+	escaped, err := module.EscapeVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	return c.fetch(ctx, path, "v/"+escaped+".mod")
+}
+
+// Zip returns the zip archive of a module version
+func (c *Client) Zip(ctx context.Context, path, version string) ([]byte, error) {
+	escaped, err := module.EscapeVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	return c.fetch(ctx, path, "v/"+escaped+".zip")
+}
+
+// fetch requests $GOPROXY/<module>/@<suffix> from each proxy in turn
+func (c *Client) fetch(ctx context.Context, path, suffix string) ([]byte, error) {
+	if module.MatchPrefixPatterns(c.noProxy, path) {
+		return nil, ErrDirect
+	}
+
+	escaped, err := module.EscapePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lastErr := ErrNotFound
+	for _, proxy := range c.proxies {
+		switch proxy.url {
+		case "direct":
+			return nil, ErrDirect
+		case "off":
+			return nil, ErrDisabled
+		}
+
+		data, err := c.get(ctx, proxy.url+"/"+escaped+"/@"+suffix)
+		if err == nil {
+			return data, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		lastErr = err
+		if !errors.Is(err, ErrNotFound) && !proxy.fallThrough {
+			return nil, err
+		}
+	}
+
+	return nil, lastErr
+}
+
+// get reads a proxy URL. file:// proxies are read from disk.
+func (c *Client) get(ctx context.Context, url string) ([]byte, error) {
+	if dir, ok := strings.CutPrefix(url, "file://"); ok {
+		data, err := os.ReadFile(filepath.FromSlash(dir))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%s: %w", url, ErrNotFound)
+		}
+		return data, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return nil, fmt.Errorf("%s: %w", url, ErrNotFound)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%s: %s", url, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", url, err)
+	}
+	return data, nil
+}
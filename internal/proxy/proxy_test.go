@@ -0,0 +1,154 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestProxy serves the given files, keyed by URL path
+func newTestProxy(t *testing.T, files map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(content))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestParseProxyList(t *testing.T) {
+	assert.Equal(t, []entry{
+		{url: "https://a.example"},
+		{url: "https://b.example", fallThrough: true},
+		{url: "direct"},
+	}, parseProxyList("https://a.example/,https://b.example|direct"))
+}
+
+func TestClientVersions(t *testing.T) {
+	server := newTestProxy(t, map[string]string{
+		"/github.com/!burnt!sushi/toml/@v/list": "v1.2.0\nv1.3.2\nv1.2.1\n",
+	})
+	client := NewClientWithConfig(server.URL, "", server.Client())
+
+	versions, err := client.Versions(context.Background(), "github.com/BurntSushi/toml")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v1.2.0", "v1.3.2", "v1.2.1"}, versions, "Module paths are case-encoded")
+}
+
+func TestClientLatestGoModAndZip(t *testing.T) {
+	server := newTestProxy(t, map[string]string{
+		"/example.com/lib/@latest":       `{"Version":"v0.0.0-20240101000000-abcdefabcdef","Time":"2024-01-01T00:00:00Z"}`,
+		"/example.com/lib/@v/v1.0.0.mod": "module example.com/lib\n",
+		"/example.com/lib/@v/v1.0.0.zip": "PK",
+	})
+	client := NewClientWithConfig(server.URL, "", server.Client())
+
+	latest, err := client.Latest(context.Background(), "example.com/lib")
+	require.NoError(t, err)
+	assert.Equal(t, "v0.0.0-20240101000000-abcdefabcdef", latest)
+
+	goMod, err := client.GoMod(context.Background(), "example.com/lib", "v1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "module example.com/lib\n", string(goMod))
+
+	zip, err := client.Zip(context.Background(), "example.com/lib", "v1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "PK", string(zip))
+}
+
+func TestClientFallsBackToNextProxy(t *testing.T) {
+	empty := newTestProxy(t, nil)
+	full := newTestProxy(t, map[string]string{"/example.com/lib/@v/list": "v1.0.0\n"})
+	client := NewClientWithConfig(empty.URL+","+full.URL, "", http.DefaultClient)
+
+	versions, err := client.Versions(context.Background(), "example.com/lib")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v1.0.0"}, versions)
+}
+
+func TestClientErrors(t *testing.T) {
+	server := newTestProxy(t, nil)
+
+	tests := []struct {
+		name    string
+		goproxy string
+		noProxy string
+		want    error
+	}{
+		{name: "not found", goproxy: server.URL, want: ErrNotFound},
+		{name: "not found then direct", goproxy: server.URL + ",direct", want: ErrDirect},
+		{name: "private module", goproxy: server.URL, noProxy: "example.com/*", want: ErrDirect},
+		{name: "proxy disabled", goproxy: "off", want: ErrDisabled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClientWithConfig(tt.goproxy, tt.noProxy, server.Client())
+
+			_, err := client.Versions(context.Background(), "example.com/lib")
+
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestClientServerError(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	full := newTestProxy(t, map[string]string{"/example.com/lib/@v/list": "v1.0.0\n"})
+
+	t.Run("comma stops at the error", func(t *testing.T) {
+		client := NewClientWithConfig(failing.URL+","+full.URL, "", http.DefaultClient)
+
+		_, err := client.Versions(context.Background(), "example.com/lib")
+		assert.ErrorContains(t, err, "500 Internal Server Error")
+	})
+
+	t.Run("pipe falls through", func(t *testing.T) {
+		client := NewClientWithConfig(failing.URL+"|"+full.URL, "", http.DefaultClient)
+
+		versions, err := client.Versions(context.Background(), "example.com/lib")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"v1.0.0"}, versions)
+	})
+}
+
+func TestClientFileProxy(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "example.com", "lib", "@v"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "example.com", "lib", "@v", "list"), []byte("v1.0.0\nv1.1.0\n"), 0644))
+	client := NewClientWithConfig("file://"+filepath.ToSlash(root), "", http.DefaultClient)
+
+	versions, err := client.Versions(context.Background(), "example.com/lib")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v1.0.0", "v1.1.0"}, versions)
+
+	_, err = client.Versions(context.Background(), "example.com/other")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestClientCancelled(t *testing.T) {
+	server := newTestProxy(t, map[string]string{"/example.com/lib/@v/list": "v1.0.0\n"})
+	client := NewClientWithConfig(server.URL, "", server.Client())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.Versions(ctx, "example.com/lib")
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -40,4 +40,10 @@ type UIInterface interface {
 
 	// PrintDependencies displays a list of dependencies
 	PrintDependencies(deps []dependency.Dependency, title string)
+
+	// PrintChangelog displays the changelog diff of a dependency, if it has one
+	PrintChangelog(dep dependency.Dependency)
+
+	// PrintImpact displays the main module packages using a dependency, if it was analyzed
+	PrintImpact(dep dependency.Dependency)
 }
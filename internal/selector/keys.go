@@ -0,0 +1,103 @@
+package selector
+
+import (
+	"bytes"
+	"unicode"
+	"unicode/utf8"
+)
+
+// keyCode identifies the keys the terminal selector reacts to
+type keyCode int
+
+const (
+	keyRune keyCode = iota // A printable character
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyTab
+	keyEnter
+	keyEscape
+	keyBackspace
+	keyCtrlC
+)
+
+// key is a key pressed on a raw terminal
+type key struct {
+	code keyCode
+	r    rune // Character of a keyRune
+}
+
+// is returns true if the key is the given printable character
+func (k key) is(r rune) bool {
+	return k.code == keyRune && k.r == r
+}
+
+// escapeKeys are the sequences sent by the arrow keys, in normal and
+// application cursor mode, and by Shift-Tab
+var escapeKeys = map[string]keyCode{
+	"\x1b[A": keyUp,
+	"\x1b[B": keyDown,
+	"\x1b[C": keyRight,
+	"\x1b[D": keyLeft,
+	"\x1bOA": keyUp,
+	"\x1bOB": keyDown,
+	"\x1bOC": keyRight,
+	"\x1bOD": keyLeft,
+	"\x1b[Z": keyTab,
+}
+
+// decodeKeys splits the bytes read from a raw terminal into keys. A read
+// holds a whole escape sequence, so a lone ESC is the Escape key. Other
+// sequences and control characters are ignored.
+func decodeKeys(data []byte) []key {
+	var keys []key
+	for len(data) > 0 {
+		if data[0] == '\x1b' {
+			code, size, ok := decodeEscape(data)
+			if ok {
+				keys = append(keys, key{code: code})
+			}
+			data = data[size:]
+			continue
+		}
+
+		r, size := utf8.DecodeRune(data)
+		data = data[size:]
+		switch {
+		case r == '\r' || r == '\n':
+			keys = append(keys, key{code: keyEnter})
+		case r == '\t':
+			keys = append(keys, key{code: keyTab})
+		case r == 0x7f || r == '\b':
+			keys = append(keys, key{code: keyBackspace})
+		case r == 0x03:
+			keys = append(keys, key{code: keyCtrlC})
+		case r != utf8.RuneError && unicode.IsPrint(r):
+			keys = append(keys, key{code: keyRune, r: r})
+		}
+	}
+	return keys
+}
+
+// decodeEscape decodes the escape sequence at the start of data, returning
+// its size and false for the sequences the selector does not use
+func decodeEscape(data []byte) (keyCode, int, bool) {
+	for sequence, code := range escapeKeys {
+		if bytes.HasPrefix(data, []byte(sequence)) {
+			return code, len(sequence), true
+		}
+	}
+
+	if len(data) == 1 || (data[1] != '[' && data[1] != 'O') {
+		return keyEscape, 1, true
+	}
+
+	// Skip an unknown control sequence up to its final byte
+	for i := 2; i < len(data); i++ {
+		if data[i] >= 0x40 && data[i] <= 0x7e {
+			return 0, i + 1, false
+		}
+	}
+	return 0, len(data), false
+}
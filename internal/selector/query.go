@@ -0,0 +1,282 @@
+package selector
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"goup/internal/dependency"
+	"goup/internal/pattern"
+)
+
+// QueryError is an invalid selection, reported at the column of the
+// offending part of the input
+type QueryError struct {
+	Column int // 1-based position in the input, in characters
+	Msg    string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("%s at column %d", e.Msg, e.Column)
+}
+
+func queryErrorf(column int, format string, args ...interface{}) error {
+	return &QueryError{Column: column, Msg: fmt.Sprintf(format, args...)}
+}
+
+type tokenKind int
+
+const (
+	tokenWord  tokenKind = iota // A number, range, keyword or pattern
+	tokenNot                    // '!' excluding the next term
+	tokenComma                  // ',' separating terms, like whitespace
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	column int
+}
+
+// tokenize splits a selection into words, '!' and ','. Words are lowercased
+// as module paths are matched regardless of case.
+func tokenize(input string) ([]token, error) {
+	runes := []rune(input)
+	separator := func(i int) bool {
+		return i < 0 || i >= len(runes) || unicode.IsSpace(runes[i]) || runes[i] == ','
+	}
+
+	var tokens []token
+	for i := 0; i < len(runes); {
+		switch r := runes[i]; {
+		case unicode.IsSpace(r):
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", column: i + 1})
+			i++
+		case r == '!':
+			// '!' starts a term and applies to the word right after it
+			if !separator(i - 1) {
+				return nil, queryErrorf(i+1, `unexpected "!"`)
+			}
+			if separator(i+1) || runes[i+1] == '!' {
+				return nil, queryErrorf(i+1, `expected a selection right after "!"`)
+			}
+			tokens = append(tokens, token{kind: tokenNot, text: "!", column: i + 1})
+			i++
+		default:
+			start := i
+			for i < len(runes) && !separator(i) {
+				if runes[i] == '!' {
+					return nil, queryErrorf(i+1, `unexpected "!"`)
+				}
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: strings.ToLower(string(runes[start:i])), column: start + 1})
+		}
+	}
+	return tokens, nil
+}
+
+type termKind int
+
+const (
+	termSelect termKind = iota // Adds dependencies: numbers, ranges, patterns and all
+	termFilter                 // Keeps the dependencies of a type or update level
+)
+
+// queryTerm is a part of a selection matching some dependencies
+type queryTerm struct {
+	kind    termKind
+	group   string // Filters of the same group are alternatives, e.g. direct and indirect
+	negated bool
+	pattern bool // Whether it is a module path pattern, which must match a dependency
+	text    string
+	column  int
+	match   func(index int, dep dependency.Dependency) bool
+}
+
+// query is a parsed selection, e.g. "1-3,5", "direct patch" or "all !golang.org/x/*"
+type query struct {
+	terms []queryTerm
+}
+
+// filters are the keywords narrowing down the selection
+var filters = map[string]queryTerm{
+	"direct":   {group: "type", match: func(_ int, dep dependency.Dependency) bool { return !dep.Indirect }},
+	"indirect": {group: "type", match: func(_ int, dep dependency.Dependency) bool { return dep.Indirect }},
+	"patch": {group: "level", match: func(_ int, dep dependency.Dependency) bool {
+		return dependency.PolicyPatch.Allows(dep.Version, dep.NewVersion)
+	}},
+	"minor": {group: "level", match: func(_ int, dep dependency.Dependency) bool {
+		return dependency.PolicyMinor.Allows(dep.Version, dep.NewVersion)
+	}},
+}
+
+// parseQuery parses a selection among count numbered dependencies
+func parseQuery(input string, count int) (*query, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, queryErrorf(1, "empty selection")
+	}
+
+	q := &query{}
+	afterComma := true // Nothing before the first term
+	negated := false
+	for _, tok := range tokens {
+		switch tok.kind {
+		case tokenComma:
+			if afterComma {
+				return nil, queryErrorf(tok.column, `expected a selection before ","`)
+			}
+			afterComma = true
+		case tokenNot:
+			negated = true
+		default:
+			t, err := parseTerm(tok, count)
+			if err != nil {
+				return nil, err
+			}
+			t.negated = negated
+			q.terms = append(q.terms, t)
+			negated = false
+			afterComma = false
+		}
+	}
+
+	if last := tokens[len(tokens)-1]; last.kind == tokenComma {
+		return nil, queryErrorf(last.column, `expected a selection after ","`)
+	}
+	return q, nil
+}
+
+// parseTerm parses a word: a keyword, a number, a range of numbers or a
+// module path pattern
+func parseTerm(tok token, count int) (queryTerm, error) {
+	if tok.text == "all" {
+		return queryTerm{kind: termSelect, text: tok.text, column: tok.column, match: func(int, dependency.Dependency) bool { return true }}, nil
+	}
+	if filter, ok := filters[tok.text]; ok {
+		filter.kind = termFilter
+		filter.text = tok.text
+		filter.column = tok.column
+		return filter, nil
+	}
+
+	if isNumeric(tok.text) {
+		num, err := strconv.Atoi(tok.text)
+		if err != nil || num < 1 || num > count {
+			return queryTerm{}, queryErrorf(tok.column, "number %s is out of range (1-%d)", tok.text, count)
+		}
+		return queryTerm{kind: termSelect, text: tok.text, column: tok.column, match: func(index int, _ dependency.Dependency) bool {
+			return index == num-1
+		}}, nil
+	}
+
+	// Only digits and dashes make a range; go-redis is a pattern
+	if strings.Trim(tok.text, "0123456789-") == "" {
+		return parseRange(tok, count)
+	}
+
+	text := tok.text
+	return queryTerm{kind: termSelect, pattern: true, text: text, column: tok.column, match: func(_ int, dep dependency.Dependency) bool {
+		return pattern.Select(dep.Path, text)
+	}}, nil
+}
+
+// parseRange parses a range of numbers, e.g. "1-3"
+func parseRange(tok token, count int) (queryTerm, error) {
+	startText, endText, ok := strings.Cut(tok.text, "-")
+	if !ok || !isNumeric(startText) || !isNumeric(endText) {
+		return queryTerm{}, queryErrorf(tok.column, "invalid range format: %s", tok.text)
+	}
+
+	start, startErr := strconv.Atoi(startText)
+	end, endErr := strconv.Atoi(endText)
+	if startErr != nil || endErr != nil || start < 1 || end > count || start > end {
+		return queryTerm{}, queryErrorf(tok.column, "range %s is out of bounds (1-%d)", tok.text, count)
+	}
+
+	return queryTerm{kind: termSelect, text: tok.text, column: tok.column, match: func(index int, _ dependency.Dependency) bool {
+		return index >= start-1 && index < end
+	}}, nil
+}
+
+func isNumeric(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
+
+// evaluate returns the dependencies selected by the query. The numbers,
+// ranges, patterns and all add dependencies in the order they are written,
+// every dependency without any; the filters then keep those matching one
+// filter of each group, and the negated terms remove the ones they match.
+func (q *query) evaluate(deps []dependency.Dependency) ([]dependency.Dependency, error) {
+	var order []int
+	chosen := make(map[int]bool)
+	hasSelect := false
+	filterGroups := make(map[string][]queryTerm)
+
+	for _, t := range q.terms {
+		matched := false
+		for i, dep := range deps {
+			if t.match(i, dep) {
+				matched = true
+				if t.kind == termSelect && !t.negated && !chosen[i] {
+					chosen[i] = true
+					order = append(order, i)
+				}
+			}
+		}
+		if t.pattern && !matched {
+			return nil, queryErrorf(t.column, "no dependencies match pattern: %s", t.text)
+		}
+
+		if t.negated {
+			continue
+		}
+		if t.kind == termSelect {
+			hasSelect = true
+		} else {
+			filterGroups[t.group] = append(filterGroups[t.group], t)
+		}
+	}
+
+	if !hasSelect {
+		for i := range deps {
+			order = append(order, i)
+		}
+	}
+
+	selected := []dependency.Dependency{}
+	for _, i := range order {
+		if q.keeps(i, deps[i], filterGroups) {
+			selected = append(selected, deps[i])
+		}
+	}
+	return selected, nil
+}
+
+// keeps reports whether a dependency matches a filter of every group and
+// none of the negated terms
+func (q *query) keeps(index int, dep dependency.Dependency, filterGroups map[string][]queryTerm) bool {
+	for _, group := range filterGroups {
+		matched := false
+		for _, t := range group {
+			matched = matched || t.match(index, dep)
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	for _, t := range q.terms {
+		if t.negated && t.match(index, dep) {
+			return false
+		}
+	}
+	return true
+}
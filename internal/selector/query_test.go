@@ -0,0 +1,107 @@
+package selector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"goup/internal/dependency"
+)
+
+var queryDeps = []dependency.Dependency{
+	{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2"},
+	{Path: "github.com/redis/go-redis/v9", Version: "v9.0.5", NewVersion: "v9.5.1"},
+	{Path: "golang.org/x/net", Version: "v0.10.0", NewVersion: "v0.10.1", Indirect: true},
+	{Path: "golang.org/x/text", Version: "v0.9.0", NewVersion: "v0.14.0", Indirect: true},
+	{Path: "github.com/foo/bar", Version: "v1.5.0", NewVersion: "v2.0.0", NewPath: "github.com/foo/bar/v2"},
+}
+
+func TestParseSelection(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{name: "numbers", input: "1,3", want: []string{"github.com/gin-gonic/gin", "golang.org/x/net"}},
+		{name: "numbers in the order written", input: "3 1", want: []string{"golang.org/x/net", "github.com/gin-gonic/gin"}},
+		{name: "range", input: "2-3", want: []string{"github.com/redis/go-redis/v9", "golang.org/x/net"}},
+		{name: "duplicates", input: "1,1-2,gin", want: []string{"github.com/gin-gonic/gin", "github.com/redis/go-redis/v9"}},
+		{name: "all", input: "ALL", want: paths(queryDeps)},
+		{name: "pattern with a dash is not a range", input: "go-redis", want: []string{"github.com/redis/go-redis/v9"}},
+		{name: "glob is anchored", input: "golang.org/x/*", want: []string{"golang.org/x/net", "golang.org/x/text"}},
+		{name: "glob with a single character", input: "*/v?", want: []string{"github.com/redis/go-redis/v9"}},
+		{name: "negation", input: "all !golang.org/x/*", want: []string{"github.com/gin-gonic/gin", "github.com/redis/go-redis/v9", "github.com/foo/bar"}},
+		{name: "negation alone starts from all", input: "!1, !3-5", want: []string{"github.com/redis/go-redis/v9"}},
+		{name: "direct", input: "direct", want: []string{"github.com/gin-gonic/gin", "github.com/redis/go-redis/v9", "github.com/foo/bar"}},
+		{name: "indirect", input: "indirect", want: []string{"golang.org/x/net", "golang.org/x/text"}},
+		{name: "patch", input: "patch", want: []string{"github.com/gin-gonic/gin", "golang.org/x/net"}},
+		{name: "minor", input: "minor", want: []string{"github.com/gin-gonic/gin", "github.com/redis/go-redis/v9", "golang.org/x/net", "golang.org/x/text"}},
+		{name: "only minor bumps", input: "minor !patch", want: []string{"github.com/redis/go-redis/v9", "golang.org/x/text"}},
+		{name: "filters of different groups", input: "direct patch", want: []string{"github.com/gin-gonic/gin"}},
+		{name: "filters of the same group", input: "direct indirect", want: paths(queryDeps)},
+		{name: "filter narrows a pattern", input: "golang.org/x/* patch", want: []string{"golang.org/x/net"}},
+		{name: "major upgrades", input: "!minor", want: []string{"github.com/foo/bar"}},
+		{name: "no dependency left", input: "indirect !indirect", want: []string{}},
+	}
+
+	parser := NewSelectionParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := parser.ParseSelection(tt.input, queryDeps)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, append([]string{}, paths(selected)...))
+		})
+	}
+}
+
+func TestParseSelectionErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+		column  int
+	}{
+		{name: "number out of range", input: "1, 9", wantErr: "number 9 is out of range (1-5) at column 4", column: 4},
+		{name: "zero", input: "0", wantErr: "number 0 is out of range (1-5) at column 1", column: 1},
+		{name: "range out of bounds", input: "gin 4-6", wantErr: "range 4-6 is out of bounds (1-5) at column 5", column: 5},
+		{name: "reversed range", input: "3-1", wantErr: "range 3-1 is out of bounds (1-5) at column 1", column: 1},
+		{name: "invalid range", input: "1-2-3", wantErr: "invalid range format: 1-2-3 at column 1", column: 1},
+		{name: "open range", input: "2-", wantErr: "invalid range format: 2- at column 1", column: 1},
+		{name: "no match", input: "all !github.com/none", wantErr: "no dependencies match pattern: github.com/none at column 6", column: 6},
+		{name: "empty", input: "  ", wantErr: "empty selection at column 1", column: 1},
+		{name: "leading comma", input: ",1", wantErr: `expected a selection before "," at column 1`, column: 1},
+		{name: "double comma", input: "1,,2", wantErr: `expected a selection before "," at column 3`, column: 3},
+		{name: "trailing comma", input: "1, 2,", wantErr: `expected a selection after "," at column 5`, column: 5},
+		{name: "lone negation", input: "all !", wantErr: `expected a selection right after "!" at column 5`, column: 5},
+		{name: "negation followed by a space", input: "! gin", wantErr: `expected a selection right after "!" at column 1`, column: 1},
+		{name: "double negation", input: "!!gin", wantErr: `expected a selection right after "!" at column 1`, column: 1},
+		{name: "negation inside a word", input: "gin!redis", wantErr: `unexpected "!" at column 4`, column: 4},
+	}
+
+	parser := NewSelectionParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parser.ParseSelection(tt.input, queryDeps)
+
+			require.EqualError(t, err, tt.wantErr)
+			var queryErr *QueryError
+			require.ErrorAs(t, err, &queryErr)
+			assert.Equal(t, tt.column, queryErr.Column)
+		})
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tokens, err := tokenize("All, !Golang.org/x/* 2-3")
+
+	require.NoError(t, err)
+	assert.Equal(t, []token{
+		{kind: tokenWord, text: "all", column: 1},
+		{kind: tokenComma, text: ",", column: 4},
+		{kind: tokenNot, text: "!", column: 6},
+		{kind: tokenWord, text: "golang.org/x/*", column: 7},
+		{kind: tokenWord, text: "2-3", column: 22},
+	}, tokens)
+}
@@ -1,8 +1,8 @@
 package selector
 
 import (
+	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 
 	"goup/internal/dependency"
@@ -28,6 +28,11 @@ func (s *interactiveSelector) Select(deps []dependency.Dependency, includeIndire
 		return SelectionResult{Selected: []dependency.Dependency{}}
 	}
 
+	// Riskiest updates first when their impact was analyzed; the numbers
+	// entered refer to this order
+	deps = append([]dependency.Dependency(nil), deps...)
+	dependency.SortByImpact(deps)
+
 	typeStr := "direct"
 	if includeIndirect {
 		typeStr = "all"
@@ -53,6 +58,7 @@ func (s *interactiveSelector) Select(deps []dependency.Dependency, includeIndire
 		selected, err := s.parser.ParseSelection(input, deps)
 		if err != nil {
 			s.ui.Error("Invalid selection: %v", err)
+			showErrorColumn(input, err)
 			s.ui.Info("Please try again or press Enter to cancel")
 			continue
 		}
@@ -66,6 +72,7 @@ func (s *interactiveSelector) Select(deps []dependency.Dependency, includeIndire
 		// Show selected dependencies and confirm
 		s.ui.Success("Selected %d dependencies:", len(selected))
 		s.ui.PrintDependencies(selected, "")
+		s.showDetails(selected)
 
 		if s.ui.Confirm("Proceed with these selected dependencies?") {
 			return SelectionResult{Selected: selected}
@@ -75,6 +82,16 @@ func (s *interactiveSelector) Select(deps []dependency.Dependency, includeIndire
 	}
 }
 
+// showDetails prints what changed in the changelogs of the selected
+// dependencies and where they are used, for those fetched with --changelog
+// and --impact
+func (s *interactiveSelector) showDetails(selected []dependency.Dependency) {
+	for _, dep := range selected {
+		s.ui.PrintChangelog(dep)
+		s.ui.PrintImpact(dep)
+	}
+}
+
 func (s *interactiveSelector) showSelectionHelp() {
 	s.ui.Info("Selection options:")
 
@@ -82,6 +99,8 @@ func (s *interactiveSelector) showSelectionHelp() {
 		"  📝 Enter numbers (e.g., 1,3,5 or 1-3 or 1,3-5)",
 		"  🔄 Enter 'all' to select all dependencies",
 		"  🔍 Enter package names or patterns (e.g., 'github.com/gin*')",
+		"  🏷️  Keep only direct, indirect, patch or minor updates (e.g., 'direct patch')",
+		"  🚫 Exclude with '!' (e.g., 'all !golang.org/x/*')",
 		"  ❌ Press Enter without input to cancel",
 	}
 
@@ -91,6 +110,17 @@ func (s *interactiveSelector) showSelectionHelp() {
 	fmt.Println()
 }
 
+// showErrorColumn points at the part of the input an invalid selection was
+// found at
+func showErrorColumn(input string, err error) {
+	var queryErr *QueryError
+	if !errors.As(err, &queryErr) {
+		return
+	}
+	fmt.Println("  " + input)
+	fmt.Println("  " + strings.Repeat(" ", queryErr.Column-1) + "^")
+}
+
 // selectionParser implements the Parser interface
 type selectionParser struct{}
 
@@ -101,122 +131,9 @@ func NewSelectionParser() Parser {
 
 // ParseSelection parses user input and returns selected dependencies
 func (p *selectionParser) ParseSelection(input string, deps []dependency.Dependency) ([]dependency.Dependency, error) {
-	input = strings.ToLower(strings.TrimSpace(input))
-
-	if input == "all" {
-		return deps, nil
-	}
-
-	var selected []dependency.Dependency
-	parts := strings.Split(input, ",")
-
-	for _, part := range parts {
-		part = strings.TrimSpace(part)
-
-		// Check if it's a range (e.g., "1-3")
-		if strings.Contains(part, "-") {
-			rangeDeps, err := p.parseRange(part, deps)
-			if err != nil {
-				return nil, err
-			}
-			selected = append(selected, rangeDeps...)
-			continue
-		}
-
-		// Check if it's a number
-		if num, err := strconv.Atoi(part); err == nil {
-			if num < 1 || num > len(deps) {
-				return nil, fmt.Errorf("number %d is out of range (1-%d)", num, len(deps))
-			}
-			dep := deps[num-1]
-			if !containsDependency(selected, dep) {
-				selected = append(selected, dep)
-			}
-			continue
-		}
-
-		// Check if it's a package name or pattern
-		matched := p.matchPattern(part, deps, &selected)
-		if !matched {
-			return nil, fmt.Errorf("no dependencies match pattern: %s", part)
-		}
-	}
-
-	return selected, nil
-}
-
-func (p *selectionParser) parseRange(part string, deps []dependency.Dependency) ([]dependency.Dependency, error) {
-	rangeParts := strings.Split(part, "-")
-	if len(rangeParts) != 2 {
-		return nil, fmt.Errorf("invalid range format: %s", part)
-	}
-
-	start, err := strconv.Atoi(strings.TrimSpace(rangeParts[0]))
+	q, err := parseQuery(input, len(deps))
 	if err != nil {
-		return nil, fmt.Errorf("invalid start number in range: %s", rangeParts[0])
-	}
-
-	end, err := strconv.Atoi(strings.TrimSpace(rangeParts[1]))
-	if err != nil {
-		return nil, fmt.Errorf("invalid end number in range: %s", rangeParts[1])
-	}
-
-	if start < 1 || end > len(deps) || start > end {
-		return nil, fmt.Errorf("range %d-%d is out of bounds (1-%d)", start, end, len(deps))
-	}
-
-	var rangeDeps []dependency.Dependency
-	for i := start - 1; i < end; i++ {
-		rangeDeps = append(rangeDeps, deps[i])
-	}
-
-	return rangeDeps, nil
-}
-
-func (p *selectionParser) matchPattern(pattern string, deps []dependency.Dependency, selected *[]dependency.Dependency) bool {
-	matched := false
-	for _, dep := range deps {
-		if matchesPattern(strings.ToLower(dep.Path), pattern) {
-			if !containsDependency(*selected, dep) {
-				*selected = append(*selected, dep)
-				matched = true
-			}
-		}
-	}
-	return matched
-}
-
-// matchesPattern checks if a path matches a pattern with * wildcards
-func matchesPattern(path, pattern string) bool {
-	// Simple pattern matching with * wildcard
-	if !strings.Contains(pattern, "*") {
-		return strings.Contains(path, pattern)
-	}
-
-	// Split pattern by * and check if all parts are present in order
-	parts := strings.Split(pattern, "*")
-	index := 0
-
-	for _, part := range parts {
-		if part == "" {
-			continue
-		}
-		newIndex := strings.Index(path[index:], part)
-		if newIndex == -1 {
-			return false
-		}
-		index += newIndex + len(part)
-	}
-
-	return true
-}
-
-// containsDependency checks if a dependency is already in the slice
-func containsDependency(slice []dependency.Dependency, dep dependency.Dependency) bool {
-	for _, item := range slice {
-		if item.Path == dep.Path {
-			return true
-		}
+		return nil, err
 	}
-	return false
+	return q.evaluate(deps)
 }
@@ -0,0 +1,465 @@
+package selector
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+
+	"goup/internal/dependency"
+	"goup/internal/pattern"
+)
+
+// Escape sequences of the terminal selector. Only attributes are used, not
+// colors, so the list stays readable on every terminal theme.
+const (
+	enterScreen = "\033[?1049h\033[?25l" // Alternate screen, hidden cursor
+	leaveScreen = "\033[?25h\033[?1049l"
+	home        = "\033[H"
+	clearLine   = "\033[K"
+	clearBelow  = "\033[J"
+	reset       = "\033[0m"
+	bold        = "\033[1m"
+	dim         = "\033[2m"
+	reverse     = "\033[7m"
+)
+
+// Tabs of the terminal selector
+const (
+	tabDirect = iota
+	tabIndirect
+)
+
+// terminalSelector implements the Selector interface with a full-screen list
+// drawn on a raw terminal
+type terminalSelector struct {
+	ui  UIInterface
+	in  *os.File
+	out *os.File
+}
+
+// NewTerminalSelector creates a selector showing the dependencies in a
+// full-screen list with checkboxes, or the line-based interactive selector
+// when stdin or stdout is not a terminal
+func NewTerminalSelector(ui UIInterface) Selector {
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return NewInteractiveSelector(ui)
+	}
+	return &terminalSelector{ui: ui, in: os.Stdin, out: os.Stdout}
+}
+
+// Select allows the user to choose which dependencies to update
+func (s *terminalSelector) Select(deps []dependency.Dependency, includeIndirect bool) SelectionResult {
+	if len(deps) == 0 {
+		return SelectionResult{Selected: []dependency.Dependency{}}
+	}
+
+	// Riskiest updates first when their impact was analyzed
+	deps = append([]dependency.Dependency(nil), deps...)
+	dependency.SortByImpact(deps)
+
+	state, err := term.MakeRaw(int(s.in.Fd()))
+	if err != nil {
+		return SelectionResult{Error: fmt.Errorf("setting up the terminal: %w", err)}
+	}
+
+	model := newListModel(deps, includeIndirect)
+	fmt.Fprint(s.out, enterScreen)
+	err = runList(model, s.in, s.out, s.size)
+	fmt.Fprint(s.out, leaveScreen)
+	if restoreErr := term.Restore(int(s.in.Fd()), state); err == nil && restoreErr != nil {
+		err = fmt.Errorf("restoring the terminal: %w", restoreErr)
+	}
+
+	if err != nil {
+		return SelectionResult{Error: err}
+	}
+	if model.cancelled {
+		s.ui.Info("Selection cancelled by user")
+		return SelectionResult{Cancelled: true}
+	}
+
+	selected := model.selected()
+	s.ui.Success("Selected %d dependencies:", len(selected))
+	s.ui.PrintDependencies(selected, "")
+	return SelectionResult{Selected: selected}
+}
+
+// size returns the width and height of the terminal, read on every frame so
+// the list follows resizes. Terminals not reporting it are taken as 80x24.
+func (s *terminalSelector) size() (int, int) {
+	width, height, err := term.GetSize(int(s.out.Fd()))
+	if err != nil || width <= 0 || height <= 0 {
+		return 80, 24
+	}
+	return width, height
+}
+
+// runList draws the list and applies the keys read from in until the
+// selection is confirmed or cancelled
+func runList(model *listModel, in io.Reader, out io.Writer, size func() (int, int)) error {
+	buf := make([]byte, 256)
+	for !model.done && !model.cancelled {
+		width, height := size()
+		fmt.Fprint(out, home+strings.Join(model.view(width, height), clearLine+"\r\n")+clearLine+clearBelow)
+
+		n, err := in.Read(buf)
+		if err != nil {
+			return fmt.Errorf("reading input: %w", err)
+		}
+		for _, k := range decodeKeys(buf[:n]) {
+			model.handle(k)
+			if model.done || model.cancelled {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// listModel is the state of the terminal selector, updated by each key
+type listModel struct {
+	deps      []dependency.Dependency
+	checked   []bool // Whether each dependency is selected, by index in deps
+	tabs      bool   // Whether indirect dependencies have their own tab
+	tab       int
+	filter    string
+	filtering bool   // Whether the keys typed edit the filter
+	cursor    int    // Highlighted row among the visible ones
+	offset    int    // First row shown when the list is taller than the screen
+	message   string // Shown instead of the key help until the next key
+	done      bool
+	cancelled bool
+}
+
+func newListModel(deps []dependency.Dependency, includeIndirect bool) *listModel {
+	m := &listModel{deps: deps, checked: make([]bool, len(deps)), tabs: includeIndirect}
+	if m.tabs && m.count(tabDirect) == 0 {
+		m.tab = tabIndirect
+	}
+	return m
+}
+
+// count returns the number of dependencies of a tab
+func (m *listModel) count(tab int) int {
+	count := 0
+	for _, dep := range m.deps {
+		if !m.tabs || dep.Indirect == (tab == tabIndirect) {
+			count++
+		}
+	}
+	return count
+}
+
+// visible returns the indexes in deps of the dependencies of the current tab
+// whose path matches the filter
+func (m *listModel) visible() []int {
+	var rows []int
+	for i, dep := range m.deps {
+		if m.tabs && dep.Indirect != (m.tab == tabIndirect) {
+			continue
+		}
+		if m.filter != "" && !pattern.Select(dep.Path, m.filter) {
+			continue
+		}
+		rows = append(rows, i)
+	}
+	return rows
+}
+
+// current returns the index in deps of the highlighted dependency, or -1 if
+// no dependency is visible
+func (m *listModel) current() int {
+	rows := m.visible()
+	if len(rows) == 0 {
+		return -1
+	}
+	return rows[m.cursor]
+}
+
+// selected returns the checked dependencies in the order of the list
+func (m *listModel) selected() []dependency.Dependency {
+	var selected []dependency.Dependency
+	for i, dep := range m.deps {
+		if m.checked[i] {
+			selected = append(selected, dep)
+		}
+	}
+	return selected
+}
+
+func (m *listModel) handle(k key) {
+	m.message = ""
+	if k.code == keyCtrlC {
+		m.cancelled = true
+		return
+	}
+
+	if m.filtering {
+		m.handleFilter(k)
+	} else {
+		m.handleList(k)
+	}
+
+	// Keep the cursor on a visible row
+	rows := len(m.visible())
+	if m.cursor >= rows {
+		m.cursor = rows - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+// handleFilter edits the filter typed after '/'
+func (m *listModel) handleFilter(k key) {
+	switch k.code {
+	case keyRune:
+		m.filter += string(k.r)
+		m.cursor = 0
+	case keyBackspace:
+		if r := []rune(m.filter); len(r) > 0 {
+			m.filter = string(r[:len(r)-1])
+		}
+	case keyEnter:
+		m.filtering = false
+	case keyEscape:
+		m.filtering = false
+		m.filter = ""
+	case keyUp:
+		m.cursor--
+	case keyDown:
+		m.cursor++
+	}
+}
+
+func (m *listModel) handleList(k key) {
+	switch {
+	case k.code == keyUp || k.is('k'):
+		m.cursor--
+	case k.code == keyDown || k.is('j'):
+		m.cursor++
+	case k.is(' '):
+		if i := m.current(); i >= 0 {
+			m.checked[i] = !m.checked[i]
+		}
+	case k.is('a'):
+		m.toggleVisible()
+	case k.is('/'):
+		m.filtering = true
+	case k.code == keyTab || k.code == keyLeft || k.code == keyRight:
+		if m.tabs {
+			m.tab = 1 - m.tab
+			m.cursor = 0
+			m.offset = 0
+		}
+	case k.code == keyEnter:
+		if len(m.selected()) == 0 {
+			m.message = "Press space to select dependencies, or q to cancel"
+			return
+		}
+		m.done = true
+	case k.code == keyEscape && m.filter != "":
+		m.filter = ""
+	case k.code == keyEscape || k.is('q'):
+		m.cancelled = true
+	}
+}
+
+// toggleVisible selects every visible dependency, or clears them all if they
+// are already selected
+func (m *listModel) toggleVisible() {
+	rows := m.visible()
+	all := true
+	for _, i := range rows {
+		all = all && m.checked[i]
+	}
+	for _, i := range rows {
+		m.checked[i] = !all
+	}
+}
+
+// view returns the lines of the screen: the tabs, the list next to the
+// details of the highlighted dependency, and the key help
+func (m *listModel) view(width, height int) []string {
+	lines := []string{bold + fit("goup - select the dependencies to update", width) + reset, m.tabLine(width)}
+
+	if m.filtering || m.filter != "" {
+		cursor := ""
+		if m.filtering {
+			cursor = "_"
+		}
+		lines = append(lines, fit("Filter: "+m.filter+cursor, width))
+	} else {
+		lines = append(lines, "")
+	}
+
+	// Header, tabs and filter above, key help below
+	body := height - 4
+	if body < 1 {
+		body = 1
+	}
+	listWidth := width * 3 / 5
+	if width < 60 {
+		// No room for the details
+		listWidth = width
+	}
+
+	rows := m.visible()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+body {
+		m.offset = m.cursor - body + 1
+	}
+
+	var details []string
+	if i := m.current(); i >= 0 {
+		details = detailLines(m.deps[i])
+	}
+
+	for line := 0; line < body; line++ {
+		left := fit("", listWidth)
+		row := m.offset + line
+		switch {
+		case row < len(rows):
+			left = m.rowLine(rows[row], row == m.cursor, listWidth)
+		case line == 0 && len(rows) == 0:
+			left = dim + fit("  No dependencies match", listWidth) + reset
+		}
+
+		if listWidth < width {
+			detail := ""
+			if line < len(details) {
+				detail = details[line]
+			}
+			left += " │ " + fit(detail, width-listWidth-3)
+		}
+		lines = append(lines, left)
+	}
+
+	help := "↑/↓ move  space select  a all  / filter  enter update  q cancel"
+	if m.tabs {
+		help = "↑/↓ move  ←/→ tab  space select  a all  / filter  enter update  q cancel"
+	}
+	if m.filtering {
+		help = "type to filter by path  enter keep  esc clear"
+	}
+	if m.message != "" {
+		return append(lines, bold+fit(m.message, width)+reset)
+	}
+	return append(lines, dim+fit(help, width)+reset)
+}
+
+// tabLine shows the tabs and the number of selected dependencies
+func (m *listModel) tabLine(width int) string {
+	status := fmt.Sprintf("  %d selected", len(m.selected()))
+	if !m.tabs {
+		return fit(fmt.Sprintf("Dependencies (%d)", len(m.deps))+status, width)
+	}
+
+	names := []string{fmt.Sprintf(" Direct (%d) ", m.count(tabDirect)), fmt.Sprintf(" Indirect (%d) ", m.count(tabIndirect))}
+	plain := names[0] + " " + names[1] + status
+	if len([]rune(plain)) > width {
+		return fit(plain, width)
+	}
+
+	names[m.tab] = reverse + names[m.tab] + reset
+	return names[0] + " " + names[1] + fit(status, width-len([]rune(plain))+len([]rune(status)))
+}
+
+// rowLine shows a dependency with its checkbox, highlighted under the cursor
+func (m *listModel) rowLine(index int, highlighted bool, width int) string {
+	dep := m.deps[index]
+	box := "[ ]"
+	if m.checked[index] {
+		box = "[x]"
+	}
+	line := fit(fmt.Sprintf("%s %s  %s", box, dep.TargetPath(), dep.VersionInfo()), width-2)
+	if highlighted {
+		return reverse + "> " + line + reset
+	}
+	return "  " + line
+}
+
+// detailLines describes a dependency in the side pane
+func detailLines(dep dependency.Dependency) []string {
+	lines := []string{dep.Path, ""}
+	add := func(label, value string) {
+		lines = append(lines, fmt.Sprintf("%-11s %s", label+":", value))
+	}
+
+	add("Current", dep.Version)
+	add("Available", dep.NewVersion)
+	if dep.IsMajorUpgrade() {
+		add("Module", dep.NewPath)
+	}
+	if dep.Indirect {
+		add("Type", "indirect")
+	} else {
+		add("Type", "direct")
+	}
+	if dep.Replace != nil {
+		replace := dep.Replace.String()
+		if dep.DropReplace {
+			replace += " (stale)"
+		}
+		add("Replaced", replace)
+	}
+	if len(dep.Modules) > 0 {
+		add("Workspace", strings.Join(dep.Modules, ", "))
+	}
+	if len(dep.Advisories) > 0 {
+		add("Advisories", strings.Join(dep.Advisories, ", "))
+	}
+	if dep.IsRetracted() {
+		add("Retracted", strings.Join(dep.Retracted, "; "))
+	}
+	if dep.Deprecated != "" {
+		add("Deprecated", dep.Deprecated)
+	}
+	if len(dep.Breaking) > 0 {
+		add("Breaking", fmt.Sprintf("%d API changes", len(dep.Breaking)))
+		for _, change := range dep.Breaking {
+			lines = append(lines, "  "+change)
+		}
+	}
+	if dep.Impact != nil {
+		add("Used by", fmt.Sprintf("%d packages, %d call sites", dep.Impact.Packages(), dep.Impact.CallSites))
+	}
+	if len(dep.Chain) > 0 {
+		lines = append(lines, "Required via:")
+		for _, link := range dep.Chain {
+			lines = append(lines, "  "+link)
+		}
+	}
+	for _, pull := range dep.PulledBy {
+		add("Raised by", fmt.Sprintf("%s@%s (requires %s)", pull.Path, pull.Version, pull.Requires))
+	}
+	if dep.Changelog != "" {
+		lines = append(lines, "Changelog:")
+		for _, line := range strings.Split(dep.Changelog, "\n") {
+			if strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++") {
+				lines = append(lines, "  "+strings.TrimPrefix(line, "+"))
+			}
+		}
+	}
+	return lines
+}
+
+// fit pads or truncates s to width runes
+func fit(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	r := []rune(s)
+	if len(r) > width {
+		if width == 1 {
+			return "…"
+		}
+		return string(r[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-len(r))
+}
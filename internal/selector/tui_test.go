@@ -0,0 +1,232 @@
+package selector
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"goup/internal/dependency"
+)
+
+var testDeps = []dependency.Dependency{
+	{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+	{Path: "github.com/spf13/cobra", Version: "v1.7.0", NewVersion: "v1.8.0", HasUpdate: true},
+	{Path: "golang.org/x/net", Version: "v0.10.0", NewVersion: "v0.17.0", HasUpdate: true, Indirect: true},
+	{Path: "golang.org/x/text", Version: "v0.9.0", NewVersion: "v0.14.0", HasUpdate: true, Indirect: true},
+}
+
+// press applies the keys decoded from the given input
+func press(m *listModel, input string) {
+	for _, k := range decodeKeys([]byte(input)) {
+		m.handle(k)
+	}
+}
+
+func paths(deps []dependency.Dependency) []string {
+	var result []string
+	for _, dep := range deps {
+		result = append(result, dep.Path)
+	}
+	return result
+}
+
+func TestListModelToggle(t *testing.T) {
+	m := newListModel(testDeps, false)
+
+	press(m, " \x1b[B\x1b[B ")
+
+	assert.Equal(t, []string{"github.com/gin-gonic/gin", "golang.org/x/net"}, paths(m.selected()))
+
+	// Space again clears the row, the cursor stays on the last row
+	press(m, "\x1b[B\x1b[B ")
+	assert.Equal(t, 3, m.cursor)
+	assert.Equal(t, []string{"github.com/gin-gonic/gin", "golang.org/x/net", "golang.org/x/text"}, paths(m.selected()))
+}
+
+func TestListModelTabs(t *testing.T) {
+	m := newListModel(testDeps, true)
+
+	assert.Equal(t, []int{0, 1}, m.visible())
+
+	press(m, "j\t ")
+	assert.Equal(t, tabIndirect, m.tab)
+	assert.Equal(t, []int{2, 3}, m.visible())
+	assert.Equal(t, []string{"golang.org/x/net"}, paths(m.selected()))
+
+	press(m, "\x1b[D")
+	assert.Equal(t, tabDirect, m.tab)
+	assert.Equal(t, 0, m.cursor)
+}
+
+func TestListModelStartsOnIndirectTab(t *testing.T) {
+	m := newListModel(testDeps[2:], true)
+
+	assert.Equal(t, tabIndirect, m.tab)
+}
+
+func TestListModelFilter(t *testing.T) {
+	m := newListModel(testDeps, false)
+
+	press(m, "/x/t")
+	assert.True(t, m.filtering)
+	assert.Equal(t, []int{3}, m.visible())
+
+	// Backspace widens the filter, Enter keeps it
+	press(m, "\x7f\x7f\r")
+	assert.False(t, m.filtering)
+	assert.Equal(t, "x", m.filter)
+	assert.Equal(t, []int{2, 3}, m.visible())
+
+	// Keys select again once the filter is kept
+	press(m, "a")
+	assert.Equal(t, []string{"golang.org/x/net", "golang.org/x/text"}, paths(m.selected()))
+
+	// Escape clears the filter before cancelling
+	press(m, "\x1b")
+	assert.Empty(t, m.filter)
+	assert.False(t, m.cancelled)
+	assert.Len(t, m.visible(), 4)
+}
+
+func TestListModelFilterPattern(t *testing.T) {
+	m := newListModel(testDeps, false)
+
+	press(m, "/GITHUB.COM/*/C*")
+	assert.Equal(t, []int{1}, m.visible())
+
+	// Like in a selection, a glob matches the whole path
+	press(m, "\x7f")
+	assert.Empty(t, m.visible())
+}
+
+func TestListModelSelectAll(t *testing.T) {
+	m := newListModel(testDeps, false)
+
+	press(m, "a")
+	assert.Len(t, m.selected(), 4)
+
+	press(m, "a")
+	assert.Empty(t, m.selected())
+}
+
+func TestListModelConfirm(t *testing.T) {
+	m := newListModel(testDeps, false)
+
+	press(m, "\r")
+	assert.False(t, m.done)
+	assert.Contains(t, m.message, "Press space")
+
+	press(m, " \r")
+	assert.True(t, m.done)
+	assert.Empty(t, m.message)
+}
+
+func TestListModelCancel(t *testing.T) {
+	for _, input := range []string{"q", "\x1b", "\x03", "/gin\x03"} {
+		m := newListModel(testDeps, false)
+
+		press(m, input)
+
+		assert.True(t, m.cancelled, "%q", input)
+	}
+}
+
+func TestListModelView(t *testing.T) {
+	m := newListModel(testDeps, true)
+	press(m, " ")
+
+	lines := m.view(100, 10)
+
+	require.Len(t, lines, 10)
+	assert.Contains(t, lines[1], " Direct (2) ")
+	assert.Contains(t, lines[1], "1 selected")
+	assert.Contains(t, lines[3], "> [x] github.com/gin-gonic/gin  v1.9.1 → v1.9.2")
+	assert.Contains(t, lines[3], "│ github.com/gin-gonic/gin")
+	assert.Contains(t, lines[4], "  [ ] github.com/spf13/cobra  v1.7.0 → v1.8.0")
+	assert.Contains(t, lines[6], "Available:  v1.9.2")
+	assert.Contains(t, lines[9], "space select")
+}
+
+func TestListModelViewScrolls(t *testing.T) {
+	m := newListModel(testDeps, false)
+	press(m, "jjj")
+
+	lines := m.view(40, 6)
+
+	// Two rows fit between the header and the key help
+	require.Len(t, lines, 6)
+	assert.Contains(t, lines[3], "golang.org/x/net")
+	assert.Contains(t, lines[4], "> [ ] golang.org/x/text")
+	assert.Equal(t, 2, m.offset)
+}
+
+func TestDetailLines(t *testing.T) {
+	dep := dependency.Dependency{
+		Path: "golang.org/x/net", Version: "v0.10.0", NewVersion: "v0.17.0", Indirect: true,
+		Advisories: []string{"GO-2023-2102"},
+		Chain:      []string{"example.com/app", "github.com/gin-gonic/gin@v1.9.1", "golang.org/x/net@v0.10.0"},
+		PulledBy:   []dependency.Pull{{Path: "github.com/gin-gonic/gin", Version: "v1.9.2", Requires: "v0.17.0"}},
+		Changelog:  "--- a/CHANGELOG.md\n+++ b/CHANGELOG.md\n@@ -1 +1,2 @@\n+Fix HTTP/2 rapid reset\n # Changelog",
+	}
+
+	assert.Equal(t, []string{
+		"golang.org/x/net",
+		"",
+		"Current:    v0.10.0",
+		"Available:  v0.17.0",
+		"Type:       indirect",
+		"Advisories: GO-2023-2102",
+		"Required via:",
+		"  example.com/app",
+		"  github.com/gin-gonic/gin@v1.9.1",
+		"  golang.org/x/net@v0.10.0",
+		"Raised by:  github.com/gin-gonic/gin@v1.9.2 (requires v0.17.0)",
+		"Changelog:",
+		"  Fix HTTP/2 rapid reset",
+	}, detailLines(dep))
+}
+
+func TestDecodeKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []key
+	}{
+		{name: "arrows", input: "\x1b[A\x1b[B\x1bOC\x1b[D", want: []key{{code: keyUp}, {code: keyDown}, {code: keyRight}, {code: keyLeft}}},
+		{name: "characters", input: "aé ", want: []key{{code: keyRune, r: 'a'}, {code: keyRune, r: 'é'}, {code: keyRune, r: ' '}}},
+		{name: "control keys", input: "\r\t\x7f\x03", want: []key{{code: keyEnter}, {code: keyTab}, {code: keyBackspace}, {code: keyCtrlC}}},
+		{name: "lone escape", input: "\x1b", want: []key{{code: keyEscape}}},
+		{name: "shift tab", input: "\x1b[Z", want: []key{{code: keyTab}}},
+		{name: "unknown sequence is skipped", input: "\x1b[5~j", want: []key{{code: keyRune, r: 'j'}}},
+		{name: "other control characters are ignored", input: "\x01\x1a", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, decodeKeys([]byte(tt.input)))
+		})
+	}
+}
+
+func TestRunList(t *testing.T) {
+	m := newListModel(testDeps, false)
+	var out bytes.Buffer
+
+	err := runList(m, strings.NewReader(" j \r"), &out, func() (int, int) { return 80, 12 })
+
+	require.NoError(t, err)
+	assert.True(t, m.done)
+	assert.Equal(t, []string{"github.com/gin-gonic/gin", "github.com/spf13/cobra"}, paths(m.selected()))
+	assert.True(t, strings.HasPrefix(out.String(), home))
+}
+
+func TestRunListInputClosed(t *testing.T) {
+	m := newListModel(testDeps, false)
+
+	err := runList(m, strings.NewReader(""), &bytes.Buffer{}, func() (int, int) { return 80, 12 })
+
+	assert.ErrorContains(t, err, "reading input")
+}
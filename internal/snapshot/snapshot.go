@@ -0,0 +1,206 @@
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ModuleFiles are the files captured by every snapshot
+var ModuleFiles = []string{"go.mod", "go.sum"}
+
+// ErrNoSnapshot is returned when there is no saved snapshot for a module
+var ErrNoSnapshot = errors.New("no snapshot found")
+
+// Snapshot holds the contents of a module's files before goup changed them
+type Snapshot struct {
+	Dir       string          `json:"dir"`
+	CreatedAt time.Time       `json:"created_at"`
+	Files     map[string]File `json:"files"` // Keyed by path relative to Dir
+}
+
+// File is the saved state of a single file
+type File struct {
+	Exists  bool        `json:"exists"`
+	Content []byte      `json:"content,omitempty"`
+	Mode    fs.FileMode `json:"mode,omitempty"`
+}
+
+// Take captures go.mod and go.sum of the module in dir
+func Take(dir string) (*Snapshot, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path '%s': %w", dir, err)
+	}
+
+	s := &Snapshot{
+		Dir:       absDir,
+		CreatedAt: time.Now(),
+		Files:     make(map[string]File),
+	}
+
+	for _, name := range ModuleFiles {
+		if err := s.Add(filepath.Join(absDir, name)); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// Add captures another file of the module, such as a source file about to be
+// rewritten. Relative paths are relative to the current directory. Files
+// already in the snapshot keep their original contents.
+func (s *Snapshot) Add(path string) error {
+	rel, err := s.relative(path)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := s.Files[rel]; ok {
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		s.Files[rel] = File{Exists: false}
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("reading %s: %w", rel, err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", rel, err)
+	}
+
+	s.Files[rel] = File{Exists: true, Content: content, Mode: info.Mode().Perm()}
+	return nil
+}
+
+// Restore writes every captured file back, removing files that did not exist
+// when the snapshot was taken
+func (s *Snapshot) Restore() error {
+	var errs []error
+
+	for rel, file := range s.Files {
+		path := filepath.Join(s.Dir, rel)
+
+		if !file.Exists {
+			if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				errs = append(errs, fmt.Errorf("removing %s: %w", rel, err))
+			}
+			continue
+		}
+
+		mode := file.Mode
+		if mode == 0 {
+			mode = 0644
+		}
+		if err := os.WriteFile(path, file.Content, mode); err != nil {
+			errs = append(errs, fmt.Errorf("restoring %s: %w", rel, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *Snapshot) relative(path string) (string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path '%s': %w", path, err)
+	}
+
+	rel, err := filepath.Rel(s.Dir, path)
+	if err != nil {
+		return "", fmt.Errorf("%s is outside the module: %w", path, err)
+	}
+
+	return filepath.ToSlash(rel), nil
+}
+
+// Store persists the snapshot of the last run of each module
+type Store interface {
+	// Save replaces the saved snapshot of the snapshot's module
+	Save(s *Snapshot) error
+	// Load returns the saved snapshot of the module in dir
+	Load(dir string) (*Snapshot, error)
+}
+
+// fileStore implements Store with one JSON file per module
+type fileStore struct {
+	root string
+}
+
+// NewStore creates a store in the user cache directory, so snapshots never
+// show up in the project's working tree
+func NewStore() Store {
+	root, err := os.UserCacheDir()
+	if err != nil {
+		root = os.TempDir()
+	}
+	return NewStoreWithRoot(filepath.Join(root, "goup", "snapshots"))
+}
+
+// NewStoreWithRoot creates a store that keeps snapshots in root
+func NewStoreWithRoot(root string) Store {
+	return &fileStore{root: root}
+}
+
+// Save writes the snapshot, replacing any previous one of the same module
+func (f *fileStore) Save(s *Snapshot) error {
+	if err := os.MkdirAll(f.root, 0755); err != nil {
+		return fmt.Errorf("creating snapshot directory: %w", err)
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("encoding snapshot: %w", err)
+	}
+
+	// Write to a temporary file first so a crash never leaves a truncated snapshot
+	path := f.path(s.Dir)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("saving snapshot: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("saving snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// Load reads the saved snapshot of the module in dir
+func (f *fileStore) Load(dir string) (*Snapshot, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path '%s': %w", dir, err)
+	}
+
+	data, err := os.ReadFile(f.path(absDir))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w for %s", ErrNoSnapshot, absDir)
+	} else if err != nil {
+		return nil, fmt.Errorf("reading snapshot: %w", err)
+	}
+
+	s := &Snapshot{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("decoding snapshot: %w", err)
+	}
+
+	return s, nil
+}
+
+// path returns the snapshot file of a module, named after a hash of its directory
+func (f *fileStore) path(absDir string) string {
+	sum := sha256.Sum256([]byte(absDir))
+	return filepath.Join(f.root, hex.EncodeToString(sum[:8])+".json")
+}
@@ -0,0 +1,83 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestTakeAndRestore(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example\n\nrequire github.com/foo/bar v1.0.0\n")
+
+	s, err := Take(dir)
+	require.NoError(t, err)
+	assert.True(t, s.Files["go.mod"].Exists)
+	assert.False(t, s.Files["go.sum"].Exists)
+
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example\n\nrequire github.com/foo/bar v1.2.0\n")
+	writeFile(t, filepath.Join(dir, "go.sum"), "github.com/foo/bar v1.2.0 h1:abc\n")
+
+	require.NoError(t, s.Restore())
+
+	assert.Equal(t, "module example\n\nrequire github.com/foo/bar v1.0.0\n", readFile(t, filepath.Join(dir, "go.mod")))
+	assert.NoFileExists(t, filepath.Join(dir, "go.sum"), "Files created after the snapshot are removed")
+}
+
+func TestAddKeepsOriginalContents(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "cmd", "main.go")
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example\n")
+	writeFile(t, source, "package main // v1\n")
+
+	s, err := Take(dir)
+	require.NoError(t, err)
+	require.NoError(t, s.Add(source))
+
+	writeFile(t, source, "package main // v2\n")
+	require.NoError(t, s.Add(source))
+	assert.Contains(t, s.Files, "cmd/main.go")
+
+	require.NoError(t, s.Restore())
+	assert.Equal(t, "package main // v1\n", readFile(t, source))
+}
+
+func TestStoreSaveAndLoad(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example\n")
+	writeFile(t, filepath.Join(dir, "go.sum"), "")
+
+	store := NewStoreWithRoot(t.TempDir())
+
+	_, err := store.Load(dir)
+	assert.ErrorIs(t, err, ErrNoSnapshot)
+
+	s, err := Take(dir)
+	require.NoError(t, err)
+	require.NoError(t, store.Save(s))
+
+	loaded, err := store.Load(dir)
+	require.NoError(t, err)
+	assert.Equal(t, s.Dir, loaded.Dir)
+	assert.Equal(t, []byte("module example\n"), loaded.Files["go.mod"].Content)
+	assert.True(t, loaded.Files["go.sum"].Exists, "An empty go.sum still exists")
+
+	_, err = store.Load(t.TempDir())
+	assert.ErrorIs(t, err, ErrNoSnapshot, "Snapshots are kept per module")
+}
@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"goup/internal/apidiff"
+	"goup/internal/dependency"
+)
+
+// PrintAPIChanges prints the exported API changes of an update, grouped by package
+func (c *console) PrintAPIChanges(dep dependency.Dependency, changes []apidiff.Change) {
+	title := fmt.Sprintf("API changes of %s (%s): %s", dep.TargetPath(), dep.VersionInfo(), apiSummary(changes))
+	if c.noColor {
+		fmt.Println(title)
+	} else {
+		fmt.Printf(" 🔬 %s%s%s\n", Accent, title, Reset)
+	}
+	fmt.Println()
+
+	pkg := ""
+	for _, change := range changes {
+		if change.Package != pkg {
+			pkg = change.Package
+			if c.noColor {
+				fmt.Printf("%s\n", pkg)
+			} else {
+				fmt.Printf(" %s %s%s%s\n", SymbolPackage, Primary, pkg, Reset)
+			}
+		}
+
+		for _, line := range apiChangeLines(change) {
+			if c.noColor {
+				fmt.Printf("  %s\n", line)
+				continue
+			}
+			fmt.Printf("  %s%s%s\n", apiChangeColor(change), line, Reset)
+		}
+	}
+	if len(changes) > 0 {
+		fmt.Println()
+	}
+}
+
+// apiSummary counts the changes of each kind
+func apiSummary(changes []apidiff.Change) string {
+	if len(changes) == 0 {
+		return "no exported API changes"
+	}
+
+	counts := make(map[apidiff.Kind]int)
+	for _, change := range changes {
+		counts[change.Kind]++
+	}
+
+	var parts []string
+	for _, kind := range []apidiff.Kind{apidiff.Added, apidiff.Removed, apidiff.Changed} {
+		if counts[kind] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[kind], kind))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// apiChangeLines formats a change as diff lines, the old and the new
+// declaration for a changed identifier
+func apiChangeLines(change apidiff.Change) []string {
+	switch change.Kind {
+	case apidiff.Added:
+		return []string{"+ " + change.New}
+	case apidiff.Removed:
+		return []string{"- " + change.Old}
+	default:
+		return []string{"~ " + change.Old, "  → " + change.New}
+	}
+}
+
+func apiChangeColor(change apidiff.Change) string {
+	switch {
+	case change.Kind == apidiff.Added && !change.Breaking():
+		return Green
+	case change.Kind != apidiff.Changed:
+		return Red
+	default:
+		return Yellow
+	}
+}
+
+// breakingLabel is the API column of the dependency table
+func breakingLabel(dep dependency.Dependency) string {
+	if len(dep.Breaking) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d breaking", len(dep.Breaking))
+}
@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"goup/internal/apidiff"
+	"goup/internal/dependency"
+)
+
+func TestAPISummary(t *testing.T) {
+	changes := []apidiff.Change{
+		{Kind: apidiff.Removed},
+		{Kind: apidiff.Added},
+		{Kind: apidiff.Removed},
+	}
+
+	assert.Equal(t, "1 added, 2 removed", apiSummary(changes))
+	assert.Equal(t, "no exported API changes", apiSummary(nil))
+}
+
+func TestAPIChangeLines(t *testing.T) {
+	assert.Equal(t, []string{"+ func Bar()"}, apiChangeLines(apidiff.Change{Kind: apidiff.Added, New: "func Bar()"}))
+	assert.Equal(t, []string{"- package v1api"}, apiChangeLines(apidiff.Change{Kind: apidiff.Removed, Old: "package v1api"}))
+	assert.Equal(t, []string{"~ func Foo()", "  → func Foo(int)"},
+		apiChangeLines(apidiff.Change{Kind: apidiff.Changed, Old: "func Foo()", New: "func Foo(int)"}))
+}
+
+func TestBreakingLabel(t *testing.T) {
+	assert.Empty(t, breakingLabel(dependency.Dependency{}))
+	assert.Equal(t, "2 breaking", breakingLabel(dependency.Dependency{Breaking: []string{"a.X", "a.Y"}}))
+}
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"goup/internal/dependency"
+)
+
+// maxChangelogLines limits how much of a changelog diff is printed per dependency
+const maxChangelogLines = 40
+
+// PrintChangelog prints the changelog diff of a dependency, if it was fetched
+func (c *console) PrintChangelog(dep dependency.Dependency) {
+	if dep.Changelog == "" {
+		return
+	}
+
+	lines, hidden := changelogLines(dep.Changelog, maxChangelogLines)
+
+	if c.noColor {
+		fmt.Printf("Changelog of %s (%s):\n", dep.TargetPath(), dep.VersionInfo())
+	} else {
+		fmt.Printf(" 📝 %sChangelog of %s%s %s(%s)%s\n", Accent, dep.TargetPath(), Reset, Secondary, dep.VersionInfo(), Reset)
+	}
+
+	for _, line := range lines {
+		if c.noColor {
+			fmt.Printf("  %s\n", line)
+			continue
+		}
+		fmt.Printf("  %s%s%s\n", changelogColor(line), line, Reset)
+	}
+
+	if hidden > 0 {
+		fmt.Printf("  … %d more lines, run with --format=json for the full diff\n", hidden)
+	}
+	fmt.Println()
+}
+
+// changelogLines splits a changelog diff into the lines to print, skipping
+// the file header, and returns how many lines over the limit were left out
+func changelogLines(changelog string, limit int) ([]string, int) {
+	lines := strings.Split(strings.TrimSuffix(changelog, "\n"), "\n")
+	for len(lines) > 0 && (strings.HasPrefix(lines[0], "--- ") || strings.HasPrefix(lines[0], "+++ ")) {
+		lines = lines[1:]
+	}
+
+	if len(lines) <= limit {
+		return lines, 0
+	}
+	return lines[:limit], len(lines) - limit
+}
+
+func changelogColor(line string) string {
+	switch {
+	case strings.HasPrefix(line, "+"):
+		return Green
+	case strings.HasPrefix(line, "-"):
+		return Red
+	case strings.HasPrefix(line, "@@"):
+		return Cyan
+	default:
+		return Secondary
+	}
+}
@@ -0,0 +1,19 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangelogLines(t *testing.T) {
+	changelog := "--- a@v1.0.0/CHANGELOG.md\n+++ a@v1.1.0/CHANGELOG.md\n@@ -1,2 +1,4 @@\n # Changelog\n+## v1.1.0\n+- Add Foo\n \n"
+
+	lines, hidden := changelogLines(changelog, 10)
+	assert.Equal(t, []string{"@@ -1,2 +1,4 @@", " # Changelog", "+## v1.1.0", "+- Add Foo", " "}, lines, "The file header is skipped")
+	assert.Zero(t, hidden)
+
+	lines, hidden = changelogLines(changelog, 3)
+	assert.Equal(t, []string{"@@ -1,2 +1,4 @@", " # Changelog", "+## v1.1.0"}, lines)
+	assert.Equal(t, 2, hidden)
+}
@@ -8,7 +8,6 @@ import (
 	"unicode/utf8"
 
 	"goup/internal/config"
-	"goup/internal/dependency"
 )
 
 // Modern ANSI color palette
@@ -224,201 +223,6 @@ func (c *console) Confirm(message string) bool {
 	return response == "y" || response == "yes"
 }
 
-func (c *console) PrintDependencies(deps []dependency.Dependency, title string) {
-	if title != "" {
-		c.Info("%s", title)
-	}
-	fmt.Println()
-
-	if len(deps) == 0 {
-		return
-	}
-
-	// Calculate optimal column widths
-	maxPathWidth := c.calculateMaxPathWidth(deps)
-	indexWidth := c.calculateIndexWidth(len(deps))
-	currentVersionWidth := 15 // Width for current version
-	newVersionWidth := 15     // Width for new version
-	typeWidth := 8            // Fixed width for type
-
-	if c.noColor {
-		c.printSimpleTable(deps, indexWidth, maxPathWidth, currentVersionWidth, newVersionWidth, typeWidth)
-		return
-	}
-
-	c.printStyledTable(deps, indexWidth, maxPathWidth, currentVersionWidth, newVersionWidth, typeWidth)
-}
-
-func (c *console) calculateMaxPathWidth(deps []dependency.Dependency) int {
-	maxWidth := 20 // minimum width
-	for _, dep := range deps {
-		if len(dep.Path) > maxWidth {
-			maxWidth = len(dep.Path)
-		}
-	}
-	// Cap the maximum width to keep table readable
-	if maxWidth > 50 {
-		maxWidth = 50
-	}
-	return maxWidth
-}
-
-func (c *console) calculateIndexWidth(total int) int {
-	totalStr := fmt.Sprintf("%d/%d", total, total)
-	return len(totalStr)
-}
-
-func (c *console) printSimpleTable(deps []dependency.Dependency, indexWidth, pathWidth, currentVersionWidth, newVersionWidth, typeWidth int) {
-	// Header with separate version columns
-	fmt.Printf(" %-*s │ %-*s │ %-*s │ %-*s │ %-*s\n",
-		indexWidth, "#",
-		pathWidth, "Package",
-		currentVersionWidth, "Current Version",
-		newVersionWidth, "New Version",
-		typeWidth, "Type")
-
-	// Separator
-	fmt.Printf("%s┼%s┼%s┼%s┼%s\n",
-		strings.Repeat("─", indexWidth+2),
-		strings.Repeat("─", pathWidth+2),
-		strings.Repeat("─", currentVersionWidth+2),
-		strings.Repeat("─", newVersionWidth+2),
-		strings.Repeat("─", typeWidth+2))
-
-	// Rows
-	for i, dep := range deps {
-		c.printSimpleDependencyRow(i+1, len(deps), dep, indexWidth, pathWidth, currentVersionWidth, newVersionWidth, typeWidth)
-	}
-
-	// Bottom border
-	fmt.Printf("%s┴%s┴%s┴%s┴%s\n",
-		strings.Repeat("─", indexWidth+2),
-		strings.Repeat("─", pathWidth+2),
-		strings.Repeat("─", currentVersionWidth+2),
-		strings.Repeat("─", newVersionWidth+2),
-		strings.Repeat("─", typeWidth+2))
-	fmt.Println()
-}
-
-func (c *console) printStyledTable(deps []dependency.Dependency, indexWidth, pathWidth, currentVersionWidth, newVersionWidth, typeWidth int) {
-	// Top border
-	fmt.Printf("   %s%s%s\n", Secondary,
-		TableTopLeft+strings.Repeat(TableHorizontal, indexWidth+2)+
-			TableTeeDown+strings.Repeat(TableHorizontal, pathWidth+2)+
-			TableTeeDown+strings.Repeat(TableHorizontal, currentVersionWidth+2)+
-			TableTeeDown+strings.Repeat(TableHorizontal, newVersionWidth+2)+
-			TableTeeDown+strings.Repeat(TableHorizontal, typeWidth+2)+
-			TableTopRight, Reset)
-
-	// Header with separate version columns
-	fmt.Printf("   %s%s%s %s%s%-*s%s %s%s%s %s%s%-*s%s %s%s%s %s%s%-*s%s %s%s%s %s%s%-*s%s %s%s%s %s%s%-*s%s %s%s%s\n",
-		Secondary, TableVertical, Reset,
-		Primary, Bold, indexWidth, "#", Reset,
-		Secondary, TableVertical, Reset,
-		Primary, Bold, pathWidth, "Package", Reset,
-		Secondary, TableVertical, Reset,
-		Primary, Bold, currentVersionWidth, "Current Version", Reset,
-		Secondary, TableVertical, Reset,
-		Primary, Bold, newVersionWidth, "New Version", Reset,
-		Secondary, TableVertical, Reset,
-		Primary, Bold, typeWidth, "Type", Reset,
-		Secondary, TableVertical, Reset)
-
-	// Header separator
-	fmt.Printf("   %s%s%s\n", Secondary,
-		TableTeeRight+strings.Repeat(TableHorizontal, indexWidth+2)+
-			TableCross+strings.Repeat(TableHorizontal, pathWidth+2)+
-			TableCross+strings.Repeat(TableHorizontal, currentVersionWidth+2)+
-			TableCross+strings.Repeat(TableHorizontal, newVersionWidth+2)+
-			TableCross+strings.Repeat(TableHorizontal, typeWidth+2)+
-			TableTeeLeft, Reset)
-
-	// Rows
-	for i, dep := range deps {
-		c.printStyledDependencyRow(i+1, len(deps), dep, indexWidth, pathWidth, currentVersionWidth, newVersionWidth, typeWidth)
-
-		// Row separator (except for last row)
-		if i < len(deps)-1 {
-			fmt.Printf("   %s%s%s\n", Secondary,
-				TableTeeRight+strings.Repeat(TableDotted, indexWidth+2)+
-					TableCross+strings.Repeat(TableDotted, pathWidth+2)+
-					TableCross+strings.Repeat(TableDotted, currentVersionWidth+2)+
-					TableCross+strings.Repeat(TableDotted, newVersionWidth+2)+
-					TableCross+strings.Repeat(TableDotted, typeWidth+2)+
-					TableTeeLeft, Reset)
-		}
-	}
-
-	// Bottom border
-	fmt.Printf("   %s%s%s\n", Secondary,
-		TableBottomLeft+strings.Repeat(TableHorizontal, indexWidth+2)+
-			TableTeeUp+strings.Repeat(TableHorizontal, pathWidth+2)+
-			TableTeeUp+strings.Repeat(TableHorizontal, currentVersionWidth+2)+
-			TableTeeUp+strings.Repeat(TableHorizontal, newVersionWidth+2)+
-			TableTeeUp+strings.Repeat(TableHorizontal, typeWidth+2)+
-			TableBottomRight, Reset)
-	fmt.Println()
-}
-
-func (c *console) printSimpleDependencyRow(index, total int, dep dependency.Dependency, indexWidth, pathWidth, currentVersionWidth, newVersionWidth, typeWidth int) {
-	indexStr := fmt.Sprintf("%d/%d", index, total)
-	pathStr := c.truncateString(dep.Path, pathWidth)
-	currentVersionStr := c.truncateString(dep.Version, currentVersionWidth)
-	newVersionStr := c.truncateString(dep.NewVersion, newVersionWidth)
-
-	typeStr := "direct"
-	if dep.Indirect {
-		typeStr = "indirect"
-	}
-
-	fmt.Printf(" %-*s │ %-*s │ %-*s │ %-*s │ %-*s\n",
-		indexWidth, indexStr,
-		pathWidth, pathStr,
-		currentVersionWidth, currentVersionStr,
-		newVersionWidth, newVersionStr,
-		typeWidth, typeStr)
-}
-
-func (c *console) printStyledDependencyRow(index, total int, dep dependency.Dependency, indexWidth, pathWidth, currentVersionWidth, newVersionWidth, typeWidth int) {
-	indexStr := fmt.Sprintf("%d/%d", index, total)
-	pathStr := c.truncateString(dep.Path, pathWidth)
-	currentVersionStr := c.truncateString(dep.Version, currentVersionWidth)
-	newVersionStr := c.truncateString(dep.NewVersion, newVersionWidth)
-
-	// Colors and symbols based on dependency type
-	var pathColor, typeColor, typeStr string
-	if dep.Indirect {
-		pathColor = Yellow
-		typeColor = Secondary
-		typeStr = "indirect"
-	} else {
-		pathColor = Green
-		typeColor = Primary
-		typeStr = "direct"
-	}
-
-	// Format each column separately
-	indexCol := fmt.Sprintf("%-*s", indexWidth, indexStr)
-	pathCol := fmt.Sprintf("%-*s", pathWidth, pathStr)
-	currentVersionCol := fmt.Sprintf("%-*s", currentVersionWidth, currentVersionStr)
-	newVersionCol := fmt.Sprintf("%-*s", newVersionWidth, newVersionStr)
-	typeCol := fmt.Sprintf("%-*s", typeWidth, typeStr)
-
-	// Print the formatted row with separate version columns
-	fmt.Printf("   %s%s%s %s%s%s %s%s%s %s%s%s %s%s%s %s%s%s %s%s%s %s%s%s %s%s%s %s%s%s %s%s%s\n",
-		Secondary, TableVertical, Reset,
-		Secondary, indexCol, Reset,
-		Secondary, TableVertical, Reset,
-		pathColor, pathCol, Reset,
-		Secondary, TableVertical, Reset,
-		Cyan, currentVersionCol, Reset,
-		Secondary, TableVertical, Reset,
-		Success, newVersionCol, Reset,
-		Secondary, TableVertical, Reset,
-		typeColor, typeCol, Reset,
-		Secondary, TableVertical, Reset)
-}
-
 func (c *console) PrintUpdateResult(updated, total int, hasErrors bool) {
 	if c.noColor {
 		if hasErrors {
@@ -437,6 +241,14 @@ func (c *console) PrintUpdateResult(updated, total int, hasErrors bool) {
 	}
 }
 
+// PrintReport prints the per-module summary of a recursive run. Everything
+// else in the report has already been printed while the run progressed.
+func (c *console) PrintReport(report Report) {
+	if len(report.Modules) > 0 {
+		c.printModuleSummary(report.Modules)
+	}
+}
+
 // Helper methods
 func (c *console) printMessage(symbol, label, color, message string) {
 	if c.noColor {
@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PrintModDiff prints the unified diff of go.mod and go.sum computed by a dry run
+func (c *console) PrintModDiff(modDiff string) {
+	if modDiff == "" {
+		c.Info("go.mod and go.sum would not change")
+		return
+	}
+
+	if c.noColor {
+		fmt.Println("Changes to go.mod and go.sum:")
+	} else {
+		fmt.Printf(" 📄 %sChanges to go.mod and go.sum%s\n", Accent, Reset)
+	}
+	fmt.Println()
+
+	for _, line := range strings.Split(strings.TrimSuffix(modDiff, "\n"), "\n") {
+		if c.noColor {
+			fmt.Println(line)
+			continue
+		}
+		fmt.Printf("%s%s%s\n", modDiffColor(line), line, Reset)
+	}
+	fmt.Println()
+}
+
+// modDiffColor colors the file headers apart from the removed and added lines
+func modDiffColor(line string) string {
+	if strings.HasPrefix(line, "--- ") || strings.HasPrefix(line, "+++ ") {
+		return Bold
+	}
+	return changelogColor(line)
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"fmt"
+
+	"goup/internal/dependency"
+)
+
+// maxImpactPackages limits how many importing packages are listed per dependency
+const maxImpactPackages = 10
+
+// PrintImpact prints the packages of the main module using a dependency, if
+// the impact was analyzed
+func (c *console) PrintImpact(dep dependency.Dependency) {
+	if dep.Impact == nil {
+		return
+	}
+
+	summary := impactSummary(dep.Impact)
+	if c.noColor {
+		fmt.Printf("Impact of %s: %s\n", dep.Path, summary)
+	} else {
+		fmt.Printf(" 🎯 %sImpact of %s%s %s(%s)%s\n", Accent, dep.Path, Reset, Secondary, summary, Reset)
+	}
+
+	lines, hidden := impactLines(dep.Impact, maxImpactPackages)
+	for _, line := range lines {
+		fmt.Printf("  %s\n", line)
+	}
+	if hidden > 0 {
+		fmt.Printf("  … %d more packages, run with --format=json for the full list\n", hidden)
+	}
+	fmt.Println()
+}
+
+// impactSummary describes how many packages depend on a dependency
+func impactSummary(impact *dependency.Impact) string {
+	if impact.Packages() == 0 {
+		return "not imported by any package"
+	}
+
+	summary := fmt.Sprintf("imported by %s", plural(len(impact.Importers), "package"))
+	if len(impact.Transitive) > 0 {
+		summary += fmt.Sprintf(", transitively by %s", plural(len(impact.Transitive), "more package"))
+	}
+	return summary + fmt.Sprintf(", %s", plural(impact.CallSites, "call site"))
+}
+
+// impactLines lists the importing packages, then the ones using the
+// dependency transitively, and returns how many were left out over the limit
+func impactLines(impact *dependency.Impact, limit int) ([]string, int) {
+	lines := append([]string(nil), impact.Importers...)
+	for _, pkg := range impact.Transitive {
+		lines = append(lines, pkg+" (transitively)")
+	}
+
+	if len(lines) <= limit {
+		return lines, 0
+	}
+	return lines[:limit], len(lines) - limit
+}
+
+// impactLabel returns the impact column of a dependency, empty if it was not
+// analyzed
+func impactLabel(dep dependency.Dependency) string {
+	switch {
+	case dep.Impact == nil:
+		return ""
+	case dep.Impact.Packages() == 0:
+		return "unused"
+	default:
+		return fmt.Sprintf("%s, %s", plural(dep.Impact.Packages(), "pkg"), plural(dep.Impact.CallSites, "call"))
+	}
+}
+
+func impactColor(dep dependency.Dependency) string {
+	if dep.Impact.Packages() == 0 {
+		return Secondary
+	}
+	return Blue
+}
+
+// plural formats a count with a singular or plural noun
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"goup/internal/dependency"
+)
+
+func TestImpactSummary(t *testing.T) {
+	assert.Equal(t, "not imported by any package", impactSummary(&dependency.Impact{}))
+	assert.Equal(t, "imported by 1 package, 1 call site",
+		impactSummary(&dependency.Impact{Importers: []string{"app"}, CallSites: 1}))
+	assert.Equal(t, "imported by 2 packages, transitively by 1 more package, 12 call sites",
+		impactSummary(&dependency.Impact{Importers: []string{"app", "app/api"}, Transitive: []string{"app/cmd"}, CallSites: 12}))
+}
+
+func TestImpactLines(t *testing.T) {
+	impact := &dependency.Impact{Importers: []string{"app", "app/api"}, Transitive: []string{"app/cmd"}}
+
+	lines, hidden := impactLines(impact, 10)
+	assert.Equal(t, []string{"app", "app/api", "app/cmd (transitively)"}, lines)
+	assert.Zero(t, hidden)
+
+	lines, hidden = impactLines(impact, 1)
+	assert.Equal(t, []string{"app"}, lines)
+	assert.Equal(t, 2, hidden)
+}
+
+func TestImpactLabel(t *testing.T) {
+	assert.Empty(t, impactLabel(dependency.Dependency{}), "Not analyzed")
+	assert.Equal(t, "unused", impactLabel(dependency.Dependency{Impact: &dependency.Impact{}}))
+	assert.Equal(t, "1 pkg, 1 call", impactLabel(dependency.Dependency{Impact: &dependency.Impact{Importers: []string{"app"}, CallSites: 1}}))
+	assert.Equal(t, "3 pkgs, 0 calls", impactLabel(dependency.Dependency{Impact: &dependency.Impact{Transitive: []string{"a", "b", "c"}}}))
+}
@@ -1,6 +1,9 @@
 package ui
 
-import "goup/internal/dependency"
+import (
+	"goup/internal/apidiff"
+	"goup/internal/dependency"
+)
 
 // Console defines the interface for console-based user interaction
 type Console interface {
@@ -37,6 +40,24 @@ type Console interface {
 	// PrintDependencies displays a numbered list of dependencies
 	PrintDependencies(deps []dependency.Dependency, title string)
 
+	// PrintChangelog displays the changelog diff of a dependency, if it has one
+	PrintChangelog(dep dependency.Dependency)
+
+	// PrintImpact displays the main module packages using a dependency, if it was analyzed
+	PrintImpact(dep dependency.Dependency)
+
+	// PrintRequirements displays why a dependency is required and which updates raise it
+	PrintRequirements(dep dependency.Dependency)
+
+	// PrintAPIChanges displays the exported API changes of a dependency update
+	PrintAPIChanges(dep dependency.Dependency, changes []apidiff.Change)
+
+	// PrintModDiff displays how a dry run would change go.mod and go.sum
+	PrintModDiff(modDiff string)
+
 	// PrintUpdateResult displays the result of an update operation
 	PrintUpdateResult(updated, total int, hasErrors bool)
+
+	// PrintReport displays the summary of a complete run
+	PrintReport(report Report)
 }
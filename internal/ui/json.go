@@ -0,0 +1,348 @@
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"goup/internal/apidiff"
+	"goup/internal/config"
+	"goup/internal/dependency"
+	"goup/internal/updater"
+)
+
+// JSONSchemaVersion is bumped whenever the JSON document changes incompatibly
+const JSONSchemaVersion = 1
+
+// ErrNonInteractive is returned when input is requested from a non-human console
+var ErrNonInteractive = errors.New("interactive input is not available with JSON output")
+
+// jsonDocument is the stable schema emitted by the JSON console
+type jsonDocument struct {
+	SchemaVersion int    `json:"schema_version"`
+	Mode          string `json:"mode"`
+	jsonOutcome
+	Modules []jsonModule `json:"modules,omitempty"`
+}
+
+// jsonOutcome is the outcome of a run, or of one module in recursive mode
+type jsonOutcome struct {
+	Dependencies []jsonDependency `json:"dependencies"`
+	Update       *jsonUpdate      `json:"update"`
+	Tidy         *jsonTidy        `json:"tidy"`
+	APIChanges   *[]jsonAPIChange `json:"api_changes,omitempty"` // Only in diff mode
+	ModDiff      *string          `json:"mod_diff,omitempty"`    // Only in dry-run mode
+	RolledBack   bool             `json:"rolled_back"`
+	Error        string           `json:"error,omitempty"`
+	ExitCode     int              `json:"exit_code"`
+}
+
+type jsonModule struct {
+	Dir string `json:"dir"`
+	jsonOutcome
+}
+
+type jsonDependency struct {
+	Path        string       `json:"path"`
+	Version     string       `json:"version"`
+	NewVersion  string       `json:"new_version"`
+	NewPath     string       `json:"new_path,omitempty"`
+	Indirect    bool         `json:"indirect"`
+	Modules     []string     `json:"modules,omitempty"`
+	Advisories  []string     `json:"advisories,omitempty"`
+	Retracted   []string     `json:"retracted,omitempty"`
+	Deprecated  string       `json:"deprecated,omitempty"`
+	Replacement string       `json:"replacement,omitempty"`
+	ReplacedBy  *jsonReplace `json:"replaced_by,omitempty"`
+	DropReplace bool         `json:"drop_replace,omitempty"`
+	Changelog   string       `json:"changelog,omitempty"`
+	Breaking    []string     `json:"breaking,omitempty"`
+	Impact      *jsonImpact  `json:"impact,omitempty"`
+	Chain       []string     `json:"chain,omitempty"`
+	PulledBy    []jsonPull   `json:"pulled_by,omitempty"`
+}
+
+type jsonReplace struct {
+	Path       string `json:"path"`
+	Version    string `json:"version,omitempty"`
+	OldVersion string `json:"old_version,omitempty"`
+}
+
+type jsonPull struct {
+	Path     string `json:"path"`
+	Version  string `json:"version"`
+	Requires string `json:"requires"`
+}
+
+type jsonImpact struct {
+	Importers  []string `json:"importers"`
+	Transitive []string `json:"transitive"`
+	CallSites  int      `json:"call_sites"`
+}
+
+type jsonUpdate struct {
+	Success  bool             `json:"success"`
+	Updated  []jsonDependency `json:"updated"`
+	Failed   []jsonFailure    `json:"failed"`
+	Verified []jsonDependency `json:"verified"`
+	Reverted []jsonFailure    `json:"reverted"`
+	Skipped  []jsonDependency `json:"skipped"`
+}
+
+type jsonFailure struct {
+	jsonDependency
+	Error string `json:"error"`
+}
+
+type jsonAPIChange struct {
+	Package  string `json:"package"`
+	Name     string `json:"name,omitempty"`
+	Kind     string `json:"kind"`
+	Old      string `json:"old,omitempty"`
+	New      string `json:"new,omitempty"`
+	Breaking bool   `json:"breaking"`
+}
+
+type jsonTidy struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// jsonConsole implements Console for tools: human messages go to the log
+// writer and the run is summarised as one JSON document on the output writer.
+type jsonConsole struct {
+	verbose bool
+	out     io.Writer
+	log     io.Writer
+}
+
+// NewJSONConsole creates a console that writes the report as JSON to stdout
+// and diagnostics to stderr
+func NewJSONConsole(cfg *config.Config) Console {
+	return NewJSONConsoleWithWriters(cfg, os.Stdout, os.Stderr)
+}
+
+// NewJSONConsoleWithWriters creates a JSON console with custom writers
+func NewJSONConsoleWithWriters(cfg *config.Config, out, log io.Writer) Console {
+	return &jsonConsole{
+		verbose: cfg.Verbose,
+		out:     out,
+		log:     log,
+	}
+}
+
+func (c *jsonConsole) Header() {}
+
+func (c *jsonConsole) Info(format string, args ...any) {
+	c.logVerbose("INFO", format, args...)
+}
+
+func (c *jsonConsole) Success(format string, args ...any) {
+	c.logVerbose("SUCCESS", format, args...)
+}
+
+func (c *jsonConsole) Warning(format string, args ...any) {
+	c.logMessage("WARNING", format, args...)
+}
+
+func (c *jsonConsole) Error(format string, args ...any) {
+	c.logMessage("ERROR", format, args...)
+}
+
+func (c *jsonConsole) Debug(format string, args ...any) {
+	c.logVerbose("DEBUG", format, args...)
+}
+
+func (c *jsonConsole) Progress(format string, args ...any) {
+	c.logVerbose("PROGRESS", format, args...)
+}
+
+func (c *jsonConsole) ProgressBar(current, total int, message string) {}
+
+func (c *jsonConsole) ReadInput(prompt string) (string, error) {
+	return "", ErrNonInteractive
+}
+
+func (c *jsonConsole) Confirm(message string) bool {
+	return false
+}
+
+func (c *jsonConsole) PrintDependencies(deps []dependency.Dependency, title string) {}
+
+func (c *jsonConsole) PrintChangelog(dep dependency.Dependency) {}
+
+func (c *jsonConsole) PrintImpact(dep dependency.Dependency) {}
+
+func (c *jsonConsole) PrintRequirements(dep dependency.Dependency) {}
+
+func (c *jsonConsole) PrintAPIChanges(dep dependency.Dependency, changes []apidiff.Change) {}
+
+func (c *jsonConsole) PrintModDiff(modDiff string) {}
+
+func (c *jsonConsole) PrintUpdateResult(updated, total int, hasErrors bool) {}
+
+func (c *jsonConsole) PrintReport(report Report) {
+	encoder := json.NewEncoder(c.out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(newJSONDocument(report)); err != nil {
+		c.Error("writing JSON report: %v", err)
+	}
+}
+
+func (c *jsonConsole) logVerbose(label, format string, args ...any) {
+	if c.verbose {
+		c.logMessage(label, format, args...)
+	}
+}
+
+func (c *jsonConsole) logMessage(label, format string, args ...any) {
+	fmt.Fprintf(c.log, "[%s] %s\n", label, fmt.Sprintf(format, args...))
+}
+
+func newJSONDocument(report Report) jsonDocument {
+	doc := jsonDocument{
+		SchemaVersion: JSONSchemaVersion,
+		Mode:          report.Mode,
+		jsonOutcome:   newJSONOutcome(report),
+	}
+
+	for _, module := range report.Modules {
+		doc.Modules = append(doc.Modules, jsonModule{
+			Dir:         module.Dir,
+			jsonOutcome: newJSONOutcome(module.Report),
+		})
+	}
+
+	return doc
+}
+
+func newJSONOutcome(report Report) jsonOutcome {
+	outcome := jsonOutcome{
+		Dependencies: newJSONDependencies(report.Dependencies),
+		RolledBack:   report.RolledBack,
+		ExitCode:     report.ExitCode,
+	}
+
+	if report.Update != nil {
+		outcome.Update = &jsonUpdate{
+			Success:  report.Update.Success,
+			Updated:  newJSONDependencies(report.Update.Updated),
+			Failed:   newJSONFailures(report.Update.Failed),
+			Verified: newJSONDependencies(report.Update.Verified),
+			Reverted: newJSONFailures(report.Update.Reverted),
+			Skipped:  newJSONDependencies(report.Update.Skipped),
+		}
+	}
+
+	if report.Tidy != nil {
+		outcome.Tidy = &jsonTidy{Success: report.Tidy.Err == nil}
+		if report.Tidy.Err != nil {
+			outcome.Tidy.Error = report.Tidy.Err.Error()
+		}
+	}
+
+	if report.Mode == ModeDiff {
+		outcome.APIChanges = newJSONAPIChanges(report.APIChanges)
+	}
+
+	if report.Mode == ModeDryRun {
+		outcome.ModDiff = &report.ModDiff
+	}
+
+	if report.Err != nil {
+		outcome.Error = report.Err.Error()
+	}
+
+	return outcome
+}
+
+func newJSONDependencies(deps []dependency.Dependency) []jsonDependency {
+	// Always emit an array, never null, so consumers can iterate unconditionally
+	result := make([]jsonDependency, 0, len(deps))
+	for _, dep := range deps {
+		result = append(result, newJSONDependency(dep))
+	}
+	return result
+}
+
+func newJSONDependency(dep dependency.Dependency) jsonDependency {
+	return jsonDependency{
+		Path:        dep.Path,
+		Version:     dep.Version,
+		NewVersion:  dep.NewVersion,
+		NewPath:     dep.NewPath,
+		Indirect:    dep.Indirect,
+		Modules:     dep.Modules,
+		Advisories:  dep.Advisories,
+		Retracted:   dep.Retracted,
+		Deprecated:  dep.Deprecated,
+		Replacement: dep.Replacement(),
+		ReplacedBy:  newJSONReplace(dep.Replace),
+		DropReplace: dep.DropReplace,
+		Changelog:   dep.Changelog,
+		Breaking:    dep.Breaking,
+		Impact:      newJSONImpact(dep.Impact),
+		Chain:       dep.Chain,
+		PulledBy:    newJSONPulls(dep.PulledBy),
+	}
+}
+
+func newJSONReplace(r *dependency.Replace) *jsonReplace {
+	if r == nil {
+		return nil
+	}
+	return &jsonReplace{Path: r.Path, Version: r.Version, OldVersion: r.OldVersion}
+}
+
+func newJSONPulls(pulls []dependency.Pull) []jsonPull {
+	var result []jsonPull
+	for _, pull := range pulls {
+		result = append(result, jsonPull{Path: pull.Path, Version: pull.Version, Requires: pull.Requires})
+	}
+	return result
+}
+
+func newJSONImpact(impact *dependency.Impact) *jsonImpact {
+	if impact == nil {
+		return nil
+	}
+	// Arrays rather than null, as for the dependencies
+	return &jsonImpact{
+		Importers:  append([]string{}, impact.Importers...),
+		Transitive: append([]string{}, impact.Transitive...),
+		CallSites:  impact.CallSites,
+	}
+}
+
+func newJSONAPIChanges(changes []apidiff.Change) *[]jsonAPIChange {
+	result := make([]jsonAPIChange, 0, len(changes))
+	for _, change := range changes {
+		result = append(result, jsonAPIChange{
+			Package:  change.Package,
+			Name:     change.Name,
+			Kind:     string(change.Kind),
+			Old:      change.Old,
+			New:      change.New,
+			Breaking: change.Breaking(),
+		})
+	}
+	return &result
+}
+
+func newJSONFailures(failures []updater.UpdateError) []jsonFailure {
+	result := make([]jsonFailure, 0, len(failures))
+	for _, failure := range failures {
+		result = append(result, newJSONFailure(failure))
+	}
+	return result
+}
+
+func newJSONFailure(failure updater.UpdateError) jsonFailure {
+	result := jsonFailure{jsonDependency: newJSONDependency(failure.Dependency)}
+	if failure.Error != nil {
+		result.Error = failure.Error.Error()
+	}
+	return result
+}
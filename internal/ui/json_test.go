@@ -0,0 +1,304 @@
+package ui
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"goup/internal/apidiff"
+	"goup/internal/config"
+	"goup/internal/dependency"
+	"goup/internal/updater"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+func assertGolden(t *testing.T, name string, actual []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+	if *updateGolden {
+		require.NoError(t, os.WriteFile(path, actual, 0644))
+	}
+
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(actual))
+}
+
+func TestJSONConsolePrintReport(t *testing.T) {
+	gin := dependency.Dependency{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true}
+	crypto := dependency.Dependency{Path: "golang.org/x/crypto", Version: "v0.14.0", NewVersion: "v0.17.0", Indirect: true, HasUpdate: true}
+
+	tests := []struct {
+		name   string
+		report Report
+	}{
+		{
+			name:   "list_up_to_date",
+			report: Report{Mode: ModeList},
+		},
+		{
+			name: "list_updates",
+			report: Report{
+				Mode:         ModeList,
+				Dependencies: []dependency.Dependency{gin, crypto},
+				ExitCode:     2,
+			},
+		},
+		{
+			name: "list_workspace",
+			report: Report{
+				Mode: ModeList,
+				Dependencies: []dependency.Dependency{
+					{Path: gin.Path, Version: gin.Version, NewVersion: gin.NewVersion, HasUpdate: true, Modules: []string{"api", "worker"}},
+				},
+			},
+		},
+		{
+			name: "list_security",
+			report: Report{
+				Mode: ModeList,
+				Dependencies: []dependency.Dependency{
+					{Path: "golang.org/x/text", Version: "v0.3.7", NewVersion: "v0.3.8", HasUpdate: true, Advisories: []string{"GO-2022-1059"}},
+				},
+			},
+		},
+		{
+			name: "list_retracted",
+			report: Report{
+				Mode: ModeList,
+				Dependencies: []dependency.Dependency{
+					{Path: "github.com/old/lib", Version: "v1.4.0", NewVersion: "v1.4.1", HasUpdate: true,
+						Retracted: []string{"Data race in the pool"}, Deprecated: "use github.com/new/lib instead"},
+				},
+			},
+		},
+		{
+			name: "list_replaced",
+			report: Report{
+				Mode: ModeList,
+				Dependencies: []dependency.Dependency{
+					{Path: "example.com/lib", Version: "v1.0.0", NewVersion: "v1.1.0", HasUpdate: true,
+						Replace: &dependency.Replace{Path: "./lib"}},
+					{Path: "github.com/foo/bar", Version: "v1.2.0", NewVersion: "v1.2.1", HasUpdate: true, DropReplace: true,
+						Replace: &dependency.Replace{OldVersion: "v1.2.0", Path: "github.com/me/bar", Version: "v1.2.1-fix"}},
+				},
+			},
+		},
+		{
+			name: "list_changelog",
+			report: Report{
+				Mode: ModeList,
+				Dependencies: []dependency.Dependency{
+					{Path: gin.Path, Version: gin.Version, NewVersion: gin.NewVersion, HasUpdate: true,
+						Changelog: "--- github.com/gin-gonic/gin@v1.9.1/CHANGELOG.md\n+++ github.com/gin-gonic/gin@v1.9.2/CHANGELOG.md\n@@ -1 +1,2 @@\n+## v1.9.2\n # Changelog\n"},
+				},
+			},
+		},
+		{
+			name: "list_impact",
+			report: Report{
+				Mode: ModeList,
+				Dependencies: []dependency.Dependency{
+					{Path: gin.Path, Version: gin.Version, NewVersion: gin.NewVersion, HasUpdate: true,
+						Impact: &dependency.Impact{Importers: []string{"example.com/app/api"}, Transitive: []string{"example.com/app"}, CallSites: 14}},
+					{Path: crypto.Path, Version: crypto.Version, NewVersion: crypto.NewVersion, HasUpdate: true, Impact: &dependency.Impact{}},
+				},
+			},
+		},
+		{
+			name: "why",
+			report: Report{
+				Mode: ModeWhy,
+				Dependencies: []dependency.Dependency{
+					{Path: "golang.org/x/net", Version: "v0.10.0", NewVersion: "v0.17.0", HasUpdate: true, Indirect: true,
+						Chain:    []string{"example.com/app", "github.com/gin-gonic/gin@v1.9.1", "golang.org/x/net@v0.10.0"},
+						PulledBy: []dependency.Pull{{Path: "github.com/gin-gonic/gin", Version: "v1.9.2", Requires: "v0.17.0"}}},
+				},
+			},
+		},
+		{
+			name: "exclude",
+			report: Report{
+				Mode:         ModeExclude,
+				Dependencies: []dependency.Dependency{{Path: "example.com/lib", Version: "v1.2.0"}},
+				Tidy:         &TidyResult{},
+			},
+		},
+		{
+			name: "diff",
+			report: Report{
+				Mode: ModeDiff,
+				Dependencies: []dependency.Dependency{
+					{Path: "example.com/lib", Version: "v1.2.0", NewVersion: "v1.3.0", HasUpdate: true, Breaking: []string{"example.com/lib.Foo"}},
+				},
+				APIChanges: []apidiff.Change{
+					{Package: "example.com/lib", Name: "Bar", Kind: apidiff.Added, New: "func Bar()"},
+					{Package: "example.com/lib", Name: "Foo", Kind: apidiff.Changed, Old: "func Foo()", New: "func Foo(int)"},
+				},
+			},
+		},
+		{
+			name: "update_partial_failure",
+			report: Report{
+				Mode:         ModeUpdate,
+				Dependencies: []dependency.Dependency{gin, crypto},
+				Update: &updater.UpdateResult{
+					Updated: []dependency.Dependency{gin},
+					Failed: []updater.UpdateError{
+						{Dependency: crypto, Error: errors.New("command failed: exit status 1")},
+					},
+					Success: false,
+				},
+				Tidy:     &TidyResult{},
+				ExitCode: 3,
+			},
+		},
+		{
+			name: "update_rolled_back",
+			report: Report{
+				Mode:         ModeUpdate,
+				Dependencies: []dependency.Dependency{gin, crypto},
+				Update: &updater.UpdateResult{
+					Updated: []dependency.Dependency{gin},
+					Failed: []updater.UpdateError{
+						{Dependency: crypto, Error: errors.New("command failed: exit status 1")},
+					},
+					Success: false,
+				},
+				RolledBack: true,
+				ExitCode:   3,
+			},
+		},
+		{
+			name: "update_verified",
+			report: Report{
+				Mode:         ModeUpdate,
+				Dependencies: []dependency.Dependency{gin, crypto},
+				Update: &updater.UpdateResult{
+					Updated:  []dependency.Dependency{gin},
+					Verified: []dependency.Dependency{gin},
+					Reverted: []updater.UpdateError{
+						{Dependency: crypto, Error: errors.New("verification failed: go test ./...: exit status 1")},
+					},
+					Success: false,
+				},
+				Tidy:     &TidyResult{},
+				ExitCode: 3,
+			},
+		},
+		{
+			name: "update_tidy_failure",
+			report: Report{
+				Mode:         ModeUpdate,
+				Dependencies: []dependency.Dependency{gin},
+				Update: &updater.UpdateResult{
+					Updated: []dependency.Dependency{gin},
+					Success: true,
+				},
+				Tidy: &TidyResult{Err: errors.New("go mod tidy failed")},
+			},
+		},
+		{
+			name: "dry_run",
+			report: Report{
+				Mode:         ModeDryRun,
+				Dependencies: []dependency.Dependency{gin},
+				Update: &updater.UpdateResult{
+					Updated: []dependency.Dependency{gin},
+					Success: true,
+				},
+				Tidy:    &TidyResult{},
+				ModDiff: "--- go.mod\n+++ go.mod\n@@ -3 +3 @@\n-require github.com/gin-gonic/gin v1.9.1\n+require github.com/gin-gonic/gin v1.9.2\n",
+			},
+		},
+		{
+			name: "update_recursive",
+			report: Report{
+				Mode:     ModeUpdate,
+				ExitCode: 1,
+				Err:      errors.New("1 of 2 modules failed: tools"),
+				Modules: []ModuleReport{
+					{Dir: "services/api", Report: Report{
+						Mode:         ModeUpdate,
+						Dependencies: []dependency.Dependency{gin},
+						Update: &updater.UpdateResult{
+							Updated: []dependency.Dependency{gin},
+							Success: true,
+						},
+						Tidy: &TidyResult{},
+					}},
+					{Dir: "tools", Report: Report{
+						Mode:     ModeUpdate,
+						Err:      errors.New("failed to check for updates"),
+						ExitCode: 1,
+					}},
+				},
+			},
+		},
+		{
+			name: "error",
+			report: Report{
+				Mode:     ModeUpdate,
+				Err:      errors.New("failed to check for updates"),
+				ExitCode: 1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, log bytes.Buffer
+			console := NewJSONConsoleWithWriters(&config.Config{}, &out, &log)
+
+			console.PrintReport(tt.report)
+
+			assertGolden(t, "report_"+tt.name+".json", out.Bytes())
+			assert.Empty(t, log.String())
+		})
+	}
+}
+
+func TestJSONConsoleKeepsOutputClean(t *testing.T) {
+	var out, log bytes.Buffer
+	console := NewJSONConsoleWithWriters(&config.Config{}, &out, &log)
+
+	console.Header()
+	console.Info("Updating dependencies...")
+	console.Success("go mod tidy completed")
+	console.Debug("Selected %d dependencies for update", 1)
+	console.ProgressBar(1, 2, "github.com/gin-gonic/gin")
+	console.PrintDependencies([]dependency.Dependency{{Path: "github.com/gin-gonic/gin"}}, "title")
+	console.PrintUpdateResult(1, 1, false)
+	console.Warning("go mod tidy failed: %v", "boom")
+	console.Error("Failed to update %s", "github.com/bad/package")
+
+	assert.Empty(t, out.String(), "Only the report may be written to the output")
+	assert.Equal(t, "[WARNING] go mod tidy failed: boom\n[ERROR] Failed to update github.com/bad/package\n", log.String())
+}
+
+func TestJSONConsoleVerboseLogging(t *testing.T) {
+	var out, log bytes.Buffer
+	console := NewJSONConsoleWithWriters(&config.Config{Verbose: true}, &out, &log)
+
+	console.Info("Updating dependencies...")
+	console.Debug("Selected %d dependencies for update", 1)
+
+	assert.Empty(t, out.String())
+	assert.Equal(t, "[INFO] Updating dependencies...\n[DEBUG] Selected 1 dependencies for update\n", log.String())
+}
+
+func TestJSONConsoleIsNotInteractive(t *testing.T) {
+	console := NewJSONConsoleWithWriters(&config.Config{}, &bytes.Buffer{}, &bytes.Buffer{})
+
+	_, err := console.ReadInput("Select dependencies to update")
+	assert.ErrorIs(t, err, ErrNonInteractive)
+	assert.False(t, console.Confirm("Proceed?"))
+}
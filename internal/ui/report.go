@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"goup/internal/apidiff"
+	"goup/internal/dependency"
+	"goup/internal/updater"
+)
+
+// Report modes
+const (
+	ModeList     = "list"
+	ModeUpdate   = "update"
+	ModeRollback = "rollback"
+	ModeDiff     = "diff"
+	ModeWhy      = "why"
+	ModeDryRun   = "dry-run"
+	ModeExclude  = "exclude"
+)
+
+// Report summarises a complete goup run. Human consoles print everything as it
+// happens, machine-readable consoles emit the report as a single document.
+type Report struct {
+	Mode         string                  // ModeList, ModeUpdate, ModeDryRun, ModeRollback, ModeDiff, ModeWhy or ModeExclude
+	Dependencies []dependency.Dependency // Dependencies with available updates
+	Update       *updater.UpdateResult   // Update outcome, nil if no update ran. In a dry run, of the copy of go.mod
+	Tidy         *TidyResult             // go mod tidy outcome, nil if it did not run
+	ModDiff      string                  // Unified diff of go.mod and go.sum computed by a dry run
+	APIChanges   []apidiff.Change        // API changes of the dependency compared by 'goup diff'
+	RolledBack   bool                    // go.mod and go.sum were restored from the snapshot
+	Err          error                   // Error that aborted the run, if any
+	ExitCode     int                     // Process exit code for the run
+	Modules      []ModuleReport          // Outcome of each module in recursive mode
+}
+
+// ModuleReport is the outcome of one module of a recursive run
+type ModuleReport struct {
+	Dir string // Module directory relative to the target directory
+	Report
+}
+
+// TidyResult contains the outcome of running go mod tidy
+type TidyResult struct {
+	Err error
+}
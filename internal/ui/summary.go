@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// printModuleSummary prints one line per module of a recursive run
+func (c *console) printModuleSummary(modules []ModuleReport) {
+	width := 0
+	for _, module := range modules {
+		width = max(width, utf8.RuneCountInString(module.Dir))
+	}
+
+	fmt.Println()
+	c.printBox(fmt.Sprintf("📁 Summary: %d modules", len(modules)), Primary)
+	fmt.Println()
+
+	for _, module := range modules {
+		ok, status := moduleStatus(module.Report)
+		dir := module.Dir + strings.Repeat(" ", width-utf8.RuneCountInString(module.Dir))
+
+		switch {
+		case c.noColor && ok:
+			fmt.Printf("[OK]   %s  %s\n", dir, status)
+		case c.noColor:
+			fmt.Printf("[FAIL] %s  %s\n", dir, status)
+		case ok:
+			fmt.Printf(" %s%s%s %s%s%s  %s\n", Success, SymbolCheck, Reset, Accent, dir, Reset, status)
+		default:
+			fmt.Printf(" %s%s%s %s%s%s  %s%s%s\n", Error, SymbolCross, Reset, Accent, dir, Reset, Red, status, Reset)
+		}
+	}
+	fmt.Println()
+}
+
+// moduleStatus describes the outcome of one module and whether it succeeded
+func moduleStatus(report Report) (bool, string) {
+	switch {
+	case report.Err != nil:
+		// The full error has been printed when the module failed
+		message, _, _ := strings.Cut(report.Err.Error(), "\n")
+		return false, "error: " + message
+	case report.Mode == ModeRollback:
+		return report.RolledBack, "restored from the last run"
+	case report.Update != nil:
+		parts := []string{fmt.Sprintf("%d updated", len(report.Update.Updated))}
+		if report.Mode == ModeDryRun {
+			parts[0] = fmt.Sprintf("%d would be updated", len(report.Update.Updated))
+		}
+		if n := len(report.Update.Failed); n > 0 {
+			parts = append(parts, fmt.Sprintf("%d failed", n))
+		}
+		if n := len(report.Update.Reverted); n > 0 {
+			parts = append(parts, fmt.Sprintf("%d reverted", n))
+		}
+		if report.RolledBack {
+			parts = append(parts, "rolled back")
+		}
+		return len(report.Update.Failed) == 0 && len(report.Update.Reverted) == 0, strings.Join(parts, ", ")
+	case len(report.Dependencies) > 0 && report.Mode == ModeList:
+		return true, fmt.Sprintf("%d updates available", len(report.Dependencies))
+	case len(report.Dependencies) > 0:
+		return true, "no updates applied"
+	default:
+		return true, "up to date"
+	}
+}
@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"goup/internal/dependency"
+	"goup/internal/updater"
+)
+
+func TestModuleStatus(t *testing.T) {
+	gin := dependency.Dependency{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2"}
+	failure := updater.UpdateError{Dependency: gin, Error: errors.New("exit status 1")}
+
+	tests := []struct {
+		name   string
+		report Report
+		ok     bool
+		status string
+	}{
+		{
+			name:   "up to date",
+			report: Report{Mode: ModeUpdate},
+			ok:     true,
+			status: "up to date",
+		},
+		{
+			name:   "list with updates",
+			report: Report{Mode: ModeList, Dependencies: []dependency.Dependency{gin}},
+			ok:     true,
+			status: "1 updates available",
+		},
+		{
+			name:   "nothing selected",
+			report: Report{Mode: ModeUpdate, Dependencies: []dependency.Dependency{gin}},
+			ok:     true,
+			status: "no updates applied",
+		},
+		{
+			name: "updated",
+			report: Report{Mode: ModeUpdate, Update: &updater.UpdateResult{
+				Updated: []dependency.Dependency{gin},
+			}},
+			ok:     true,
+			status: "1 updated",
+		},
+		{
+			name: "failed and rolled back",
+			report: Report{Mode: ModeUpdate, RolledBack: true, Update: &updater.UpdateResult{
+				Updated: []dependency.Dependency{gin},
+				Failed:  []updater.UpdateError{failure},
+			}},
+			status: "1 updated, 1 failed, rolled back",
+		},
+		{
+			name: "reverted by verification",
+			report: Report{Mode: ModeUpdate, Update: &updater.UpdateResult{
+				Reverted: []updater.UpdateError{failure},
+			}},
+			status: "0 updated, 1 reverted",
+		},
+		{
+			name: "dry run",
+			report: Report{Mode: ModeDryRun, Update: &updater.UpdateResult{
+				Updated: []dependency.Dependency{gin},
+				Failed:  []updater.UpdateError{failure},
+			}},
+			status: "1 would be updated, 1 failed",
+		},
+		{
+			name:   "rollback",
+			report: Report{Mode: ModeRollback, RolledBack: true},
+			ok:     true,
+			status: "restored from the last run",
+		},
+		{
+			name:   "error",
+			report: Report{Mode: ModeUpdate, Err: errors.New("go list failed\noutput:\ngo: missing go.sum entry")},
+			status: "error: go list failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, status := moduleStatus(tt.report)
+
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.status, status)
+		})
+	}
+}
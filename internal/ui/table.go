@@ -0,0 +1,370 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"goup/internal/dependency"
+)
+
+// tableColumn describes one column of the dependency table
+type tableColumn struct {
+	title string
+	width int
+	value func(index int, dep dependency.Dependency) string // index is 1-based
+	color func(dep dependency.Dependency) string
+}
+
+// PrintDependencies prints dependencies in a formatted table
+func (c *console) PrintDependencies(deps []dependency.Dependency, title string) {
+	if title != "" {
+		c.Info("%s", title)
+	}
+	fmt.Println()
+
+	if len(deps) == 0 {
+		return
+	}
+
+	columns := c.tableColumns(deps)
+
+	if c.noColor {
+		c.printSimpleTable(deps, columns)
+		return
+	}
+
+	c.printStyledTable(deps, columns)
+}
+
+// tableColumns returns the columns shown for a set of dependencies. Optional
+// columns only appear when at least one dependency has a value for them.
+func (c *console) tableColumns(deps []dependency.Dependency) []tableColumn {
+	total := len(deps)
+
+	columns := []tableColumn{
+		{
+			title: "#",
+			width: c.calculateIndexWidth(total),
+			value: func(index int, dep dependency.Dependency) string { return fmt.Sprintf("%d/%d", index, total) },
+			color: func(dep dependency.Dependency) string { return Secondary },
+		},
+		{
+			title: "Package",
+			width: c.calculateMaxPathWidth(deps),
+			value: func(index int, dep dependency.Dependency) string { return dep.Path },
+			color: pathColor,
+		},
+		{
+			title: "Current Version",
+			width: 15,
+			value: func(index int, dep dependency.Dependency) string { return dep.Version },
+			color: func(dep dependency.Dependency) string { return Cyan },
+		},
+		{
+			title: "New Version",
+			width: 15,
+			value: func(index int, dep dependency.Dependency) string { return dep.NewVersion },
+			color: func(dep dependency.Dependency) string { return Success },
+		},
+		{
+			title: "Type",
+			width: 8,
+			value: func(index int, dep dependency.Dependency) string { return dependencyType(dep) },
+			color: typeColor,
+		},
+	}
+
+	status := func(index int, dep dependency.Dependency) string { return statusLabel(dep) }
+	if width := optionalWidth(deps, "Status", status); width > 0 {
+		columns = append(columns, tableColumn{
+			title: "Status",
+			width: width,
+			value: status,
+			color: statusColor,
+		})
+	}
+
+	replaced := func(index int, dep dependency.Dependency) string { return replacedBy(dep) }
+	if width := optionalWidth(deps, "Replaced By", replaced); width > 0 {
+		columns = append(columns, tableColumn{
+			title: "Replaced By",
+			width: width,
+			value: replaced,
+			color: replacedColor,
+		})
+	}
+
+	modules := func(index int, dep dependency.Dependency) string { return strings.Join(dep.Modules, ", ") }
+	if width := optionalWidth(deps, "Module", modules); width > 0 {
+		columns = append(columns, tableColumn{
+			title: "Module",
+			width: width,
+			value: modules,
+			color: func(dep dependency.Dependency) string { return Blue },
+		})
+	}
+
+	advisories := func(index int, dep dependency.Dependency) string { return strings.Join(dep.Advisories, ", ") }
+	if width := optionalWidth(deps, "Advisories", advisories); width > 0 {
+		columns = append(columns, tableColumn{
+			title: "Advisories",
+			width: width,
+			value: advisories,
+			color: func(dep dependency.Dependency) string { return Error },
+		})
+	}
+
+	breaking := func(index int, dep dependency.Dependency) string { return breakingLabel(dep) }
+	if width := optionalWidth(deps, "API", breaking); width > 0 {
+		columns = append(columns, tableColumn{
+			title: "API",
+			width: width,
+			value: breaking,
+			color: func(dep dependency.Dependency) string { return Warning },
+		})
+	}
+
+	via := func(index int, dep dependency.Dependency) string { return requiredVia(dep) }
+	if width := optionalWidth(deps, "Required Via", via); width > 0 {
+		columns = append(columns, tableColumn{
+			title: "Required Via",
+			width: width,
+			value: via,
+			color: func(dep dependency.Dependency) string { return Secondary },
+		})
+	}
+
+	raised := func(index int, dep dependency.Dependency) string { return raisedBy(dep) }
+	if width := optionalWidth(deps, "Raised By", raised); width > 0 {
+		columns = append(columns, tableColumn{
+			title: "Raised By",
+			width: width,
+			value: raised,
+			color: func(dep dependency.Dependency) string { return Blue },
+		})
+	}
+
+	impact := func(index int, dep dependency.Dependency) string { return impactLabel(dep) }
+	if width := optionalWidth(deps, "Impact", impact); width > 0 {
+		columns = append(columns, tableColumn{
+			title: "Impact",
+			width: width,
+			value: impact,
+			color: impactColor,
+		})
+	}
+
+	return columns
+}
+
+// optionalWidth returns the width of an optional column, or 0 if no
+// dependency has a value for it
+func optionalWidth(deps []dependency.Dependency, title string, value func(index int, dep dependency.Dependency) string) int {
+	maxWidth := 0
+	for i, dep := range deps {
+		if width := len(value(i+1, dep)); width > maxWidth {
+			maxWidth = width
+		}
+	}
+
+	switch {
+	case maxWidth == 0:
+		return 0
+	case maxWidth < len(title):
+		return len(title)
+	case maxWidth > 40:
+		return 40
+	default:
+		return maxWidth
+	}
+}
+
+func (c *console) calculateMaxPathWidth(deps []dependency.Dependency) int {
+	maxWidth := 20 // minimum width
+	for _, dep := range deps {
+		if len(dep.Path) > maxWidth {
+			maxWidth = len(dep.Path)
+		}
+	}
+	// Cap the maximum width to keep table readable
+	if maxWidth > 50 {
+		maxWidth = 50
+	}
+	return maxWidth
+}
+
+func (c *console) calculateIndexWidth(total int) int {
+	totalStr := fmt.Sprintf("%d/%d", total, total)
+	return len(totalStr)
+}
+
+func (c *console) printSimpleTable(deps []dependency.Dependency, columns []tableColumn) {
+	// Header
+	titles := make([]string, len(columns))
+	for i, col := range columns {
+		titles[i] = fmt.Sprintf("%-*s", col.width, col.title)
+	}
+	fmt.Printf(" %s\n", strings.Join(titles, " │ "))
+
+	// Separator
+	fmt.Println(simpleBorder(columns, "┼"))
+
+	// Rows
+	for i, dep := range deps {
+		cells := make([]string, len(columns))
+		for j, col := range columns {
+			cells[j] = fmt.Sprintf("%-*s", col.width, c.truncateString(col.value(i+1, dep), col.width))
+		}
+		fmt.Printf(" %s\n", strings.Join(cells, " │ "))
+	}
+
+	// Bottom border
+	fmt.Println(simpleBorder(columns, "┴"))
+	fmt.Println()
+}
+
+func simpleBorder(columns []tableColumn, junction string) string {
+	parts := make([]string, len(columns))
+	for i, col := range columns {
+		parts[i] = strings.Repeat("─", col.width+2)
+	}
+	return strings.Join(parts, junction)
+}
+
+func (c *console) printStyledTable(deps []dependency.Dependency, columns []tableColumn) {
+	// Top border
+	fmt.Println(styledBorder(columns, TableTopLeft, TableHorizontal, TableTeeDown, TableTopRight))
+
+	// Header
+	titles := make([]string, len(columns))
+	for i, col := range columns {
+		titles[i] = fmt.Sprintf("%s%s%-*s%s", Primary, Bold, col.width, col.title, Reset)
+	}
+	printStyledRow(titles)
+
+	// Header separator
+	fmt.Println(styledBorder(columns, TableTeeRight, TableHorizontal, TableCross, TableTeeLeft))
+
+	// Rows
+	for i, dep := range deps {
+		cells := make([]string, len(columns))
+		for j, col := range columns {
+			cell := c.truncateString(col.value(i+1, dep), col.width)
+			cells[j] = fmt.Sprintf("%s%-*s%s", col.color(dep), col.width, cell, Reset)
+		}
+		printStyledRow(cells)
+
+		// Row separator (except for last row)
+		if i < len(deps)-1 {
+			fmt.Println(styledBorder(columns, TableTeeRight, TableDotted, TableCross, TableTeeLeft))
+		}
+	}
+
+	// Bottom border
+	fmt.Println(styledBorder(columns, TableBottomLeft, TableHorizontal, TableTeeUp, TableBottomRight))
+	fmt.Println()
+}
+
+func styledBorder(columns []tableColumn, left, horizontal, junction, right string) string {
+	parts := make([]string, len(columns))
+	for i, col := range columns {
+		parts[i] = strings.Repeat(horizontal, col.width+2)
+	}
+	return fmt.Sprintf("   %s%s%s%s%s", Secondary, left, strings.Join(parts, junction), right, Reset)
+}
+
+func printStyledRow(cells []string) {
+	vertical := Secondary + TableVertical + Reset
+
+	var row strings.Builder
+	row.WriteString("   ")
+	for _, cell := range cells {
+		row.WriteString(vertical + " " + cell + " ")
+	}
+	row.WriteString(vertical)
+
+	fmt.Println(row.String())
+}
+
+// pathColor returns the color of the Package column based on the dependency type
+func pathColor(dep dependency.Dependency) string {
+	switch {
+	case dep.IsRetracted():
+		return Error + Bold
+	case dep.IsMajorUpgrade():
+		return Magenta
+	case dep.Indirect:
+		return Yellow
+	default:
+		return Green
+	}
+}
+
+// typeColor returns the color of the Type column
+func typeColor(dep dependency.Dependency) string {
+	switch {
+	case dep.IsMajorUpgrade():
+		return Magenta + Bold
+	case dep.Indirect:
+		return Secondary
+	default:
+		return Primary
+	}
+}
+
+// dependencyType returns the label shown in the Type column
+func dependencyType(dep dependency.Dependency) string {
+	switch {
+	case dep.IsMajorUpgrade():
+		return "major"
+	case dep.Indirect:
+		return "indirect"
+	default:
+		return "direct"
+	}
+}
+
+// statusLabel returns the label shown in the Status column: retracted
+// current versions are urgent to update, deprecated modules to replace
+func statusLabel(dep dependency.Dependency) string {
+	switch {
+	case dep.IsRetracted() && dep.Deprecated != "":
+		return "retracted (urgent), deprecated"
+	case dep.IsRetracted():
+		return "retracted (urgent)"
+	case dep.Deprecated != "":
+		return "deprecated"
+	default:
+		return ""
+	}
+}
+
+// statusColor returns the color of the Status column
+func statusColor(dep dependency.Dependency) string {
+	if dep.IsRetracted() {
+		return Error + Bold
+	}
+	return Warning
+}
+
+// replacedBy returns the label shown in the Replaced By column: the target of
+// the replace directive, marked when it is stale and will be dropped
+func replacedBy(dep dependency.Dependency) string {
+	switch {
+	case dep.Replace == nil:
+		return ""
+	case dep.DropReplace:
+		return dep.Replace.String() + " (stale)"
+	default:
+		return dep.Replace.String()
+	}
+}
+
+// replacedColor returns the color of the Replaced By column. Updating a module
+// replaced by a local directory does not change the build.
+func replacedColor(dep dependency.Dependency) string {
+	if dep.Replace != nil && dep.Replace.IsLocal() {
+		return Warning
+	}
+	return Blue
+}
@@ -0,0 +1,27 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"goup/internal/dependency"
+)
+
+func TestStatusLabel(t *testing.T) {
+	retracted := []string{"Data race in the pool"}
+
+	assert.Equal(t, "retracted (urgent)", statusLabel(dependency.Dependency{Retracted: retracted}))
+	assert.Equal(t, "deprecated", statusLabel(dependency.Dependency{Deprecated: "unmaintained"}))
+	assert.Equal(t, "retracted (urgent), deprecated", statusLabel(dependency.Dependency{Retracted: retracted, Deprecated: "unmaintained"}))
+	assert.Empty(t, statusLabel(dependency.Dependency{}))
+}
+
+func TestReplacedBy(t *testing.T) {
+	fork := &dependency.Replace{Path: "github.com/me/bar", Version: "v1.2.1-fix"}
+
+	assert.Equal(t, "./lib", replacedBy(dependency.Dependency{Replace: &dependency.Replace{Path: "./lib"}}))
+	assert.Equal(t, "github.com/me/bar v1.2.1-fix", replacedBy(dependency.Dependency{Replace: fork}))
+	assert.Equal(t, "github.com/me/bar v1.2.1-fix (stale)", replacedBy(dependency.Dependency{Replace: fork, DropReplace: true}))
+	assert.Empty(t, replacedBy(dependency.Dependency{}))
+}
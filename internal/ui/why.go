@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"goup/internal/dependency"
+)
+
+// PrintRequirements prints why a dependency is required: the chain of
+// requirements from the main module, then the direct dependency updates that
+// raise it
+func (c *console) PrintRequirements(dep dependency.Dependency) {
+	if c.noColor {
+		fmt.Printf("Why %s %s is required:\n", dep.Path, dep.VersionInfo())
+	} else {
+		fmt.Printf(" 🔗 %sWhy %s is required%s %s(%s)%s\n", Accent, dep.Path, Reset, Secondary, dep.VersionInfo(), Reset)
+	}
+
+	for _, line := range chainLines(dep.Chain) {
+		fmt.Printf("  %s\n", line)
+	}
+	fmt.Println()
+
+	if !dep.Indirect {
+		c.Info("%s is a direct dependency, required by go.mod", dep.Path)
+		return
+	}
+	if len(dep.PulledBy) == 0 {
+		c.Info("No update of a direct dependency requires a newer version of %s", dep.Path)
+		return
+	}
+
+	c.Info("Direct dependency updates raising %s:", dep.Path)
+	for _, line := range pullLines(dep.PulledBy) {
+		if c.noColor {
+			fmt.Printf("  %s\n", line)
+			continue
+		}
+		fmt.Printf("  %s%s%s\n", Blue, line, Reset)
+	}
+	fmt.Println()
+}
+
+// chainLines draws a requirement chain as a tree, one module per line
+func chainLines(chain []string) []string {
+	lines := make([]string, 0, len(chain))
+	for i, node := range chain {
+		if i == 0 {
+			lines = append(lines, node)
+			continue
+		}
+		lines = append(lines, strings.Repeat("   ", i-1)+"└─ "+node)
+	}
+	return lines
+}
+
+// pullLines describes the updates of direct dependencies raising an indirect one
+func pullLines(pulls []dependency.Pull) []string {
+	lines := make([]string, 0, len(pulls))
+	for _, pull := range pulls {
+		lines = append(lines, fmt.Sprintf("%s %s requires %s", pull.Path, pull.Version, pull.Requires))
+	}
+	return lines
+}
+
+// requiredVia returns the modules between the main module and an indirect
+// dependency in its requirement chain, empty if it has none
+func requiredVia(dep dependency.Dependency) string {
+	if len(dep.Chain) <= 2 {
+		return ""
+	}
+
+	paths := make([]string, 0, len(dep.Chain)-2)
+	for _, node := range dep.Chain[1 : len(dep.Chain)-1] {
+		path, _, _ := strings.Cut(node, "@")
+		paths = append(paths, path)
+	}
+	return strings.Join(paths, " → ")
+}
+
+// raisedBy returns the direct dependencies whose update raises an indirect one
+func raisedBy(dep dependency.Dependency) string {
+	paths := make([]string, 0, len(dep.PulledBy))
+	for _, pull := range dep.PulledBy {
+		paths = append(paths, pull.Path)
+	}
+	return strings.Join(paths, ", ")
+}
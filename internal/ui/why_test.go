@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"goup/internal/dependency"
+)
+
+func TestChainLines(t *testing.T) {
+	assert.Equal(t, []string{
+		"example.com/app",
+		"└─ github.com/gin-gonic/gin@v1.9.1",
+		"   └─ golang.org/x/net@v0.10.0",
+	}, chainLines([]string{"example.com/app", "github.com/gin-gonic/gin@v1.9.1", "golang.org/x/net@v0.10.0"}))
+}
+
+func TestPullLines(t *testing.T) {
+	assert.Equal(t, []string{"github.com/gin-gonic/gin v1.9.2 requires v0.17.0"},
+		pullLines([]dependency.Pull{{Path: "github.com/gin-gonic/gin", Version: "v1.9.2", Requires: "v0.17.0"}}))
+}
+
+func TestRequirementColumns(t *testing.T) {
+	dep := dependency.Dependency{
+		Path:  "golang.org/x/text",
+		Chain: []string{"example.com/app", "github.com/gin-gonic/gin@v1.9.1", "golang.org/x/net@v0.10.0", "golang.org/x/text@v0.9.0"},
+		PulledBy: []dependency.Pull{
+			{Path: "github.com/gin-gonic/gin", Version: "v1.9.2", Requires: "v0.14.0"},
+			{Path: "github.com/spf13/cobra", Version: "v1.8.1", Requires: "v0.13.0"},
+		},
+	}
+
+	assert.Equal(t, "github.com/gin-gonic/gin → golang.org/x/net", requiredVia(dep))
+	assert.Equal(t, "github.com/gin-gonic/gin, github.com/spf13/cobra", raisedBy(dep))
+
+	assert.Empty(t, requiredVia(dependency.Dependency{Chain: []string{"example.com/app", "golang.org/x/text@v0.9.0"}}),
+		"Required by go.mod only")
+	assert.Empty(t, raisedBy(dependency.Dependency{}))
+}
@@ -0,0 +1,118 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+
+	"goup/internal/dependency"
+)
+
+// updateBatch applies every update with a single 'go get' per module so the
+// module graph is resolved once. Major upgrades rewrite imports one module at
+// a time and are applied individually after the batch, like the updates that
+// edit a replace directive. If the batch fails, the module files are restored
+// and each dependency is updated on its own to find the culprit.
+func (u *goUpdater) updateBatch(ctx context.Context, deps []dependency.Dependency, verbose bool, result *UpdateResult) {
+	var batch, single []dependency.Dependency
+	targets := make(map[string][]string)
+	var flags []string
+
+	for _, dep := range deps {
+		if dep.IsMajorUpgrade() || dep.UpdatesReplacement() || dep.DropReplace {
+			single = append(single, dep)
+			continue
+		}
+
+		args, err := u.getArgs(dep)
+		if err == nil {
+			err = u.addTargets(targets, dep, args[len(args)-1])
+		}
+		if err != nil {
+			result.Failed = append(result.Failed, UpdateError{Dependency: dep, Error: err})
+			continue
+		}
+
+		// The flags depend only on the configuration, so they are the same for every dependency
+		flags = args[:len(args)-1]
+		batch = append(batch, dep)
+	}
+
+	if len(batch) > 0 {
+		applied, err := u.applyBatch(ctx, batch, flags, targets, verbose, result)
+		switch {
+		case err != nil:
+			// The batch could not be run safely or left the module half updated, so report every update as failed
+			for _, dep := range batch {
+				result.Failed = append(result.Failed, UpdateError{Dependency: dep, Error: err})
+			}
+		case !applied:
+			single = append(batch, single...)
+		}
+	}
+
+	for _, dep := range single {
+		u.updateOne(ctx, dep, verbose, result)
+	}
+}
+
+// addTargets records the 'go get' target of a dependency for each module directory it is applied to
+func (u *goUpdater) addTargets(targets map[string][]string, dep dependency.Dependency, target string) error {
+	dirs, err := u.dependencyDirs(dep)
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range dirs {
+		targets[dir] = append(targets[dir], target)
+	}
+	return nil
+}
+
+// applyBatch runs the batched 'go get' in every module and, in verify mode,
+// the verify command. It reports whether the batch was applied; when it was
+// not, the module files are restored and nothing is added to the result. An
+// error means the files could not be saved before the batch or restored after
+// it failed.
+func (u *goUpdater) applyBatch(ctx context.Context, batch []dependency.Dependency, flags []string, targets map[string][]string, verbose bool, result *UpdateResult) (bool, error) {
+	verify := u.verifySteps != nil && u.checkBaseline(ctx, verbose) == baselinePassed
+
+	step, err := u.takeSnapshot()
+	if err != nil {
+		return false, fmt.Errorf("saving the module files before the batch update: %w", err)
+	}
+
+	batchErr := u.runBatch(ctx, flags, targets, verbose)
+	if batchErr == nil && verify {
+		batchErr = u.verify(ctx, verbose)
+	}
+	if batchErr != nil {
+		if err := step.Restore(); err != nil {
+			return false, fmt.Errorf("batch update failed: %w; restoring the module files also failed: %v", batchErr, err)
+		}
+		return false, nil
+	}
+
+	result.Updated = append(result.Updated, batch...)
+	switch {
+	case verify:
+		result.Verified = append(result.Verified, batch...)
+	case u.verifySteps != nil:
+		result.Skipped = append(result.Skipped, batch...)
+	}
+	return true, nil
+}
+
+// runBatch runs one 'go get' with all targets in each module directory
+func (u *goUpdater) runBatch(ctx context.Context, flags []string, targets map[string][]string, verbose bool) error {
+	for _, dir := range u.moduleDirs() {
+		if len(targets[dir]) == 0 {
+			continue
+		}
+
+		args := append(append([]string{}, flags...), targets[dir]...)
+		if err := u.run(ctx, dir, "go", args, verbose); err != nil {
+			return u.inModule(dir, err)
+		}
+	}
+	return nil
+}
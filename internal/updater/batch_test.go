@@ -0,0 +1,230 @@
+package updater
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"goup/internal/config"
+	"goup/internal/dependency"
+)
+
+func newBatchUpdater(t *testing.T, cfg *config.Config, runner CommandRunner) *goUpdater {
+	t.Helper()
+	root := t.TempDir()
+	writeFile(t, root+"/go.mod", "module example\n\nrequire github.com/bad/package v1.0.0\n")
+
+	cfg.Batch = true
+	upd := NewGoUpdaterWithRunner(cfg, runner).(*goUpdater)
+	upd.moduleDir = root
+	return upd
+}
+
+func TestUpdateDependenciesBatch(t *testing.T) {
+	runner := &recordingRunner{}
+	upd := newBatchUpdater(t, &config.Config{}, runner)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+		{Path: "golang.org/x/crypto", Version: "v0.14.0", NewVersion: "v0.17.0", Indirect: true, HasUpdate: true},
+	}
+
+	result := upd.UpdateDependencies(context.Background(), deps, false)
+
+	assert.True(t, result.Success)
+	assert.Equal(t, deps, result.Updated)
+	assert.Equal(t, []string{
+		"go get github.com/gin-gonic/gin@v1.9.2 golang.org/x/crypto@v0.17.0",
+	}, runner.commands)
+}
+
+func TestUpdateDependenciesBatchTransitive(t *testing.T) {
+	runner := &recordingRunner{}
+	upd := newBatchUpdater(t, &config.Config{Transitive: true, Policy: dependency.PolicyPatch}, runner)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+		{Path: "golang.org/x/crypto", Version: "v0.17.0", NewVersion: "v0.17.1", HasUpdate: true},
+	}
+
+	result := upd.UpdateDependencies(context.Background(), deps, false)
+
+	assert.True(t, result.Success)
+	assert.Equal(t, []string{
+		"go get -u=patch github.com/gin-gonic/gin@v1.9.2 golang.org/x/crypto@v0.17.1",
+	}, runner.commands)
+}
+
+func TestUpdateDependenciesBatchFallsBackToSingleUpdates(t *testing.T) {
+	runner := &recordingRunner{
+		failOn: map[string]error{
+			"go get github.com/bad/package@v1.1.0 github.com/gin-gonic/gin@v1.9.2": errors.New("conflict"),
+			"go get github.com/bad/package@v1.1.0":                                 errors.New("module not found"),
+		},
+	}
+	upd := newBatchUpdater(t, &config.Config{}, runner)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/bad/package", Version: "v1.0.0", NewVersion: "v1.1.0", HasUpdate: true},
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+	}
+
+	result := upd.UpdateDependencies(context.Background(), deps, false)
+
+	assert.False(t, result.Success)
+	assert.Equal(t, []dependency.Dependency{deps[1]}, result.Updated)
+	require.Len(t, result.Failed, 1)
+	assert.Equal(t, deps[0], result.Failed[0].Dependency)
+	assert.EqualError(t, result.Failed[0].Error, "module not found")
+	assert.Equal(t, []string{
+		"go get github.com/bad/package@v1.1.0 github.com/gin-gonic/gin@v1.9.2",
+		"go get github.com/bad/package@v1.1.0",
+		"go get github.com/gin-gonic/gin@v1.9.2",
+	}, runner.commands)
+}
+
+func TestUpdateDependenciesBatchRestoresFilesBeforeFallback(t *testing.T) {
+	runner := &recordingRunner{}
+	upd := newBatchUpdater(t, &config.Config{}, runner)
+	original := readFile(t, upd.moduleDir+"/go.mod")
+
+	var seen []string
+	upd.commandRunner = runnerFunc(func(name string, args []string, verbose bool) error {
+		seen = append(seen, readFile(t, upd.moduleDir+"/go.mod"))
+		if len(args) > 2 {
+			// The batch writes go.mod before failing
+			writeFile(t, upd.moduleDir+"/go.mod", "module example\n\nrequire github.com/bad/package v1.1.0\n")
+			return errors.New("conflict")
+		}
+		return nil
+	})
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/bad/package", Version: "v1.0.0", NewVersion: "v1.1.0", HasUpdate: true},
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+	}
+
+	result := upd.UpdateDependencies(context.Background(), deps, false)
+
+	assert.True(t, result.Success)
+	assert.Equal(t, []string{original, original, original}, seen, "Every single update must start from the restored go.mod")
+}
+
+func TestUpdateDependenciesBatchSnapshotFails(t *testing.T) {
+	runner := &recordingRunner{}
+	upd := newBatchUpdater(t, &config.Config{}, runner)
+	// go.sum cannot be read, so the batch could not be undone
+	require.NoError(t, os.Mkdir(upd.moduleDir+"/go.sum", 0o755))
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+		{Path: "golang.org/x/crypto", Version: "v0.14.0", NewVersion: "v0.17.0", HasUpdate: true},
+	}
+
+	result := upd.UpdateDependencies(context.Background(), deps, false)
+
+	assert.False(t, result.Success)
+	require.Len(t, result.Failed, 2)
+	assert.ErrorContains(t, result.Failed[0].Error, "saving the module files before the batch update")
+	assert.Empty(t, runner.commands)
+}
+
+func TestUpdateDependenciesBatchAppliesMajorUpgradesSeparately(t *testing.T) {
+	runner := &recordingRunner{}
+	upd := newBatchUpdater(t, &config.Config{}, runner)
+	writeFile(t, upd.moduleDir+"/main.go", "package main\n\nimport \"github.com/foo/bar/v2\"\n\nvar _ = bar.X\n")
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/foo/bar/v2", Version: "v2.5.0", NewPath: "github.com/foo/bar/v4", NewVersion: "v4.0.1", HasUpdate: true},
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+		{Path: "golang.org/x/crypto", Version: "v0.14.0", HasUpdate: true},
+	}
+
+	result := upd.UpdateDependencies(context.Background(), deps, false)
+
+	assert.False(t, result.Success)
+	assert.Equal(t, []dependency.Dependency{deps[1], deps[0]}, result.Updated)
+	require.Len(t, result.Failed, 1)
+	assert.ErrorIs(t, result.Failed[0].Error, ErrNoTargetVersion)
+	assert.Equal(t, []string{
+		"go get github.com/gin-gonic/gin@v1.9.2",
+		"go get github.com/foo/bar/v4@v4.0.1",
+		"go mod edit -droprequire=github.com/foo/bar/v2",
+	}, runner.commands)
+}
+
+func TestUpdateDependenciesBatchVerify(t *testing.T) {
+	runner := &recordingRunner{}
+	upd := newBatchUpdater(t, &config.Config{Verify: true}, runner)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+		{Path: "golang.org/x/crypto", Version: "v0.14.0", NewVersion: "v0.17.0", HasUpdate: true},
+	}
+
+	result := upd.UpdateDependencies(context.Background(), deps, false)
+
+	assert.True(t, result.Success)
+	assert.Equal(t, deps, result.Updated)
+	assert.Equal(t, deps, result.Verified)
+	assert.Equal(t, []string{
+		"go build ./...",
+		"go test ./...",
+		"go get github.com/gin-gonic/gin@v1.9.2 golang.org/x/crypto@v0.17.0",
+		"go build ./...",
+		"go test ./...",
+	}, runner.commands)
+}
+
+func TestUpdateDependenciesBatchVerifyFallsBack(t *testing.T) {
+	deps := []dependency.Dependency{
+		{Path: "github.com/bad/package", Version: "v1.0.0", NewVersion: "v1.1.0", HasUpdate: true},
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+	}
+
+	// The baseline passes, the batch and then the first single update break the check
+	checks := 0
+	runner := runnerFunc(func(name string, args []string, verbose bool) error {
+		if name == "go" {
+			return nil
+		}
+		checks++
+		if checks == 2 || checks == 3 {
+			return errors.New("exit status 2")
+		}
+		return nil
+	})
+	upd := newBatchUpdater(t, &config.Config{Verify: true, VerifyCommand: "make check"}, runner)
+
+	result := upd.UpdateDependencies(context.Background(), deps, false)
+
+	assert.False(t, result.Success)
+	assert.Equal(t, []dependency.Dependency{deps[1]}, result.Updated)
+	assert.Equal(t, []dependency.Dependency{deps[1]}, result.Verified)
+	require.Len(t, result.Reverted, 1)
+	assert.Equal(t, deps[0], result.Reverted[0].Dependency)
+	assert.Equal(t, 4, checks)
+}
+
+func TestWorkspaceUpdateDependenciesBatch(t *testing.T) {
+	ws := newTestWorkspace(t)
+	runner := &recordingRunner{}
+	upd := NewWorkspaceUpdaterWithRunner(&config.Config{Batch: true}, ws, runner)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true, Modules: []string{"api", "worker"}},
+		{Path: "golang.org/x/crypto", Version: "v0.14.0", NewVersion: "v0.17.0", HasUpdate: true, Modules: []string{"worker"}},
+	}
+
+	result := upd.UpdateDependencies(context.Background(), deps, false)
+
+	assert.True(t, result.Success)
+	assert.Equal(t, []string{
+		"[api] go get github.com/gin-gonic/gin@v1.9.2",
+		"[worker] go get github.com/gin-gonic/gin@v1.9.2 golang.org/x/crypto@v0.17.0",
+	}, runner.commands)
+}
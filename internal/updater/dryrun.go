@@ -0,0 +1,112 @@
+package updater
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"goup/internal/dependency"
+	"goup/internal/diff"
+	"goup/internal/snapshot"
+)
+
+// ErrDryRunWorkspace is returned when a dry run is requested in workspace
+// mode, where the go command does not accept -modfile
+var ErrDryRunWorkspace = errors.New("--dry-run is not supported in workspace mode")
+
+// Preview applies the updates and go mod tidy to copies of go.mod and go.sum
+// through the -modfile flag of the go command, then diffs the copies against
+// the originals. The imports of major upgrades are not rewritten in a dry
+// run, so go mod tidy is skipped when there are any: it would restore the
+// old major version.
+func (u *goUpdater) Preview(ctx context.Context, deps []dependency.Dependency, verbose bool) (Preview, error) {
+	if u.workspace != nil {
+		return Preview{}, ErrDryRunWorkspace
+	}
+
+	tmp, err := os.MkdirTemp("", "goup-dry-run-")
+	if err != nil {
+		return Preview{}, fmt.Errorf("creating dry run directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	originals, err := copyModuleFiles(u.moduleDir, tmp)
+	if err != nil {
+		return Preview{}, err
+	}
+
+	dry := &goUpdater{
+		commandRunner: u.commandRunner,
+		transitive:    u.transitive,
+		batch:         u.batch,
+		policy:        u.policy,
+		moduleDir:     u.moduleDir,
+		modfile:       filepath.Join(tmp, "go.mod"),
+	}
+
+	preview := Preview{Result: dry.UpdateDependencies(ctx, deps, verbose)}
+	if slices.ContainsFunc(preview.Result.Updated, dependency.Dependency.IsMajorUpgrade) {
+		preview.TidySkipped = true
+	} else {
+		preview.Tidy = dry.RunModTidy(ctx, verbose)
+	}
+
+	preview.Diff, err = diffModuleFiles(originals, tmp)
+	if err != nil {
+		return Preview{}, err
+	}
+	return preview, nil
+}
+
+// withModfile points a go command at the copy of go.mod of a dry run. go mod
+// edit takes the file as an argument instead of the flag.
+func withModfile(args []string, modfile string) []string {
+	if len(args) >= 2 && args[0] == "mod" && args[1] == "edit" {
+		return append(slices.Clone(args), modfile)
+	}
+
+	n := 1 // go get -modfile=...
+	if len(args) >= 2 && args[0] == "mod" {
+		n = 2 // go mod tidy -modfile=...
+	}
+	return slices.Concat(args[:n], []string{"-modfile=" + modfile}, args[n:])
+}
+
+// copyModuleFiles copies go.mod and, if the module has one, go.sum from dir
+// to tmp and returns their contents by file name
+func copyModuleFiles(dir, tmp string) (map[string]string, error) {
+	originals := make(map[string]string)
+	for _, name := range snapshot.ModuleFiles {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if errors.Is(err, os.ErrNotExist) && name != "go.mod" {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", name, err)
+		}
+
+		if err := os.WriteFile(filepath.Join(tmp, name), data, 0o644); err != nil {
+			return nil, fmt.Errorf("copying %s: %w", name, err)
+		}
+		originals[name] = string(data)
+	}
+	return originals, nil
+}
+
+// diffModuleFiles returns the unified diff of the module files in tmp
+// against their original contents. A file missing on either side diffs as
+// empty.
+func diffModuleFiles(originals map[string]string, tmp string) (string, error) {
+	var result string
+	for _, name := range snapshot.ModuleFiles {
+		data, err := os.ReadFile(filepath.Join(tmp, name))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("reading the updated %s: %w", name, err)
+		}
+		result += diff.Unified(name, name, originals[name], string(data), 3)
+	}
+	return result, nil
+}
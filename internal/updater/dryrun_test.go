@@ -0,0 +1,161 @@
+package updater
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"goup/internal/config"
+	"goup/internal/dependency"
+)
+
+// modfileRunner records the commands with the copy of go.mod replaced by
+// <modfile>, and lets go get write the copy like the go command would
+type modfileRunner struct {
+	t        *testing.T
+	modfile  string
+	commands []string
+	get      map[string]string // Files written next to the copy of go.mod by go get, by name
+}
+
+func (r *modfileRunner) Run(ctx context.Context, name string, args []string, verbose bool) error {
+	for _, arg := range args {
+		if modfile, ok := strings.CutPrefix(arg, "-modfile="); ok {
+			r.modfile = modfile
+		} else if strings.HasSuffix(arg, "go.mod") {
+			r.modfile = arg
+		}
+	}
+
+	command := name + " " + strings.Join(args, " ")
+	if r.modfile != "" {
+		command = strings.ReplaceAll(command, r.modfile, "<modfile>")
+	}
+	r.commands = append(r.commands, command)
+
+	if len(args) > 0 && args[0] == "get" {
+		for file, content := range r.get {
+			writeFile(r.t, filepath.Join(filepath.Dir(r.modfile), file), content)
+		}
+	}
+	return nil
+}
+
+func (r *modfileRunner) RunInDir(ctx context.Context, dir, name string, args []string, verbose bool) error {
+	return r.Run(ctx, name, args, verbose)
+}
+
+func TestPreview(t *testing.T) {
+	root := t.TempDir()
+	goMod := "module example\n\ngo 1.21\n\nrequire github.com/gin-gonic/gin v1.9.1\n"
+	goSum := "github.com/gin-gonic/gin v1.9.1 h1:old=\n"
+	writeFile(t, root+"/go.mod", goMod)
+	writeFile(t, root+"/go.sum", goSum)
+
+	runner := &modfileRunner{t: t, get: map[string]string{
+		"go.mod": "module example\n\ngo 1.21\n\nrequire (\n\tgithub.com/gin-gonic/gin v1.9.2\n\tgolang.org/x/net v0.17.0 // indirect\n)\n",
+		"go.sum": "github.com/gin-gonic/gin v1.9.2 h1:new=\ngolang.org/x/net v0.17.0 h1:net=\n",
+	}}
+	upd := &goUpdater{commandRunner: runner, moduleDir: root}
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+	}
+	preview, err := upd.Preview(context.Background(), deps, false)
+
+	require.NoError(t, err)
+	assert.Equal(t, deps, preview.Result.Updated)
+	assert.NoError(t, preview.Tidy)
+	assert.False(t, preview.TidySkipped)
+	assert.Equal(t, []string{
+		"go get -modfile=<modfile> github.com/gin-gonic/gin@v1.9.2",
+		"go mod tidy -modfile=<modfile>",
+	}, runner.commands)
+	assert.Equal(t, `--- go.mod
++++ go.mod
+@@ -2,4 +2,7 @@
+ 
+ go 1.21
+ 
+-require github.com/gin-gonic/gin v1.9.1
++require (
++	github.com/gin-gonic/gin v1.9.2
++	golang.org/x/net v0.17.0 // indirect
++)
+--- go.sum
++++ go.sum
+@@ -1 +1,2 @@
+-github.com/gin-gonic/gin v1.9.1 h1:old=
++github.com/gin-gonic/gin v1.9.2 h1:new=
++golang.org/x/net v0.17.0 h1:net=
+`, preview.Diff)
+
+	assert.Equal(t, goMod, readFile(t, root+"/go.mod"), "The module must be left untouched")
+	assert.Equal(t, goSum, readFile(t, root+"/go.sum"))
+	_, err = os.Stat(runner.modfile)
+	assert.ErrorIs(t, err, os.ErrNotExist, "The copies are removed")
+}
+
+func TestPreviewWithoutGoSum(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root+"/go.mod", "module example\n")
+
+	runner := &modfileRunner{t: t}
+	upd := &goUpdater{commandRunner: runner, moduleDir: root}
+
+	preview, err := upd.Preview(context.Background(), nil, false)
+
+	require.NoError(t, err)
+	assert.Empty(t, preview.Diff)
+	assert.NoFileExists(t, root+"/go.sum")
+}
+
+func TestPreviewMajorUpgradeSkipsTidy(t *testing.T) {
+	root := t.TempDir()
+	source := "package main\n\nimport \"github.com/foo/bar/v2\"\n\nvar _ = bar.X\n"
+	writeFile(t, root+"/go.mod", "module example\n\nrequire github.com/foo/bar/v2 v2.5.0\n")
+	writeFile(t, root+"/main.go", source)
+
+	runner := &modfileRunner{t: t}
+	upd := &goUpdater{commandRunner: runner, moduleDir: root}
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/foo/bar/v2", Version: "v2.5.0", NewPath: "github.com/foo/bar/v4", NewVersion: "v4.0.1", HasUpdate: true},
+	}
+	preview, err := upd.Preview(context.Background(), deps, false)
+
+	require.NoError(t, err)
+	assert.True(t, preview.TidySkipped)
+	assert.Equal(t, []string{
+		"go get -modfile=<modfile> github.com/foo/bar/v4@v4.0.1",
+		"go mod edit -droprequire=github.com/foo/bar/v2 <modfile>",
+	}, runner.commands)
+	assert.Equal(t, source, readFile(t, root+"/main.go"), "Imports are not rewritten in a dry run")
+}
+
+func TestPreviewModuleUpdater(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root+"/api/go.mod", "module example/api\n")
+
+	runner := &recordingRunner{}
+	upd := NewModuleUpdaterWithRunner(&config.Config{}, root+"/api", runner)
+
+	_, err := upd.Preview(context.Background(), nil, false)
+
+	require.NoError(t, err)
+	require.Len(t, runner.commands, 1)
+	assert.Regexp(t, `^\[api\] go mod tidy -modfile=.*go\.mod$`, runner.commands[0])
+}
+
+func TestPreviewWorkspace(t *testing.T) {
+	upd := NewWorkspaceUpdaterWithRunner(&config.Config{}, &dependency.Workspace{Root: t.TempDir()}, &recordingRunner{})
+
+	_, err := upd.Preview(context.Background(), nil, false)
+
+	assert.ErrorIs(t, err, ErrDryRunWorkspace)
+}
@@ -0,0 +1,63 @@
+package updater
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/mod/modfile"
+)
+
+// ErrExcludeWorkspace is returned when an exclusion is requested in
+// workspace mode, where it is unclear which module should declare it
+var ErrExcludeWorkspace = errors.New("goup exclude is not supported in workspace mode")
+
+// Exclude adds an exclude directive for a module version to go.mod, with
+// comment at the end of the line. go.mod is edited in place, keeping its
+// layout and comments, after a snapshot is saved for --rollback. It reports
+// false, leaving the files and the last snapshot alone, if the version was
+// already excluded.
+func (u *goUpdater) Exclude(path, version, comment string) (bool, error) {
+	if u.workspace != nil {
+		return false, ErrExcludeWorkspace
+	}
+
+	name := filepath.Join(u.moduleDir, "go.mod")
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return false, fmt.Errorf("reading go.mod: %w", err)
+	}
+
+	f, err := modfile.Parse(name, data, nil)
+	if err != nil {
+		return false, fmt.Errorf("parsing go.mod: %w", err)
+	}
+
+	for _, x := range f.Exclude {
+		if x.Mod.Path == path && x.Mod.Version == version {
+			return false, nil
+		}
+	}
+
+	if err := f.AddExclude(path, version); err != nil {
+		return false, err
+	}
+	if comment != "" {
+		line := f.Exclude[len(f.Exclude)-1].Syntax
+		line.Suffix = append(line.Suffix, modfile.Comment{Token: "// " + comment, Suffix: true})
+	}
+
+	out, err := f.Format()
+	if err != nil {
+		return false, fmt.Errorf("formatting go.mod: %w", err)
+	}
+
+	if err := u.Snapshot(); err != nil {
+		return false, fmt.Errorf("saving snapshot of go.mod and go.sum: %w", err)
+	}
+	if err := os.WriteFile(name, out, 0o644); err != nil {
+		return false, fmt.Errorf("writing go.mod: %w", err)
+	}
+	return true, nil
+}
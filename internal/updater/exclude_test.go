@@ -0,0 +1,82 @@
+package updater
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"goup/internal/config"
+	"goup/internal/dependency"
+	"goup/internal/snapshot"
+)
+
+func TestExclude(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root+"/go.mod", `module example.com/app
+
+go 1.21
+
+// Shared by every service
+require example.com/lib v1.0.0
+`)
+	upd := NewModuleUpdaterWithRunner(&config.Config{}, root, &recordingRunner{}).(*goUpdater)
+	upd.store = snapshot.NewStoreWithRoot(t.TempDir())
+
+	added, err := upd.Exclude("example.com/lib", "v1.2.0", "Panics on startup")
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	added, err = upd.Exclude("example.com/lib", "v1.3.0", "")
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	added, err = upd.Exclude("example.com/lib", "v1.2.0", "Again")
+	require.NoError(t, err)
+	assert.False(t, added)
+
+	assert.Equal(t, `module example.com/app
+
+go 1.21
+
+// Shared by every service
+require example.com/lib v1.0.0
+
+exclude (
+	example.com/lib v1.2.0 // Panics on startup
+	example.com/lib v1.3.0
+)
+`, readFile(t, root+"/go.mod"))
+
+	// The snapshot was taken before the last exclusion that changed go.mod
+	require.NoError(t, upd.Rollback())
+	assert.Equal(t, `module example.com/app
+
+go 1.21
+
+// Shared by every service
+require example.com/lib v1.0.0
+
+exclude example.com/lib v1.2.0 // Panics on startup
+`, readFile(t, root+"/go.mod"))
+}
+
+func TestExcludeInvalidVersion(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root+"/go.mod", "module example.com/app\n")
+	upd := NewModuleUpdaterWithRunner(&config.Config{}, root, &recordingRunner{}).(*goUpdater)
+	upd.store = snapshot.NewStoreWithRoot(t.TempDir())
+
+	_, err := upd.Exclude("example.com/lib", "latest", "")
+
+	assert.Error(t, err)
+	assert.Equal(t, "module example.com/app\n", readFile(t, root+"/go.mod"))
+}
+
+func TestExcludeWorkspace(t *testing.T) {
+	upd := NewWorkspaceUpdaterWithRunner(&config.Config{}, &dependency.Workspace{Root: t.TempDir()}, &recordingRunner{})
+
+	_, err := upd.Exclude("example.com/lib", "v1.2.0", "")
+
+	assert.ErrorIs(t, err, ErrExcludeWorkspace)
+}
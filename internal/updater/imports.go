@@ -0,0 +1,200 @@
+package updater
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// rewriteImports replaces imports of oldPath and its packages with newPath in
+// every .go file of the module rooted at root. Vendored code, testdata and
+// nested modules are left untouched, as are the imports of packages belonging
+// to other modules under oldPath, like its /vN major versions. backup is
+// called with each file before it is changed. It returns the number of files
+// changed.
+func rewriteImports(root, oldPath, newPath string, backup func(path string) error) (int, error) {
+	changed := 0
+	modules := requiredModules(root)
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if path != root && skipDir(path, d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		rewritten, err := rewriteFileImports(path, oldPath, newPath, modules, backup)
+		if err != nil {
+			return err
+		}
+		if rewritten {
+			changed++
+		}
+		return nil
+	})
+	if err != nil {
+		return changed, fmt.Errorf("rewriting imports of %s: %w", oldPath, err)
+	}
+
+	return changed, nil
+}
+
+// skipDir reports whether a directory is outside the module's own source
+func skipDir(path, name string) bool {
+	if name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+		return true
+	}
+
+	// A nested go.mod starts a different module
+	_, err := os.Stat(filepath.Join(path, "go.mod"))
+	return err == nil
+}
+
+// rewriteFileImports rewrites the import paths of a single file in place,
+// touching only the import path literals so formatting is preserved
+func rewriteFileImports(path, oldPath, newPath string, modules []string, backup func(path string) error) (bool, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ImportsOnly)
+	if err != nil {
+		return false, err
+	}
+
+	type edit struct {
+		start, end int
+		value      string
+	}
+
+	var edits []edit
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+
+		newImport, ok := replaceModulePath(importPath, oldPath, newPath, modules)
+		if !ok {
+			continue
+		}
+
+		edits = append(edits, edit{
+			start: fset.Position(spec.Path.Pos()).Offset,
+			end:   fset.Position(spec.Path.End()).Offset,
+			value: strconv.Quote(newImport),
+		})
+	}
+
+	if len(edits) == 0 {
+		return false, nil
+	}
+
+	// Apply from the end so earlier offsets stay valid
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	for _, e := range edits {
+		src = append(src[:e.start], append([]byte(e.value), src[e.end:]...)...)
+	}
+
+	if err := backup(path); err != nil {
+		return false, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+
+	return true, os.WriteFile(path, src, info.Mode().Perm())
+}
+
+// replaceModulePath maps an import path of a package of the oldPath module to
+// the same package inside newPath. Packages of other modules whose path starts
+// with oldPath, among the required modules or under a /vN major version, are
+// not part of it.
+func replaceModulePath(importPath, oldPath, newPath string, modules []string) (string, bool) {
+	if importPath == newPath || strings.HasPrefix(importPath, newPath+"/") {
+		// Already imports the new major version
+		return "", false
+	}
+
+	if owner := owningModule(importPath, modules); owner != "" && owner != oldPath {
+		return "", false
+	}
+
+	if importPath == oldPath {
+		return newPath, true
+	}
+
+	rest, ok := strings.CutPrefix(importPath, oldPath+"/")
+	if !ok {
+		return "", false
+	}
+
+	first, _, _ := strings.Cut(rest, "/")
+	if isMajorSuffix(first) {
+		return "", false
+	}
+	return newPath + "/" + rest, true
+}
+
+// owningModule returns the longest module path containing the package, or an
+// empty string if none of the modules does
+func owningModule(importPath string, modules []string) string {
+	owner := ""
+	for _, path := range modules {
+		if (importPath == path || strings.HasPrefix(importPath, path+"/")) && len(path) > len(owner) {
+			owner = path
+		}
+	}
+	return owner
+}
+
+// isMajorSuffix reports whether a path element is a major version suffix, v2 or above
+func isMajorSuffix(elem string) bool {
+	if len(elem) < 2 || elem[0] != 'v' {
+		return false
+	}
+	n, err := strconv.Atoi(elem[1:])
+	return err == nil && n >= 2 && elem[1] != '0'
+}
+
+// requiredModules returns the module paths required by the go.mod of the
+// module rooted at root, which lists the whole build list since Go 1.17. It
+// returns nil when go.mod cannot be read.
+func requiredModules(root string) []string {
+	data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return nil
+	}
+
+	file, err := modfile.ParseLax("go.mod", data, nil)
+	if err != nil {
+		return nil
+	}
+
+	modules := make([]string, 0, len(file.Require))
+	for _, req := range file.Require {
+		modules = append(modules, req.Mod.Path)
+	}
+	return modules
+}
@@ -0,0 +1,213 @@
+package updater
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func noBackup(path string) error { return nil }
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestRewriteImports(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, filepath.Join(root, "go.mod"), "module example.com/app\n")
+	writeFile(t, filepath.Join(root, "main.go"), `package main
+
+import (
+	"fmt"
+
+	bar "github.com/foo/bar/v2"
+	"github.com/foo/bar/v2/sub/pkg"
+	"github.com/foo/barbell"
+)
+
+func main() { fmt.Println(bar.X, pkg.Y, barbell.Z) }
+`)
+	writeFile(t, filepath.Join(root, "internal", "single.go"), `package internal
+
+import "github.com/foo/bar/v2"
+
+var _ = bar.X
+`)
+	untouched := `package vendored
+
+import "github.com/foo/bar/v2"
+`
+	writeFile(t, filepath.Join(root, "vendor", "x", "x.go"), untouched)
+	writeFile(t, filepath.Join(root, "testdata", "x.go"), untouched)
+	writeFile(t, filepath.Join(root, "nested", "go.mod"), "module example.com/nested\n")
+	writeFile(t, filepath.Join(root, "nested", "x.go"), untouched)
+
+	changed, err := rewriteImports(root, "github.com/foo/bar/v2", "github.com/foo/bar/v4", noBackup)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, changed)
+	assert.Equal(t, `package main
+
+import (
+	"fmt"
+
+	bar "github.com/foo/bar/v4"
+	"github.com/foo/bar/v4/sub/pkg"
+	"github.com/foo/barbell"
+)
+
+func main() { fmt.Println(bar.X, pkg.Y, barbell.Z) }
+`, readFile(t, filepath.Join(root, "main.go")))
+	assert.Equal(t, `package internal
+
+import "github.com/foo/bar/v4"
+
+var _ = bar.X
+`, readFile(t, filepath.Join(root, "internal", "single.go")))
+
+	for _, dir := range []string{"vendor/x", "testdata", "nested"} {
+		matches, err := filepath.Glob(filepath.Join(root, dir, "*.go"))
+		require.NoError(t, err)
+		for _, match := range matches {
+			assert.Equal(t, untouched, readFile(t, match), "%s should not be rewritten", match)
+		}
+	}
+}
+
+func TestRewriteImportsFromUnversionedPath(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, filepath.Join(root, "a.go"), `package a
+
+import (
+	"github.com/foo/bar"
+	"github.com/foo/bar/v2/already"
+)
+`)
+
+	changed, err := rewriteImports(root, "github.com/foo/bar", "github.com/foo/bar/v2", noBackup)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, changed)
+	assert.Equal(t, `package a
+
+import (
+	"github.com/foo/bar/v2"
+	"github.com/foo/bar/v2/already"
+)
+`, readFile(t, filepath.Join(root, "a.go")))
+}
+
+func TestRewriteImportsInvalidFile(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "broken.go"), "this is not go")
+
+	_, err := rewriteImports(root, "github.com/foo/bar", "github.com/foo/bar/v2", noBackup)
+
+	assert.ErrorContains(t, err, "rewriting imports of github.com/foo/bar")
+}
+
+func TestRewriteImportsSkipsOtherMajorVersions(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, filepath.Join(root, "a.go"), `package a
+
+import (
+	"github.com/foo/bar/sub"
+	"github.com/foo/bar/v2/x"
+	"github.com/foo/bar/v10"
+)
+`)
+
+	changed, err := rewriteImports(root, "github.com/foo/bar", "github.com/foo/bar/v3", noBackup)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, changed)
+	assert.Equal(t, `package a
+
+import (
+	"github.com/foo/bar/v3/sub"
+	"github.com/foo/bar/v2/x"
+	"github.com/foo/bar/v10"
+)
+`, readFile(t, filepath.Join(root, "a.go")))
+}
+
+func TestRewriteImportsSkipsNestedModules(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, filepath.Join(root, "go.mod"), `module example.com/app
+
+go 1.22
+
+require (
+	github.com/foo/bar v1.5.0
+	github.com/foo/bar/otel v0.3.0
+	github.com/foo/bar/v3 v3.0.0
+)
+`)
+	writeFile(t, filepath.Join(root, "a.go"), `package a
+
+import (
+	"github.com/foo/bar/otel"
+	"github.com/foo/bar/otel/trace"
+	"github.com/foo/bar/other"
+	"github.com/foo/bar"
+)
+`)
+
+	changed, err := rewriteImports(root, "github.com/foo/bar", "github.com/foo/bar/v3", noBackup)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, changed)
+	assert.Equal(t, `package a
+
+import (
+	"github.com/foo/bar/otel"
+	"github.com/foo/bar/otel/trace"
+	"github.com/foo/bar/v3/other"
+	"github.com/foo/bar/v3"
+)
+`, readFile(t, filepath.Join(root, "a.go")))
+}
+
+func TestReplaceModulePath(t *testing.T) {
+	modules := []string{"github.com/foo/bar", "github.com/foo/bar/otel"}
+
+	tests := []struct {
+		importPath string
+		want       string
+		ok         bool
+	}{
+		{importPath: "github.com/foo/bar", want: "github.com/foo/bar/v3", ok: true},
+		{importPath: "github.com/foo/bar/pkg/x", want: "github.com/foo/bar/v3/pkg/x", ok: true},
+		{importPath: "github.com/foo/bar/v2/x"},
+		{importPath: "github.com/foo/bar/v3/x"},
+		{importPath: "github.com/foo/bar/otel/trace"},
+		{importPath: "github.com/foo/barbell"},
+		// v0 and v1 are not major version suffixes
+		{importPath: "github.com/foo/bar/v1", want: "github.com/foo/bar/v3/v1", ok: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.importPath, func(t *testing.T) {
+			got, ok := replaceModulePath(tt.importPath, "github.com/foo/bar", "github.com/foo/bar/v3", modules)
+
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
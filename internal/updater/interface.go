@@ -1,12 +1,19 @@
 package updater
 
-import "goup/internal/dependency"
+import (
+	"context"
+
+	"goup/internal/dependency"
+)
 
 // UpdateResult contains the result of an update operation
 type UpdateResult struct {
-	Updated []dependency.Dependency
-	Failed  []UpdateError
-	Success bool
+	Updated  []dependency.Dependency
+	Failed   []UpdateError
+	Verified []dependency.Dependency // Updated and passed the verification command
+	Reverted []UpdateError           // Failed the verification command and were reverted
+	Skipped  []dependency.Dependency // Updated without verification because the module failed it beforehand
+	Success  bool
 }
 
 // UpdateError represents an error that occurred during update
@@ -15,16 +22,36 @@ type UpdateError struct {
 	Error      error
 }
 
+// Preview is the outcome of a dry run
+type Preview struct {
+	Result      UpdateResult
+	Tidy        error  // go mod tidy failure, nil if it succeeded or was skipped
+	TidySkipped bool   // go mod tidy did not run because major upgrades were selected
+	Diff        string // Unified diff of go.mod and go.sum, empty if they would not change
+}
+
 // Updater defines the interface for updating dependencies
 type Updater interface {
 	// UpdateDependencies updates the specified dependencies
-	UpdateDependencies(deps []dependency.Dependency, verbose bool) UpdateResult
+	UpdateDependencies(ctx context.Context, deps []dependency.Dependency, verbose bool) UpdateResult
 	// RunModTidy runs go mod tidy to clean up the module
-	RunModTidy(verbose bool) error
+	RunModTidy(ctx context.Context, verbose bool) error
+	// Preview applies the updates to a copy of go.mod and go.sum and returns
+	// how they would change, leaving the module untouched
+	Preview(ctx context.Context, deps []dependency.Dependency, verbose bool) (Preview, error)
+	// Exclude saves a snapshot and adds an exclude directive with a comment
+	// to go.mod, reporting false if the module version was already excluded
+	Exclude(path, version, comment string) (bool, error)
+	// Snapshot saves go.mod and go.sum so the update can be rolled back
+	Snapshot() error
+	// Rollback restores the files saved by the last snapshot of the module
+	Rollback() error
 }
 
 // CommandRunner defines the interface for running system commands
 type CommandRunner interface {
 	// Run executes a command and returns the result
-	Run(name string, args []string, verbose bool) error
-}
\ No newline at end of file
+	Run(ctx context.Context, name string, args []string, verbose bool) error
+	// RunInDir executes a command in the given directory
+	RunInDir(ctx context.Context, dir, name string, args []string, verbose bool) error
+}
@@ -0,0 +1,22 @@
+//go:build !unix
+
+package updater
+
+import (
+	"os"
+	"os/exec"
+)
+
+// detach is a no-op where process groups are not available
+func detach(cmd *exec.Cmd) {}
+
+// interrupt stops a command. Interrupts cannot be sent to other processes on
+// every platform, so the command is killed.
+func interrupt(process *os.Process) error {
+	return process.Kill()
+}
+
+// kill stops a command at once
+func kill(process *os.Process) error {
+	return process.Kill()
+}
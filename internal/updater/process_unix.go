@@ -0,0 +1,26 @@
+//go:build unix
+
+package updater
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// detach starts the command in its own process group, so the interrupt sent
+// by the terminal on Ctrl-C only reaches goup
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// interrupt asks a command to stop
+func interrupt(process *os.Process) error {
+	return process.Signal(os.Interrupt)
+}
+
+// kill stops a command at once, with the processes it started in its
+// process group
+func kill(process *os.Process) error {
+	return syscall.Kill(-process.Pid, syscall.SIGKILL)
+}
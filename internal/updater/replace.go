@@ -0,0 +1,34 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+
+	"goup/internal/dependency"
+)
+
+// updateReplacement points the replace directive of a dependency to the new
+// version of its fork. The require line is left alone: 'go get' would only
+// change the version that is replaced.
+func (u *goUpdater) updateReplacement(ctx context.Context, dep dependency.Dependency, verbose bool) error {
+	if dep.NewVersion == "" {
+		return fmt.Errorf("%s: %w", dep.Path, ErrNoTargetVersion)
+	}
+
+	arg := "-replace=" + replacedModule(dep) + "=" + dep.Replace.Path + "@" + dep.NewVersion
+	return u.run(ctx, u.moduleDir, "go", []string{"mod", "edit", arg}, verbose)
+}
+
+// dropReplace removes the replace directive of a dependency from the module in dir
+func (u *goUpdater) dropReplace(ctx context.Context, dir string, dep dependency.Dependency, verbose bool) error {
+	return u.run(ctx, dir, "go", []string{"mod", "edit", "-dropreplace=" + replacedModule(dep)}, verbose)
+}
+
+// replacedModule returns the left-hand side of the replace directive of a
+// dependency, with the version it is restricted to if any
+func replacedModule(dep dependency.Dependency) string {
+	if dep.Replace.OldVersion == "" {
+		return dep.Path
+	}
+	return dep.Path + "@" + dep.Replace.OldVersion
+}
@@ -0,0 +1,85 @@
+package updater
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"goup/internal/config"
+	"goup/internal/dependency"
+)
+
+func TestUpdateDependenciesReplacement(t *testing.T) {
+	runner := &recordingRunner{}
+	upd := NewGoUpdaterWithRunner(&config.Config{Batch: true}, runner)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/foo/bar", Version: "v1.2.0", NewVersion: "v1.3.0", HasUpdate: true,
+			Replace: &dependency.Replace{Path: "github.com/me/bar", Version: "v1.2.0"}},
+		{Path: "github.com/foo/baz", Version: "v0.1.0", NewVersion: "v0.2.0", HasUpdate: true,
+			Replace: &dependency.Replace{OldVersion: "v0.1.0", Path: "github.com/me/baz", Version: "v0.1.1"}},
+	}
+
+	result := upd.UpdateDependencies(context.Background(), deps, false)
+
+	assert.True(t, result.Success)
+	assert.Equal(t, deps, result.Updated)
+	assert.Equal(t, []string{
+		"go mod edit -replace=github.com/foo/bar=github.com/me/bar@v1.3.0",
+		"go mod edit -replace=github.com/foo/baz@v0.1.0=github.com/me/baz@v0.2.0",
+	}, runner.commands)
+}
+
+func TestUpdateDependenciesReplacementWithoutNewVersion(t *testing.T) {
+	runner := &recordingRunner{}
+	upd := NewGoUpdaterWithRunner(&config.Config{}, runner)
+
+	deps := []dependency.Dependency{{Path: "github.com/foo/bar", Version: "v1.2.0", HasUpdate: true,
+		Replace: &dependency.Replace{Path: "github.com/me/bar", Version: "v1.2.0"}}}
+
+	result := upd.UpdateDependencies(context.Background(), deps, false)
+
+	require.Len(t, result.Failed, 1)
+	assert.ErrorIs(t, result.Failed[0].Error, ErrNoTargetVersion)
+	assert.Empty(t, runner.commands)
+}
+
+func TestUpdateDependenciesDropReplace(t *testing.T) {
+	runner := &recordingRunner{}
+	upd := NewGoUpdaterWithRunner(&config.Config{}, runner)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/foo/bar", Version: "v1.2.0", NewVersion: "v1.2.3", HasUpdate: true, DropReplace: true,
+			Replace: &dependency.Replace{Path: "github.com/me/bar", Version: "v1.2.1-fix"}},
+		{Path: "github.com/foo/baz", Version: "v0.1.0", NewVersion: "v0.2.0", HasUpdate: true, DropReplace: true,
+			Replace: &dependency.Replace{OldVersion: "v0.1.0", Path: "github.com/me/baz", Version: "v0.1.1"}},
+	}
+
+	result := upd.UpdateDependencies(context.Background(), deps, false)
+
+	assert.True(t, result.Success)
+	assert.Equal(t, []string{
+		"go mod edit -dropreplace=github.com/foo/bar",
+		"go get github.com/foo/bar@v1.2.3",
+		"go mod edit -dropreplace=github.com/foo/baz@v0.1.0",
+		"go get github.com/foo/baz@v0.2.0",
+	}, runner.commands)
+}
+
+func TestUpdateDependenciesDropReplaceFails(t *testing.T) {
+	runner := &recordingRunner{failOn: map[string]error{
+		"go mod edit -dropreplace=github.com/foo/bar": assert.AnError,
+	}}
+	upd := NewGoUpdaterWithRunner(&config.Config{}, runner)
+
+	deps := []dependency.Dependency{{Path: "github.com/foo/bar", Version: "v1.2.0", NewVersion: "v1.2.3", HasUpdate: true, DropReplace: true,
+		Replace: &dependency.Replace{Path: "github.com/me/bar", Version: "v1.2.1-fix"}}}
+
+	result := upd.UpdateDependencies(context.Background(), deps, false)
+
+	require.Len(t, result.Failed, 1)
+	assert.ErrorIs(t, result.Failed[0].Error, assert.AnError)
+	assert.Equal(t, []string{"go mod edit -dropreplace=github.com/foo/bar"}, runner.commands)
+}
@@ -1,80 +1,452 @@
 package updater
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
 
+	"goup/internal/config"
 	"goup/internal/dependency"
+	"goup/internal/snapshot"
 )
 
+// ErrNoTargetVersion is returned when a dependency has no version to pin to
+var ErrNoTargetVersion = errors.New("no target version available")
+
 // goUpdater implements the Updater interface using Go commands
 type goUpdater struct {
 	commandRunner CommandRunner
+	transitive    bool
+	batch         bool // Apply all updates with one 'go get' per module
+	policy        dependency.Policy
+	moduleDir     string
+	workspace     *dependency.Workspace // nil outside workspace mode
+	store         snapshot.Store
+	snapshot      *snapshot.Snapshot // Taken by Snapshot, nil until then
+	verifySteps   [][]string         // Verify command steps, nil unless verify mode is enabled
+	baseline      baseline           // Whether the module passed the verify command before any update
+	step          *snapshot.Snapshot // Files touched by the update being verified
+	modfile       string             // Copy of go.mod the go commands work on in a dry run, empty otherwise
 }
 
 // NewGoUpdater creates a new Go updater
-func NewGoUpdater() Updater {
-	return &goUpdater{
-		commandRunner: &systemCommandRunner{},
-	}
+func NewGoUpdater(cfg *config.Config) Updater {
+	return NewGoUpdaterWithRunner(cfg, NewCommandRunner(cfg.CommandTimeout))
 }
 
 // NewGoUpdaterWithRunner creates a new Go updater with a custom command runner
-func NewGoUpdaterWithRunner(runner CommandRunner) Updater {
-	return &goUpdater{
+func NewGoUpdaterWithRunner(cfg *config.Config, runner CommandRunner) Updater {
+	u := &goUpdater{
 		commandRunner: runner,
+		transitive:    cfg.Transitive,
+		batch:         cfg.Batch,
+		policy:        cfg.Policy,
+		moduleDir:     ".",
+		store:         snapshot.NewStore(),
 	}
+
+	if cfg.Verify {
+		// Validate has already rejected malformed commands
+		u.verifySteps, _ = cfg.VerifySteps()
+	}
+
+	return u
+}
+
+// NewWorkspaceUpdater creates a Go updater that applies each update to the
+// workspace modules requiring the dependency
+func NewWorkspaceUpdater(cfg *config.Config, ws *dependency.Workspace) Updater {
+	return NewWorkspaceUpdaterWithRunner(cfg, ws, NewCommandRunner(cfg.CommandTimeout))
+}
+
+// NewWorkspaceUpdaterWithRunner creates a workspace updater with a custom command runner
+func NewWorkspaceUpdaterWithRunner(cfg *config.Config, ws *dependency.Workspace, runner CommandRunner) Updater {
+	u := NewGoUpdaterWithRunner(cfg, runner).(*goUpdater)
+	u.moduleDir = ws.Root
+	u.workspace = ws
+	return u
 }
 
-// UpdateDependencies updates the specified dependencies individually
-func (u *goUpdater) UpdateDependencies(deps []dependency.Dependency, verbose bool) UpdateResult {
+// NewModuleUpdater creates a Go updater for the module in dir, which runs its
+// commands there instead of in the current directory
+func NewModuleUpdater(cfg *config.Config, dir string) Updater {
+	return NewModuleUpdaterWithRunner(cfg, dir, NewCommandRunner(cfg.CommandTimeout))
+}
+
+// NewModuleUpdaterWithRunner creates a module updater with a custom command runner
+func NewModuleUpdaterWithRunner(cfg *config.Config, dir string, runner CommandRunner) Updater {
+	u := NewGoUpdaterWithRunner(cfg, &dirRunner{dir: dir, runner: runner}).(*goUpdater)
+	u.moduleDir = dir
+	return u
+}
+
+// UpdateDependencies updates the specified dependencies individually or, in
+// batch mode, all at once
+func (u *goUpdater) UpdateDependencies(ctx context.Context, deps []dependency.Dependency, verbose bool) UpdateResult {
 	result := UpdateResult{
 		Updated: make([]dependency.Dependency, 0),
 		Failed:  make([]UpdateError, 0),
 	}
 
-	for _, dep := range deps {
-		// Try to update each dependency individually
-		// If one fails, add to Failed slice and continue with others
-		err := u.commandRunner.Run("go", []string{"get", "-u", dep.Path}, verbose)
-		if err != nil {
-			result.Failed = append(result.Failed, UpdateError{
-				Dependency: dep,
-				Error:      err, // Keep original error for better reporting
-			})
-		} else {
-			result.Updated = append(result.Updated, dep)
+	if u.batch && len(deps) > 1 {
+		u.updateBatch(ctx, deps, verbose, &result)
+	} else {
+		for _, dep := range deps {
+			u.updateOne(ctx, dep, verbose, &result)
 		}
 	}
 
-	result.Success = len(result.Failed) == 0
+	result.Success = len(result.Failed) == 0 && len(result.Reverted) == 0
 	return result
 }
 
-// RunModTidy runs go mod tidy to clean up the module
-func (u *goUpdater) RunModTidy(verbose bool) error {
-	return u.commandRunner.Run("go", []string{"mod", "tidy"}, verbose)
+// updateOne updates a single dependency and adds the outcome to the result
+func (u *goUpdater) updateOne(ctx context.Context, dep dependency.Dependency, verbose bool, result *UpdateResult) {
+	if u.verifySteps != nil {
+		u.updateAndVerify(ctx, dep, verbose, result)
+		return
+	}
+
+	// Try to update each dependency individually
+	// If one fails, add to Failed slice and continue with others
+	err := u.updateDependency(ctx, dep, verbose)
+	if err != nil {
+		result.Failed = append(result.Failed, UpdateError{
+			Dependency: dep,
+			Error:      err, // Keep original error for better reporting
+		})
+	} else {
+		result.Updated = append(result.Updated, dep)
+	}
+}
+
+func (u *goUpdater) updateDependency(ctx context.Context, dep dependency.Dependency, verbose bool) error {
+	if dep.UpdatesReplacement() {
+		return u.updateReplacement(ctx, dep, verbose)
+	}
+
+	args, err := u.getArgs(dep)
+	if err != nil {
+		return err
+	}
+
+	dirs, err := u.dependencyDirs(dep)
+	if err != nil {
+		return err
+	}
+
+	// In a workspace the same version is applied to every module requiring it
+	for _, dir := range dirs {
+		// The upstream version only takes effect once the replacement is gone
+		if dep.DropReplace {
+			if err := u.dropReplace(ctx, dir, dep, verbose); err != nil {
+				return u.inModule(dir, err)
+			}
+		}
+
+		if err := u.run(ctx, dir, "go", args, verbose); err != nil {
+			return u.inModule(dir, err)
+		}
+
+		if dep.IsMajorUpgrade() {
+			if err := u.migrateMajor(ctx, dir, dep, verbose); err != nil {
+				return u.inModule(dir, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// migrateMajor switches the module in dir from the old major version path to
+// the new one: every import is rewritten and the old requirement is dropped
+// from go.mod
+func (u *goUpdater) migrateMajor(ctx context.Context, dir string, dep dependency.Dependency, verbose bool) error {
+	// A dry run only changes the copy of go.mod
+	if u.modfile != "" {
+		return u.run(ctx, dir, "go", []string{"mod", "edit", "-droprequire=" + dep.Path}, verbose)
+	}
+
+	if _, err := rewriteImports(dir, dep.Path, dep.NewPath, u.backup); err != nil {
+		return err
+	}
+
+	// Rewritten sources must be restored together with go.mod
+	if u.snapshot != nil {
+		if err := u.store.Save(u.snapshot); err != nil {
+			return err
+		}
+	}
+
+	return u.run(ctx, dir, "go", []string{"mod", "edit", "-droprequire=" + dep.Path}, verbose)
+}
+
+// dependencyDirs returns the module directories an update is applied to
+func (u *goUpdater) dependencyDirs(dep dependency.Dependency) ([]string, error) {
+	if u.workspace == nil {
+		return []string{u.moduleDir}, nil
+	}
+
+	if len(dep.Modules) == 0 {
+		return nil, fmt.Errorf("%s is not required by any workspace module", dep.Path)
+	}
+
+	return u.workspaceDirs(dep.Modules), nil
+}
+
+// moduleDirs returns the directory of every module goup works on
+func (u *goUpdater) moduleDirs() []string {
+	if u.workspace == nil {
+		return []string{u.moduleDir}
+	}
+	return u.workspaceDirs(u.workspace.Modules)
+}
+
+func (u *goUpdater) workspaceDirs(modules []string) []string {
+	dirs := make([]string, 0, len(modules))
+	for _, module := range modules {
+		dirs = append(dirs, filepath.Join(u.moduleDir, filepath.FromSlash(module)))
+	}
+	return dirs
+}
+
+// run executes a command in a module directory. Outside a workspace commands
+// run in the current directory, which is the module directory.
+func (u *goUpdater) run(ctx context.Context, dir, name string, args []string, verbose bool) error {
+	if u.modfile != "" && name == "go" {
+		args = withModfile(args, u.modfile)
+	}
+	if u.workspace == nil {
+		return u.commandRunner.Run(ctx, name, args, verbose)
+	}
+	return u.commandRunner.RunInDir(ctx, dir, name, args, verbose)
+}
+
+// inModule prefixes an error with the workspace module it happened in
+func (u *goUpdater) inModule(dir string, err error) error {
+	if u.workspace == nil {
+		return err
+	}
+
+	module, relErr := filepath.Rel(u.moduleDir, dir)
+	if relErr != nil {
+		module = dir
+	}
+	return fmt.Errorf("%s: %w", filepath.ToSlash(module), err)
 }
 
+// getArgs builds the 'go get' arguments for a dependency. The dependency is
+// always pinned to the exact NewVersion shown to the user, which may be a
+// security fix, a pinned or aligned version rather than the latest one;
+// transitive mode adds -u so the module's own dependencies are upgraded too.
+func (u *goUpdater) getArgs(dep dependency.Dependency) ([]string, error) {
+	if dep.NewVersion == "" {
+		return nil, fmt.Errorf("%s: %w", dep.Path, ErrNoTargetVersion)
+	}
+
+	target := dep.TargetPath() + "@" + dep.NewVersion
+	switch {
+	case !u.transitive:
+		return []string{"get", target}, nil
+	case u.policy == dependency.PolicyPatch:
+		return []string{"get", "-u=patch", target}, nil
+	default:
+		return []string{"get", "-u", target}, nil
+	}
+}
+
+// RunModTidy runs go mod tidy to clean up the module. In a workspace the
+// selected versions are first written back to every module with go work sync.
+func (u *goUpdater) RunModTidy(ctx context.Context, verbose bool) error {
+	if u.workspace == nil {
+		return u.run(ctx, u.moduleDir, "go", []string{"mod", "tidy"}, verbose)
+	}
+
+	if err := u.run(ctx, u.moduleDir, "go", []string{"work", "sync"}, verbose); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, dir := range u.moduleDirs() {
+		if err := u.run(ctx, dir, "go", []string{"mod", "tidy"}, verbose); err != nil {
+			errs = append(errs, u.inModule(dir, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Snapshot captures go.mod and go.sum and saves them, replacing the snapshot
+// of the previous run
+func (u *goUpdater) Snapshot() error {
+	s, err := u.takeSnapshot()
+	if err != nil {
+		return err
+	}
+
+	if err := u.store.Save(s); err != nil {
+		return err
+	}
+
+	u.snapshot = s
+	return nil
+}
+
+// Rollback restores the snapshot taken during this run or, if there is none,
+// the one saved by the previous run
+func (u *goUpdater) Rollback() error {
+	s := u.snapshot
+	if s == nil {
+		var err error
+		if s, err = u.store.Load(u.moduleDir); err != nil {
+			return err
+		}
+	}
+
+	return s.Restore()
+}
+
+// takeSnapshot captures the module files of the module or of every workspace module
+func (u *goUpdater) takeSnapshot() (*snapshot.Snapshot, error) {
+	s, err := snapshot.Take(u.moduleDir)
+	if err != nil || u.workspace == nil {
+		return s, err
+	}
+
+	for _, dir := range u.moduleDirs() {
+		for _, name := range snapshot.ModuleFiles {
+			if err := s.Add(filepath.Join(dir, name)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := s.Add(filepath.Join(u.moduleDir, "go.work.sum")); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// backup adds a file about to be modified to the current snapshots
+func (u *goUpdater) backup(path string) error {
+	if u.step != nil {
+		if err := u.step.Add(path); err != nil {
+			return err
+		}
+	}
+	if u.snapshot == nil {
+		return nil
+	}
+	return u.snapshot.Add(path)
+}
+
+// stopGracePeriod is how long a command may take to exit after being
+// interrupted before it is killed
+const stopGracePeriod = 10 * time.Second
+
 // systemCommandRunner implements CommandRunner using os/exec
-type systemCommandRunner struct{}
+type systemCommandRunner struct {
+	timeout time.Duration // Limit of each command, zero for none
+}
+
+// NewCommandRunner creates a command runner that stops every command after
+// timeout, unless it is zero
+func NewCommandRunner(timeout time.Duration) CommandRunner {
+	return &systemCommandRunner{timeout: timeout}
+}
 
 // Run executes a command and returns the result
-func (r *systemCommandRunner) Run(name string, args []string, verbose bool) error {
-	cmd := exec.Command(name, args...)
+func (r *systemCommandRunner) Run(ctx context.Context, name string, args []string, verbose bool) error {
+	return r.RunInDir(ctx, "", name, args, verbose)
+}
+
+// RunInDir executes a command in dir, or in the current directory if dir is
+// empty. When ctx is done or the timeout expires the command is interrupted,
+// so the go command can finish writing go.mod, and killed if it does not
+// exit within stopGracePeriod.
+func (r *systemCommandRunner) RunInDir(ctx context.Context, dir, name string, args []string, verbose bool) error {
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	cmd.Cancel = func() error { return interrupt(cmd.Process) }
+	cmd.WaitDelay = stopGracePeriod
+	// Ctrl-C stops goup after the current command rather than in the middle of it
+	detach(cmd)
 
 	if verbose {
-		cmd.Stdout = os.Stdout
+		// stdout carries goup's own output, such as the JSON report
+		cmd.Stdout = os.Stderr
 		cmd.Stderr = os.Stderr
-		return cmd.Run()
+		return contextError(ctx, run(cmd))
 	}
 
 	// Capture output for non-verbose mode to show only on error
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("command failed: %w\nOutput: %s", err, string(output))
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := run(cmd); err != nil {
+		return fmt.Errorf("command failed: %w\nOutput: %s", contextError(ctx, err), output.String())
 	}
 
 	return nil
 }
+
+// running holds the processes of the commands in progress. Being detached,
+// they outlive goup unless killed by KillCommands.
+var running sync.Map
+
+// run runs a command, registered in running until it exits
+func run(cmd *exec.Cmd) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	running.Store(cmd.Process, struct{}{})
+	defer running.Delete(cmd.Process)
+	return cmd.Wait()
+}
+
+// KillCommands kills the commands in progress and the processes they
+// started, for goup to quit without waiting for them
+func KillCommands() {
+	running.Range(func(process, _ any) bool {
+		_ = kill(process.(*os.Process))
+		return true
+	})
+}
+
+// dirRunner runs the commands of a module that is not in the current directory
+// in the module directory
+type dirRunner struct {
+	dir    string
+	runner CommandRunner
+}
+
+// Run executes a command in the module directory
+func (r *dirRunner) Run(ctx context.Context, name string, args []string, verbose bool) error {
+	return r.runner.RunInDir(ctx, r.dir, name, args, verbose)
+}
+
+// RunInDir executes a command in dir
+func (r *dirRunner) RunInDir(ctx context.Context, dir, name string, args []string, verbose bool) error {
+	return r.runner.RunInDir(ctx, dir, name, args, verbose)
+}
+
+// contextError explains that a command failed because it was stopped, which
+// the bare exit status of an interrupted command does not tell
+func contextError(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() == nil || errors.Is(err, ctx.Err()) {
+		return err
+	}
+	return fmt.Errorf("%w (%w)", ctx.Err(), err)
+}
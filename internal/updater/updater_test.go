@@ -0,0 +1,340 @@
+package updater
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"goup/internal/config"
+	"goup/internal/dependency"
+	"goup/internal/snapshot"
+)
+
+// recordingRunner records every command it is asked to run
+type recordingRunner struct {
+	commands []string
+	failOn   map[string]error
+}
+
+func (r *recordingRunner) Run(ctx context.Context, name string, args []string, verbose bool) error {
+	command := name + " " + strings.Join(args, " ")
+	r.commands = append(r.commands, command)
+	return r.failOn[command]
+}
+
+// RunInDir records the command prefixed with the base name of dir
+func (r *recordingRunner) RunInDir(ctx context.Context, dir, name string, args []string, verbose bool) error {
+	command := "[" + filepath.Base(dir) + "] " + name + " " + strings.Join(args, " ")
+	r.commands = append(r.commands, command)
+	return r.failOn[command]
+}
+
+func TestUpdateDependenciesPinsNewVersion(t *testing.T) {
+	runner := &recordingRunner{}
+	upd := NewGoUpdaterWithRunner(&config.Config{}, runner)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+		{Path: "golang.org/x/crypto", Version: "v0.14.0", NewVersion: "v0.17.0", Indirect: true, HasUpdate: true},
+	}
+
+	result := upd.UpdateDependencies(context.Background(), deps, false)
+
+	assert.True(t, result.Success)
+	assert.Equal(t, deps, result.Updated)
+	assert.Empty(t, result.Failed)
+	assert.Equal(t, []string{
+		"go get github.com/gin-gonic/gin@v1.9.2",
+		"go get golang.org/x/crypto@v0.17.0",
+	}, runner.commands)
+}
+
+func TestUpdateDependenciesTransitive(t *testing.T) {
+	runner := &recordingRunner{}
+	upd := NewGoUpdaterWithRunner(&config.Config{Transitive: true}, runner)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+	}
+
+	result := upd.UpdateDependencies(context.Background(), deps, false)
+
+	assert.True(t, result.Success)
+	assert.Equal(t, []string{"go get -u github.com/gin-gonic/gin@v1.9.2"}, runner.commands)
+}
+
+func TestUpdateDependenciesTransitiveSecurityFix(t *testing.T) {
+	runner := &recordingRunner{}
+	upd := NewGoUpdaterWithRunner(&config.Config{Transitive: true, Security: true, VulnDB: "osv.json"}, runner)
+
+	// The minimal fixed version, not the latest release
+	deps := []dependency.Dependency{
+		{Path: "golang.org/x/text", Version: "v0.3.7", NewVersion: "v0.3.8", HasUpdate: true, Advisories: []string{"GO-2022-1059"}},
+	}
+
+	result := upd.UpdateDependencies(context.Background(), deps, false)
+
+	assert.True(t, result.Success)
+	assert.Equal(t, []string{"go get -u golang.org/x/text@v0.3.8"}, runner.commands)
+}
+
+func TestUpdateDependenciesTransitiveWithPolicy(t *testing.T) {
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.0", NewVersion: "v1.9.1", HasUpdate: true},
+	}
+
+	tests := []struct {
+		policy   dependency.Policy
+		expected string
+	}{
+		{policy: dependency.PolicyPatch, expected: "go get -u=patch github.com/gin-gonic/gin@v1.9.1"},
+		{policy: dependency.PolicyMinor, expected: "go get -u github.com/gin-gonic/gin@v1.9.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			runner := &recordingRunner{}
+			upd := NewGoUpdaterWithRunner(&config.Config{Transitive: true, Policy: tt.policy}, runner)
+
+			result := upd.UpdateDependencies(context.Background(), deps, false)
+
+			assert.True(t, result.Success)
+			assert.Equal(t, []string{tt.expected}, runner.commands)
+		})
+	}
+}
+
+func TestUpdateDependenciesWithoutNewVersion(t *testing.T) {
+	runner := &recordingRunner{}
+	upd := NewGoUpdaterWithRunner(&config.Config{}, runner)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1"},
+	}
+
+	result := upd.UpdateDependencies(context.Background(), deps, false)
+
+	assert.False(t, result.Success)
+	assert.Empty(t, runner.commands, "Nothing should run without a target version")
+	require.Len(t, result.Failed, 1)
+	assert.ErrorIs(t, result.Failed[0].Error, ErrNoTargetVersion)
+}
+
+func TestUpdateDependenciesContinuesAfterFailure(t *testing.T) {
+	runner := &recordingRunner{
+		failOn: map[string]error{
+			"go get github.com/bad/package@v1.1.0": errors.New("module not found"),
+		},
+	}
+	upd := NewGoUpdaterWithRunner(&config.Config{}, runner)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/bad/package", Version: "v1.0.0", NewVersion: "v1.1.0", HasUpdate: true},
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+	}
+
+	result := upd.UpdateDependencies(context.Background(), deps, false)
+
+	assert.False(t, result.Success)
+	assert.Equal(t, []dependency.Dependency{deps[1]}, result.Updated)
+	require.Len(t, result.Failed, 1)
+	assert.Equal(t, deps[0], result.Failed[0].Dependency)
+	assert.EqualError(t, result.Failed[0].Error, "module not found")
+}
+
+func TestUpdateDependenciesMajorUpgrade(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root+"/main.go", "package main\n\nimport \"github.com/foo/bar/v2\"\n\nvar _ = bar.X\n")
+
+	runner := &recordingRunner{}
+	upd := &goUpdater{commandRunner: runner, moduleDir: root}
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/foo/bar/v2", Version: "v2.5.0", NewPath: "github.com/foo/bar/v4", NewVersion: "v4.0.1", HasUpdate: true},
+	}
+
+	result := upd.UpdateDependencies(context.Background(), deps, false)
+
+	assert.True(t, result.Success)
+	assert.Equal(t, []string{
+		"go get github.com/foo/bar/v4@v4.0.1",
+		"go mod edit -droprequire=github.com/foo/bar/v2",
+	}, runner.commands)
+	assert.Contains(t, readFile(t, root+"/main.go"), `import "github.com/foo/bar/v4"`)
+}
+
+func TestUpdateDependenciesMajorUpgradeGetFails(t *testing.T) {
+	root := t.TempDir()
+	source := "package main\n\nimport \"github.com/foo/bar/v2\"\n\nvar _ = bar.X\n"
+	writeFile(t, root+"/main.go", source)
+
+	runner := &recordingRunner{
+		failOn: map[string]error{"go get github.com/foo/bar/v4@v4.0.1": errors.New("module not found")},
+	}
+	upd := &goUpdater{commandRunner: runner, moduleDir: root}
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/foo/bar/v2", Version: "v2.5.0", NewPath: "github.com/foo/bar/v4", NewVersion: "v4.0.1", HasUpdate: true},
+	}
+
+	result := upd.UpdateDependencies(context.Background(), deps, false)
+
+	assert.False(t, result.Success)
+	assert.Equal(t, source, readFile(t, root+"/main.go"), "Imports must not be rewritten when go get fails")
+}
+
+func TestRunModTidy(t *testing.T) {
+	runner := &recordingRunner{}
+	upd := NewGoUpdaterWithRunner(&config.Config{}, runner)
+
+	require.NoError(t, upd.RunModTidy(context.Background(), false))
+	assert.Equal(t, []string{"go mod tidy"}, runner.commands)
+}
+
+func TestModuleUpdaterRunsInModuleDirectory(t *testing.T) {
+	runner := &recordingRunner{}
+	upd := NewModuleUpdaterWithRunner(&config.Config{}, "services/api", runner)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+	}
+	result := upd.UpdateDependencies(context.Background(), deps, false)
+	require.True(t, result.Success)
+	require.NoError(t, upd.RunModTidy(context.Background(), false))
+
+	assert.Equal(t, []string{
+		"[api] go get github.com/gin-gonic/gin@v1.9.2",
+		"[api] go mod tidy",
+	}, runner.commands)
+}
+
+func TestSnapshotAndRollback(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root+"/go.mod", "module example\n\nrequire github.com/foo/bar/v2 v2.5.0\n")
+	writeFile(t, root+"/main.go", "package main\n\nimport \"github.com/foo/bar/v2\"\n\nvar _ = bar.X\n")
+
+	store := snapshot.NewStoreWithRoot(t.TempDir())
+	runner := &recordingRunner{}
+	upd := &goUpdater{commandRunner: runner, moduleDir: root, store: store}
+
+	require.NoError(t, upd.Snapshot())
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/foo/bar/v2", Version: "v2.5.0", NewPath: "github.com/foo/bar/v4", NewVersion: "v4.0.1", HasUpdate: true},
+	}
+	result := upd.UpdateDependencies(context.Background(), deps, false)
+	require.True(t, result.Success)
+	writeFile(t, root+"/go.mod", "module example\n\nrequire github.com/foo/bar/v4 v4.0.1\n")
+
+	require.NoError(t, upd.Rollback())
+
+	assert.Equal(t, "module example\n\nrequire github.com/foo/bar/v2 v2.5.0\n", readFile(t, root+"/go.mod"))
+	assert.Contains(t, readFile(t, root+"/main.go"), `import "github.com/foo/bar/v2"`, "Rewritten imports are restored too")
+}
+
+func TestRollbackFromSavedSnapshot(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root+"/go.mod", "module example\n\ngo 1.21\n")
+	store := snapshot.NewStoreWithRoot(t.TempDir())
+
+	// The snapshot is taken by one run and restored by the next
+	previous := &goUpdater{moduleDir: root, store: store}
+	require.NoError(t, previous.Snapshot())
+	writeFile(t, root+"/go.mod", "module example\n\ngo 1.22\n")
+
+	current := &goUpdater{moduleDir: root, store: store}
+	require.NoError(t, current.Rollback())
+
+	assert.Equal(t, "module example\n\ngo 1.21\n", readFile(t, root+"/go.mod"))
+}
+
+func TestRollbackWithoutSnapshot(t *testing.T) {
+	upd := &goUpdater{moduleDir: t.TempDir(), store: snapshot.NewStoreWithRoot(t.TempDir())}
+
+	assert.ErrorIs(t, upd.Rollback(), snapshot.ErrNoSnapshot)
+}
+
+func TestCommandRunnerTimeout(t *testing.T) {
+	runner := NewCommandRunner(50 * time.Millisecond)
+
+	start := time.Now()
+	err := runner.Run(context.Background(), "sleep", []string{"5"}, false)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second, "The command must be stopped when the timeout expires")
+}
+
+func TestCommandRunnerCancelled(t *testing.T) {
+	runner := NewCommandRunner(0)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := runner.RunInDir(ctx, t.TempDir(), "sleep", []string{"5"}, false)
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestKillCommands(t *testing.T) {
+	done := make(chan error)
+	go func() {
+		// The background sleep keeps the output pipe open until it is killed too
+		done <- NewCommandRunner(0).Run(context.Background(), "sh", []string{"-c", "sleep 30 & wait"}, false)
+	}()
+
+	require.Eventually(t, func() bool {
+		started := false
+		running.Range(func(_, _ any) bool {
+			started = true
+			return false
+		})
+		return started
+	}, 5*time.Second, 10*time.Millisecond)
+	KillCommands()
+
+	select {
+	case err := <-done:
+		assert.Error(t, err)
+	case <-time.After(stopGracePeriod / 2):
+		t.Fatal("The command and the processes it started must be killed")
+	}
+}
+
+func TestCommandRunnerVerboseWritesToStderr(t *testing.T) {
+	stdout := redirect(t, &os.Stdout)
+	stderr := redirect(t, &os.Stderr)
+
+	err := NewCommandRunner(0).Run(context.Background(), "sh", []string{"-c", "echo building"}, true)
+
+	require.NoError(t, err)
+	assert.Empty(t, stdout(), "The JSON report on stdout must not be mixed with command output")
+	assert.Equal(t, "building\n", stderr())
+}
+
+// redirect replaces an output file with a pipe for the rest of the test and
+// returns a function reading what was written to it
+func redirect(t *testing.T, file **os.File) func() string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+
+	original := *file
+	*file = w
+	t.Cleanup(func() { *file = original })
+
+	return func() string {
+		*file = original
+		require.NoError(t, w.Close())
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		return string(data)
+	}
+}
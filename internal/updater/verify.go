@@ -0,0 +1,94 @@
+package updater
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"goup/internal/dependency"
+)
+
+// ErrVerificationFailed is returned when the verify command fails after an update
+var ErrVerificationFailed = errors.New("verification failed")
+
+// baseline records whether the module passed the verify command before any update
+type baseline int
+
+const (
+	baselineUnchecked baseline = iota
+	baselinePassed
+	baselineFailed
+)
+
+// verify runs every step of the verify command in each module, stopping at
+// the first failure
+func (u *goUpdater) verify(ctx context.Context, verbose bool) error {
+	for _, dir := range u.moduleDirs() {
+		for _, step := range u.verifySteps {
+			if err := u.run(ctx, dir, step[0], step[1:], verbose); err != nil {
+				err = fmt.Errorf("%s: %v", strings.Join(step, " "), err)
+				return fmt.Errorf("%w: %w", ErrVerificationFailed, u.inModule(dir, err))
+			}
+		}
+	}
+	return nil
+}
+
+// checkBaseline runs the verify command once on the untouched module. A check
+// that already fails cannot tell which update broke it.
+func (u *goUpdater) checkBaseline(ctx context.Context, verbose bool) baseline {
+	if u.baseline == baselineUnchecked {
+		u.baseline = baselinePassed
+		if err := u.verify(ctx, verbose); err != nil {
+			u.baseline = baselineFailed
+		}
+	}
+	return u.baseline
+}
+
+// updateAndVerify updates a dependency and runs the verify command, restoring
+// the module files touched by the update if the check fails
+func (u *goUpdater) updateAndVerify(ctx context.Context, dep dependency.Dependency, verbose bool, result *UpdateResult) {
+	if u.checkBaseline(ctx, verbose) == baselineFailed {
+		if err := u.updateDependency(ctx, dep, verbose); err != nil {
+			result.Failed = append(result.Failed, UpdateError{Dependency: dep, Error: err})
+			return
+		}
+		result.Updated = append(result.Updated, dep)
+		result.Skipped = append(result.Skipped, dep)
+		return
+	}
+
+	step, err := u.takeSnapshot()
+	if err != nil {
+		result.Failed = append(result.Failed, UpdateError{Dependency: dep, Error: err})
+		return
+	}
+
+	u.step = step
+	defer func() { u.step = nil }()
+
+	if err := u.updateDependency(ctx, dep, verbose); err != nil {
+		result.Failed = append(result.Failed, UpdateError{Dependency: dep, Error: err})
+		return
+	}
+
+	verifyErr := u.verify(ctx, verbose)
+	if verifyErr == nil {
+		result.Updated = append(result.Updated, dep)
+		result.Verified = append(result.Verified, dep)
+		return
+	}
+
+	if err := step.Restore(); err != nil {
+		// The module is left with the update applied, so report it as a failure
+		result.Failed = append(result.Failed, UpdateError{
+			Dependency: dep,
+			Error:      fmt.Errorf("%w; reverting the update also failed: %v", verifyErr, err),
+		})
+		return
+	}
+
+	result.Reverted = append(result.Reverted, UpdateError{Dependency: dep, Error: verifyErr})
+}
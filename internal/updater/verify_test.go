@@ -0,0 +1,139 @@
+package updater
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"goup/internal/config"
+	"goup/internal/dependency"
+)
+
+func TestUpdateDependenciesVerify(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root+"/go.mod", "module example\n")
+
+	runner := &recordingRunner{}
+	upd := NewGoUpdaterWithRunner(&config.Config{Verify: true}, runner).(*goUpdater)
+	upd.moduleDir = root
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+	}
+
+	result := upd.UpdateDependencies(context.Background(), deps, false)
+
+	assert.True(t, result.Success)
+	assert.Equal(t, deps, result.Updated)
+	assert.Equal(t, deps, result.Verified)
+	assert.Equal(t, []string{
+		"go build ./...", // Baseline check before any update
+		"go test ./...",
+		"go get github.com/gin-gonic/gin@v1.9.2",
+		"go build ./...",
+		"go test ./...",
+	}, runner.commands)
+}
+
+func TestUpdateDependenciesVerifyRevertsFailingUpdate(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root+"/go.mod", "module example\n\nrequire github.com/bad/package v1.0.0\n")
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/bad/package", Version: "v1.0.0", NewVersion: "v1.1.0", HasUpdate: true},
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+	}
+
+	// The baseline passes, then only the first update breaks the check
+	checks := 0
+	runner := runnerFunc(func(name string, args []string, verbose bool) error {
+		if name == "go" {
+			return nil
+		}
+		checks++
+		if checks == 2 {
+			return errors.New("exit status 2")
+		}
+		return nil
+	})
+	upd := NewGoUpdaterWithRunner(&config.Config{Verify: true, VerifyCommand: "make check"}, runner).(*goUpdater)
+	upd.moduleDir = root
+
+	result := upd.UpdateDependencies(context.Background(), deps, false)
+
+	assert.False(t, result.Success)
+	assert.Equal(t, []dependency.Dependency{deps[1]}, result.Updated)
+	assert.Equal(t, []dependency.Dependency{deps[1]}, result.Verified)
+	assert.Empty(t, result.Failed)
+	require.Len(t, result.Reverted, 1)
+	assert.Equal(t, deps[0], result.Reverted[0].Dependency)
+	assert.ErrorIs(t, result.Reverted[0].Error, ErrVerificationFailed)
+	assert.Contains(t, result.Reverted[0].Error.Error(), "make check")
+}
+
+func TestUpdateDependenciesVerifyRestoresFiles(t *testing.T) {
+	root := t.TempDir()
+	original := "module example\n\nrequire github.com/bad/package v1.0.0\n"
+	writeFile(t, root+"/go.mod", original)
+
+	upd := &goUpdater{moduleDir: root, verifySteps: [][]string{{"make", "check"}}, baseline: baselinePassed}
+	upd.commandRunner = runnerFunc(func(name string, args []string, verbose bool) error {
+		if name == "go" {
+			writeFile(t, root+"/go.mod", "module example\n\nrequire github.com/bad/package v1.1.0\n")
+			writeFile(t, root+"/go.sum", "github.com/bad/package v1.1.0 h1:abc\n")
+			return nil
+		}
+		return errors.New("exit status 2")
+	})
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/bad/package", Version: "v1.0.0", NewVersion: "v1.1.0", HasUpdate: true},
+	}
+
+	result := upd.UpdateDependencies(context.Background(), deps, false)
+
+	require.Len(t, result.Reverted, 1)
+	assert.Equal(t, original, readFile(t, root+"/go.mod"))
+	assert.NoFileExists(t, root+"/go.sum")
+}
+
+func TestUpdateDependenciesVerifyBaselineFails(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root+"/go.mod", "module example\n")
+
+	runner := &recordingRunner{failOn: map[string]error{"go test ./...": errors.New("exit status 1")}}
+	upd := NewGoUpdaterWithRunner(&config.Config{Verify: true}, runner).(*goUpdater)
+	upd.moduleDir = root
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true},
+		{Path: "golang.org/x/crypto", Version: "v0.14.0", NewVersion: "v0.17.0", HasUpdate: true},
+	}
+
+	result := upd.UpdateDependencies(context.Background(), deps, false)
+
+	assert.True(t, result.Success)
+	assert.Equal(t, deps, result.Updated)
+	assert.Equal(t, deps, result.Skipped, "Updates cannot be verified against a failing baseline")
+	assert.Empty(t, result.Verified)
+	assert.Equal(t, []string{
+		"go build ./...",
+		"go test ./...",
+		"go get github.com/gin-gonic/gin@v1.9.2",
+		"go get golang.org/x/crypto@v0.17.0",
+	}, runner.commands)
+}
+
+// runnerFunc adapts a function to the CommandRunner interface
+type runnerFunc func(name string, args []string, verbose bool) error
+
+func (f runnerFunc) Run(ctx context.Context, name string, args []string, verbose bool) error {
+	return f(name, args, verbose)
+}
+
+func (f runnerFunc) RunInDir(ctx context.Context, dir, name string, args []string, verbose bool) error {
+	return f(name, args, verbose)
+}
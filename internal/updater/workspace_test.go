@@ -0,0 +1,126 @@
+package updater
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"goup/internal/config"
+	"goup/internal/dependency"
+	"goup/internal/snapshot"
+)
+
+func newTestWorkspace(t *testing.T) *dependency.Workspace {
+	t.Helper()
+	root := t.TempDir()
+	writeFile(t, root+"/go.work", "go 1.21\n\nuse (\n\t./api\n\t./worker\n)\n")
+	writeFile(t, root+"/api/go.mod", "module example.com/api\n\nrequire github.com/foo/bar/v2 v2.5.0\n")
+	writeFile(t, root+"/api/main.go", "package main\n\nimport \"github.com/foo/bar/v2\"\n\nvar _ = bar.X\n")
+	writeFile(t, root+"/worker/go.mod", "module example.com/worker\n\nrequire github.com/foo/bar/v2 v2.5.0\n")
+	return &dependency.Workspace{Root: root, Modules: []string{"api", "worker"}}
+}
+
+func TestWorkspaceUpdateDependencies(t *testing.T) {
+	ws := newTestWorkspace(t)
+	runner := &recordingRunner{}
+	upd := NewWorkspaceUpdaterWithRunner(&config.Config{}, ws, runner)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true, Modules: []string{"api", "worker"}},
+		{Path: "golang.org/x/crypto", Version: "v0.14.0", NewVersion: "v0.17.0", HasUpdate: true, Modules: []string{"worker"}},
+	}
+
+	result := upd.UpdateDependencies(context.Background(), deps, false)
+
+	assert.True(t, result.Success)
+	assert.Equal(t, []string{
+		"[api] go get github.com/gin-gonic/gin@v1.9.2",
+		"[worker] go get github.com/gin-gonic/gin@v1.9.2",
+		"[worker] go get golang.org/x/crypto@v0.17.0",
+	}, runner.commands)
+}
+
+func TestWorkspaceUpdateMajorUpgrade(t *testing.T) {
+	ws := newTestWorkspace(t)
+	runner := &recordingRunner{}
+	upd := NewWorkspaceUpdaterWithRunner(&config.Config{}, ws, runner)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/foo/bar/v2", Version: "v2.5.0", NewPath: "github.com/foo/bar/v4", NewVersion: "v4.0.1", HasUpdate: true, Modules: []string{"api"}},
+	}
+
+	result := upd.UpdateDependencies(context.Background(), deps, false)
+
+	assert.True(t, result.Success)
+	assert.Equal(t, []string{
+		"[api] go get github.com/foo/bar/v4@v4.0.1",
+		"[api] go mod edit -droprequire=github.com/foo/bar/v2",
+	}, runner.commands)
+	assert.Contains(t, readFile(t, ws.Root+"/api/main.go"), `import "github.com/foo/bar/v4"`)
+}
+
+func TestWorkspaceUpdateFailureNamesModule(t *testing.T) {
+	ws := newTestWorkspace(t)
+	runner := &recordingRunner{
+		failOn: map[string]error{"[worker] go get github.com/gin-gonic/gin@v1.9.2": errors.New("module not found")},
+	}
+	upd := NewWorkspaceUpdaterWithRunner(&config.Config{}, ws, runner)
+
+	deps := []dependency.Dependency{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", NewVersion: "v1.9.2", HasUpdate: true, Modules: []string{"api", "worker"}},
+		{Path: "github.com/unused/module", Version: "v1.0.0", NewVersion: "v1.1.0", HasUpdate: true},
+	}
+
+	result := upd.UpdateDependencies(context.Background(), deps, false)
+
+	require.Len(t, result.Failed, 2)
+	assert.EqualError(t, result.Failed[0].Error, "worker: module not found")
+	assert.EqualError(t, result.Failed[1].Error, "github.com/unused/module is not required by any workspace module")
+}
+
+func TestWorkspaceRunModTidy(t *testing.T) {
+	ws := newTestWorkspace(t)
+	runner := &recordingRunner{}
+	upd := NewWorkspaceUpdaterWithRunner(&config.Config{}, ws, runner)
+
+	require.NoError(t, upd.RunModTidy(context.Background(), false))
+	assert.Equal(t, []string{
+		"[" + filepath.Base(ws.Root) + "] go work sync",
+		"[api] go mod tidy",
+		"[worker] go mod tidy",
+	}, runner.commands)
+}
+
+func TestWorkspaceSnapshotCoversEveryModule(t *testing.T) {
+	ws := newTestWorkspace(t)
+	upd := NewWorkspaceUpdaterWithRunner(&config.Config{}, ws, &recordingRunner{}).(*goUpdater)
+	upd.store = snapshot.NewStoreWithRoot(t.TempDir())
+
+	require.NoError(t, upd.Snapshot())
+	writeFile(t, ws.Root+"/api/go.mod", "module example.com/api\n\nrequire github.com/foo/bar/v4 v4.0.1\n")
+	writeFile(t, ws.Root+"/worker/go.sum", "github.com/foo/bar/v2 v2.6.0 h1:abc\n")
+
+	require.NoError(t, upd.Rollback())
+
+	assert.Equal(t, "module example.com/api\n\nrequire github.com/foo/bar/v2 v2.5.0\n", readFile(t, ws.Root+"/api/go.mod"))
+	assert.NoFileExists(t, ws.Root+"/worker/go.sum")
+}
+
+func TestWorkspaceVerifyRunsInEveryModule(t *testing.T) {
+	ws := newTestWorkspace(t)
+	runner := &recordingRunner{
+		failOn: map[string]error{"[worker] go vet ./...": errors.New("exit status 1")},
+	}
+	upd := NewWorkspaceUpdaterWithRunner(&config.Config{Verify: true, VerifyCommand: "go vet ./..."}, ws, runner).(*goUpdater)
+	upd.baseline = baselinePassed
+
+	err := upd.verify(context.Background(), false)
+
+	assert.ErrorIs(t, err, ErrVerificationFailed)
+	assert.EqualError(t, err, "verification failed: worker: go vet ./...: exit status 1")
+	assert.Equal(t, []string{"[api] go vet ./...", "[worker] go vet ./..."}, runner.commands)
+}
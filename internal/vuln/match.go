@@ -0,0 +1,188 @@
+package vuln
+
+import (
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// Finding lists the advisories affecting a module version
+type Finding struct {
+	Path       string
+	Version    string
+	Advisories []string // IDs of the advisories affecting the version
+	Fixed      string   // Lowest newer version affected by none of them, empty if no fix is known
+}
+
+// Check returns the advisories affecting a module version, and whether there
+// are any. The fixed version is the smallest step that clears all of them, so
+// a fix never pulls in more change than needed.
+func (db *Database) Check(path, version string) (Finding, bool) {
+	finding := Finding{Path: path, Version: version}
+
+	for _, advisory := range db.advisories[path] {
+		if advisory.affects(path, version) {
+			finding.Advisories = append(finding.Advisories, advisory.ID)
+		}
+	}
+	if len(finding.Advisories) == 0 {
+		return finding, false
+	}
+
+	finding.Fixed = fixedVersion(path, version, db.advisories[path])
+	return finding, true
+}
+
+// FirstUnaffected returns the lowest of versions above version that none of
+// the advisories of the module affects, or an empty string if there is none.
+// It picks a fix among the versions an update may actually move to.
+func (db *Database) FirstUnaffected(path, version string, versions []string) string {
+	first := ""
+	for _, candidate := range versions {
+		if semver.Compare(candidate, version) <= 0 || (first != "" && semver.Compare(candidate, first) >= 0) {
+			continue
+		}
+		if !db.affected(path, candidate) {
+			first = candidate
+		}
+	}
+	return first
+}
+
+// affected reports whether any advisory of the module applies to a version
+func (db *Database) affected(path, version string) bool {
+	for _, advisory := range db.advisories[path] {
+		if advisory.affects(path, version) {
+			return true
+		}
+	}
+	return false
+}
+
+// fixedVersion raises version past the fix of each advisory until none of
+// them applies, including advisories introduced after version. Every step
+// moves to a strictly higher fixed version, so the loop ends once the fixes
+// are exhausted.
+func fixedVersion(path, version string, advisories []Advisory) string {
+	target := version
+	for {
+		raised := false
+		for _, advisory := range advisories {
+			if !advisory.affects(path, target) {
+				continue
+			}
+			fix := advisory.nextFix(path, target)
+			if fix == "" {
+				return ""
+			}
+			target = fix
+			raised = true
+		}
+		if !raised {
+			return target
+		}
+	}
+}
+
+// affects reports whether the advisory applies to a version of a module.
+// A package listed without ranges is affected in every version.
+func (a Advisory) affects(path, version string) bool {
+	for _, affected := range a.Affected {
+		if affected.Package.Name != path {
+			continue
+		}
+		if len(affected.Ranges) == 0 {
+			return true
+		}
+		for _, r := range affected.Ranges {
+			if r.semantic() && r.affects(version) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nextFix returns the lowest fixed version of the module above version
+func (a Advisory) nextFix(path, version string) string {
+	var next string
+	for _, affected := range a.Affected {
+		if affected.Package.Name != path {
+			continue
+		}
+		for _, r := range affected.Ranges {
+			if !r.semantic() {
+				continue
+			}
+			for _, event := range r.Events {
+				if event.Fixed == "" {
+					continue
+				}
+				fixed := canonical(event.Fixed)
+				if semver.Compare(fixed, version) > 0 && (next == "" || semver.Compare(fixed, next) < 0) {
+					next = fixed
+				}
+			}
+		}
+	}
+	return next
+}
+
+// semantic reports whether the range uses semantic versions. Go advisories
+// use SEMVER, and the Go ecosystem ordering is semantic versioning too.
+func (r Range) semantic() bool {
+	return r.Type == "SEMVER" || r.Type == "ECOSYSTEM"
+}
+
+// affects evaluates the range events in version order: the last boundary at
+// or below version decides whether it is affected
+func (r Range) affects(version string) bool {
+	events := append([]Event(nil), r.Events...)
+	sort.SliceStable(events, func(i, j int) bool {
+		return semver.Compare(events[i].version(), events[j].version()) < 0
+	})
+
+	affected := false
+	for _, event := range events {
+		switch {
+		case event.Introduced != "":
+			if semver.Compare(event.version(), version) <= 0 {
+				affected = true
+			}
+		case event.Fixed != "":
+			if semver.Compare(event.version(), version) <= 0 {
+				affected = false
+			}
+		case event.LastAffected != "":
+			if semver.Compare(event.version(), version) < 0 {
+				affected = false
+			}
+		}
+	}
+	return affected
+}
+
+// version returns the canonical version of the event
+func (e Event) version() string {
+	switch {
+	case e.Introduced != "":
+		return canonical(e.Introduced)
+	case e.Fixed != "":
+		return canonical(e.Fixed)
+	default:
+		return canonical(e.LastAffected)
+	}
+}
+
+// canonical converts an OSV version ("1.2.3") to a Go module version
+// ("v1.2.3"). The special introduced version "0" sorts before every version.
+func canonical(version string) string {
+	if version == "0" {
+		return ""
+	}
+	if !strings.HasPrefix(version, "v") {
+		return "v" + version
+	}
+	return version
+}
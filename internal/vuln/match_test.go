@@ -0,0 +1,109 @@
+package vuln
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func advisory(id, module string, ranges ...Range) Advisory {
+	return Advisory{
+		ID: id,
+		Affected: []Affected{{
+			Package: Package{Ecosystem: "Go", Name: module},
+			Ranges:  ranges,
+		}},
+	}
+}
+
+func semverRange(events ...Event) Range {
+	return Range{Type: "SEMVER", Events: events}
+}
+
+func TestCheck(t *testing.T) {
+	db := &Database{advisories: make(map[string][]Advisory)}
+	db.add(advisory("GO-0001", "example.com/lib",
+		semverRange(Event{Introduced: "0"}, Event{Fixed: "1.2.3"}),
+		semverRange(Event{Introduced: "1.3.0"}, Event{Fixed: "1.3.2"}),
+	))
+	db.add(advisory("GO-0002", "example.com/lib",
+		semverRange(Event{Introduced: "1.1.0"}, Event{Fixed: "1.3.1"}),
+	))
+	db.add(advisory("GO-0005", "example.com/simple",
+		semverRange(Event{Introduced: "0"}, Event{Fixed: "1.4.1"}),
+	))
+	db.add(advisory("GO-0003", "example.com/nofix",
+		semverRange(Event{Introduced: "0"}),
+	))
+	db.add(advisory("GO-0004", "example.com/last",
+		semverRange(Event{Introduced: "0"}, Event{LastAffected: "0.9.0"}),
+	))
+
+	tests := []struct {
+		name       string
+		path       string
+		version    string
+		affected   bool
+		advisories []string
+		fixed      string
+	}{
+		{name: "unknown module", path: "example.com/other", version: "v1.0.0"},
+		{name: "minimal fix", path: "example.com/simple", version: "v1.0.0",
+			affected: true, advisories: []string{"GO-0005"}, fixed: "v1.4.1"},
+		{name: "fix skips versions affected by other advisories", path: "example.com/lib", version: "v1.0.0",
+			affected: true, advisories: []string{"GO-0001"}, fixed: "v1.3.2"},
+		{name: "fix that another advisory still affects", path: "example.com/lib", version: "v1.1.5",
+			affected: true, advisories: []string{"GO-0001", "GO-0002"}, fixed: "v1.3.2"},
+		{name: "fixed version", path: "example.com/lib", version: "v1.2.3",
+			affected: true, advisories: []string{"GO-0002"}, fixed: "v1.3.2"},
+		{name: "after every fix", path: "example.com/lib", version: "v1.3.2"},
+		{name: "no fix known", path: "example.com/nofix", version: "v2.0.0",
+			affected: true, advisories: []string{"GO-0003"}},
+		{name: "last affected version", path: "example.com/last", version: "v0.9.0",
+			affected: true, advisories: []string{"GO-0004"}},
+		{name: "after last affected", path: "example.com/last", version: "v0.9.1"},
+		{name: "pseudo-version", path: "example.com/simple", version: "v0.0.0-20200101000000-abcdefabcdef",
+			affected: true, advisories: []string{"GO-0005"}, fixed: "v1.4.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finding, affected := db.Check(tt.path, tt.version)
+
+			assert.Equal(t, tt.affected, affected)
+			assert.Equal(t, tt.advisories, finding.Advisories)
+			assert.Equal(t, tt.fixed, finding.Fixed)
+		})
+	}
+}
+
+func TestFirstUnaffected(t *testing.T) {
+	db := &Database{advisories: make(map[string][]Advisory)}
+	db.add(advisory("GO-0001", "example.com/lib",
+		semverRange(Event{Introduced: "0"}, Event{Fixed: "1.2.3"}),
+		semverRange(Event{Introduced: "1.3.0"}, Event{Fixed: "1.3.2"}),
+	))
+
+	tests := []struct {
+		name     string
+		versions []string
+		want     string
+	}{
+		{name: "lowest fix", versions: []string{"v1.4.0", "v1.2.3", "v1.2.4"}, want: "v1.2.3"},
+		{name: "fix excluded", versions: []string{"v1.2.2", "v1.2.4", "v1.3.0", "v1.3.2"}, want: "v1.2.4"},
+		{name: "skips affected versions", versions: []string{"v1.3.0", "v1.3.1", "v1.3.2"}, want: "v1.3.2"},
+		{name: "no unaffected version", versions: []string{"v1.2.0", "v1.3.1"}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, db.FirstUnaffected("example.com/lib", "v1.2.0", tt.versions))
+		})
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	assert.Equal(t, "v1.2.3", canonical("1.2.3"))
+	assert.Equal(t, "v1.2.3", canonical("v1.2.3"))
+	assert.Equal(t, "", canonical("0"))
+}
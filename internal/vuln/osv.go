@@ -0,0 +1,135 @@
+// Package vuln matches module versions against a local vulnerability database
+// in the OSV format (https://ossf.github.io/osv-schema/).
+package vuln
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Advisory is an OSV vulnerability entry
+type Advisory struct {
+	ID       string     `json:"id"`
+	Aliases  []string   `json:"aliases"`
+	Summary  string     `json:"summary"`
+	Affected []Affected `json:"affected"`
+}
+
+// Affected lists the vulnerable versions of one package
+type Affected struct {
+	Package Package `json:"package"`
+	Ranges  []Range `json:"ranges"`
+}
+
+// Package identifies the affected package. For Go the name is the module path.
+type Package struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+}
+
+// Range is a list of events in version order that mark where the vulnerability
+// was introduced and fixed
+type Range struct {
+	Type   string  `json:"type"`
+	Events []Event `json:"events"`
+}
+
+// Event is a single boundary of an affected range; exactly one field is set
+type Event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+}
+
+// Database holds the advisories of a local OSV database, indexed by module path
+type Database struct {
+	advisories map[string][]Advisory
+}
+
+// Load reads an OSV database from a directory, searched recursively for .json
+// files, or from a single file holding one advisory or an array of them.
+// JSON files that are not advisories, such as database indexes, are ignored.
+func Load(path string) (*Database, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading vulnerability database: %w", err)
+	}
+
+	db := &Database{advisories: make(map[string][]Advisory)}
+
+	if !info.IsDir() {
+		if err := db.loadFile(path); err != nil {
+			return nil, err
+		}
+		return db, nil
+	}
+
+	err = filepath.WalkDir(path, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(file) != ".json" {
+			return nil
+		}
+		return db.loadFile(file)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading vulnerability database: %w", err)
+	}
+
+	return db, nil
+}
+
+func (db *Database) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	var advisories []Advisory
+	if data = bytes.TrimSpace(data); bytes.HasPrefix(data, []byte("[")) {
+		err = json.Unmarshal(data, &advisories)
+	} else {
+		var advisory Advisory
+		err = json.Unmarshal(data, &advisory)
+		advisories = append(advisories, advisory)
+	}
+	if err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	for _, advisory := range advisories {
+		db.add(advisory)
+	}
+	return nil
+}
+
+// add indexes an advisory under every Go module it affects
+func (db *Database) add(advisory Advisory) {
+	if advisory.ID == "" {
+		return
+	}
+
+	for _, affected := range advisory.Affected {
+		if !strings.EqualFold(affected.Package.Ecosystem, "Go") || affected.Package.Name == "" {
+			continue
+		}
+
+		entries := db.advisories[affected.Package.Name]
+		if len(entries) > 0 && entries[len(entries)-1].ID == advisory.ID {
+			// The same module listed twice, e.g. once per package
+			continue
+		}
+		db.advisories[affected.Package.Name] = append(entries, advisory)
+	}
+}
+
+// Len returns the number of modules with at least one advisory
+func (db *Database) Len() int {
+	return len(db.advisories)
+}
@@ -0,0 +1,68 @@
+package vuln
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const textAdvisory = `{
+  "id": "GO-2022-1059",
+  "aliases": ["CVE-2022-32149"],
+  "summary": "Denial of service via crafted Accept-Language header",
+  "affected": [{
+    "package": {"ecosystem": "Go", "name": "golang.org/x/text"},
+    "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "0.3.8"}]}]
+  }]
+}`
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestLoadDirectory(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "ID", "GO-2022-1059.json"), textAdvisory)
+	writeFile(t, filepath.Join(root, "index", "modules.json"), `[{"path": "golang.org/x/text", "vulns": []}]`)
+	writeFile(t, filepath.Join(root, "README.md"), "not an advisory")
+
+	db, err := Load(root)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, db.Len())
+	require.Len(t, db.advisories["golang.org/x/text"], 1)
+	assert.Equal(t, "GO-2022-1059", db.advisories["golang.org/x/text"][0].ID)
+}
+
+func TestLoadFileWithArray(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "osv.json")
+	writeFile(t, path, `[`+textAdvisory+`, {
+	  "id": "PYSEC-2023-1",
+	  "affected": [{"package": {"ecosystem": "PyPI", "name": "requests"}}]
+	}]`)
+
+	db, err := Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, db.Len(), "Advisories of other ecosystems are ignored")
+}
+
+func TestLoadErrors(t *testing.T) {
+	t.Run("missing database", func(t *testing.T) {
+		_, err := Load(filepath.Join(t.TempDir(), "missing"))
+		assert.ErrorContains(t, err, "reading vulnerability database")
+	})
+
+	t.Run("malformed advisory", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "bad.json")
+		writeFile(t, path, `{"id": 42}`)
+
+		_, err := Load(path)
+		assert.ErrorContains(t, err, "parsing "+path)
+	})
+}